@@ -9,7 +9,9 @@ import (
 	"time"
 
 	"time2meet/internal/infrastructure/config"
+	"time2meet/internal/infrastructure/fakepay"
 	"time2meet/internal/infrastructure/persistence/postgres"
+	"time2meet/internal/infrastructure/worker"
 	httpiface "time2meet/internal/presentation/http"
 	"time2meet/pkg/logger"
 
@@ -22,6 +24,14 @@ import (
 // @BasePath /api/v1
 // @schemes http
 // @host localhost:8080
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Access token: "Bearer <token>"
+// @securityDefinitions.apikey DeviceToken
+// @in header
+// @name X-Device-Token
+// @description Scanner device token issued on device registration
 func main() {
 	log := logger.New()
 	defer func() { _ = log.Sync() }()
@@ -39,7 +49,16 @@ func main() {
 	}
 	defer db.Close()
 
-	srv := httpiface.NewServer(cfg.HTTP, db, log)
+	paymentGW := fakepay.New(fakepay.Mode(cfg.Payment.FakeMode), cfg.Payment.WebhookSecret)
+	srv, services := httpiface.NewServer(cfg, db, paymentGW, log)
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go worker.NewHoldSweeper(postgres.NewTicketHoldRepo(db), cfg.Hold.SweepInterval, log).Run(workerCtx)
+	go worker.NewTransferSweeper(postgres.NewTicketTransferRepo(db), cfg.Transfer.SweepInterval, log).Run(workerCtx)
+	go worker.NewWaitlistOfferer(services.Waitlist, cfg.Waitlist.OfferInterval, log).Run(workerCtx)
+	go worker.NewPaymentReconciler(services.Payments, cfg.Payment.ReconcileAfter, cfg.Payment.ReconcileInterval, log).Run(workerCtx)
+	go worker.NewIdempotencyPurger(postgres.NewIdempotencyStore(db), time.Hour, log).Run(workerCtx)
 
 	go func() {
 		log.Info("http server starting", zap.String("addr", cfg.HTTP.Addr))
@@ -54,6 +73,7 @@ func main() {
 	<-stop
 
 	log.Info("shutting down")
+	stopWorkers()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"time"
+
+	"time2meet/internal/infrastructure/config"
+	"time2meet/internal/infrastructure/fakepay"
+	"time2meet/internal/infrastructure/persistence/postgres"
+	httpiface "time2meet/internal/presentation/http"
+	"time2meet/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// reconcile settles payments and refunds stuck in pending by asking the provider for their outcome,
+// once, outside the API's own reconciler (e.g. from cron or after an outage). The fake provider
+// keeps its intents in process memory, so against it only the API's reconciler can settle them.
+func main() {
+	var (
+		olderThan = flag.Duration("older-than", 5*time.Minute, "only payments pending for longer than this")
+		limit     = flag.Int("limit", 100, "max payments per run")
+	)
+	flag.Parse()
+
+	log := logger.New()
+	defer func() { _ = log.Sync() }()
+	cfg, err := config.LoadFromEnv()
+	if err != nil {
+		log.Error("config load failed", zap.Error(err))
+		os.Exit(1)
+	}
+
+	db, err := postgres.NewDB(cfg.Database, log)
+	if err != nil {
+		log.Error("db connect failed", zap.Error(err))
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	gw := fakepay.New(fakepay.Mode(cfg.Payment.FakeMode), cfg.Payment.WebhookSecret)
+	proc := httpiface.NewPaymentProcessor(httpiface.Dependencies{Config: cfg, DB: db, PaymentGateway: gw, Log: log})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	res, err := proc.Reconcile(ctx, *olderThan, *limit)
+	if err != nil {
+		log.Error("reconcile failed", zap.Error(err))
+		os.Exit(1)
+	}
+	log.Info("reconcile done",
+		zap.Int("checked", res.Checked),
+		zap.Int("paid", res.Paid),
+		zap.Int("failed", res.Failed),
+		zap.Int("still_pending", res.StillPending),
+		zap.Int("refunds_checked", res.RefundsChecked),
+		zap.Int("refunds_succeeded", res.RefundsSucceeded),
+		zap.Int("refunds_failed", res.RefundsFailed),
+		zap.Int("refunds_still_pending", res.RefundsStillPending),
+	)
+}
@@ -8,8 +8,12 @@ import (
 	"os"
 	"time"
 
+	"time2meet/internal/application/port/qrtoken"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/internal/infrastructure/auth"
 	"time2meet/internal/infrastructure/config"
 	"time2meet/internal/infrastructure/persistence/postgres"
+	"time2meet/internal/infrastructure/qrsign"
 	"time2meet/pkg/logger"
 
 	"github.com/brianvoe/gofakeit/v6"
@@ -28,6 +32,7 @@ func main() {
 		typesN     = flag.Int("ticket-types", 2000, "ticket types count")
 		ticketsN   = flag.Int("tickets", 5000, "tickets count")
 		regsN      = flag.Int("registrations", 3000, "registrations count")
+		password   = flag.String("password", "time2meet", "password for all seeded users")
 	)
 	flag.Parse()
 
@@ -46,6 +51,8 @@ func main() {
 	}
 	defer db.Close()
 
+	qrSigner := qrsign.NewHMACSigner(cfg.QR.SigningKeys, cfg.QR.ActiveKeyID)
+
 	rnd := rand.New(rand.NewSource(*seed))
 	gofakeit.Seed(*seed)
 
@@ -127,6 +134,12 @@ func main() {
 		roomIDs = append(roomIDs, id)
 	}
 
+	passwordHash, err := auth.NewArgon2Hasher(auth.DefaultArgon2Params).Hash(*password)
+	if err != nil {
+		log.Error("hash seed password failed", zap.Error(err))
+		os.Exit(1)
+	}
+
 	userIDs := make([]string, 0, *usersN)
 	organizerIDs := make([]string, 0, *usersN/5)
 	for i := 0; i < *usersN; i++ {
@@ -142,7 +155,7 @@ func main() {
 			INSERT INTO users (email, password_hash, full_name, phone, role, is_active)
 			VALUES ($1,$2,$3,$4,$5,true)
 			RETURNING id
-		`, email, "seed_hash", gofakeit.Name(), gofakeit.Phone(), role).Scan(&id)
+		`, email, passwordHash, gofakeit.Name(), gofakeit.Phone(), role).Scan(&id)
 		if err != nil {
 			log.Warn("insert user failed", zap.Int("i", i), zap.Error(err))
 			continue
@@ -212,6 +225,7 @@ func main() {
 
 	type typeInfo struct {
 		ID        string
+		EventID   string
 		Remaining int
 	}
 	types := make([]typeInfo, 0, *typesN)
@@ -230,7 +244,7 @@ func main() {
 			log.Warn("insert ticket_type failed", zap.Int("i", i), zap.Error(err))
 			continue
 		}
-		types = append(types, typeInfo{ID: id, Remaining: qty})
+		types = append(types, typeInfo{ID: id, EventID: eid, Remaining: qty})
 	}
 
 	for i := 0; i < *ticketsN; i++ {
@@ -246,12 +260,23 @@ func main() {
 		}
 		tt := &types[idx]
 		buyer := userIDs[rnd.Intn(len(userIDs))]
-		qr := fmt.Sprintf("QR-%d-%d", time.Now().UnixNano(), i)
+		purchased := time.Now().Add(-time.Duration(rnd.Intn(60*24)) * time.Hour).UTC()
+		ticketID := valueobject.NewUUID()
+		eventID, err := valueobject.ParseUUID(tt.EventID)
+		if err != nil {
+			log.Warn("invalid event id", zap.Int("i", i), zap.Error(err))
+			continue
+		}
+		qr, err := qrSigner.Sign(qrtoken.Payload{TicketID: ticketID, EventID: eventID, IssuedAt: purchased})
+		if err != nil {
+			log.Error("sign qr failed", zap.Error(err))
+			os.Exit(1)
+		}
 		amount := fmt.Sprintf("%.2f", float64(rnd.Intn(500000)+10000)/100.0)
-		_, err := db.ExecContext(ctx, `
-			INSERT INTO tickets (ticket_type_id, buyer_id, purchase_date, status, qr_code, amount_paid)
-			VALUES ($1,$2,$3,'paid',$4,$5)
-		`, tt.ID, buyer, time.Now().Add(-time.Duration(rnd.Intn(60*24))*time.Hour).UTC(), qr, amount)
+		_, err = db.ExecContext(ctx, `
+			INSERT INTO tickets (id, ticket_type_id, buyer_id, purchase_date, status, qr_code, amount_paid)
+			VALUES ($1,$2,$3,$4,'paid',$5,$6)
+		`, ticketID.String(), tt.ID, buyer, purchased, qr, amount)
 		if err != nil {
 			log.Warn("insert ticket failed", zap.Int("i", i), zap.Error(err))
 			continue
@@ -0,0 +1,73 @@
+package authz
+
+import (
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+)
+
+// Actor is the authenticated caller of a use case: a user, or a registered scanner device.
+type Actor struct {
+	UserID valueobject.UUID
+	Role   entity.UserRole
+	Device *Device
+}
+
+// Device identifies a scanner device authenticated with its own token. Devices have no
+// user and may only scan tickets of the event they were registered for.
+type Device struct {
+	ID      valueobject.UUID
+	EventID valueobject.UUID
+	Gate    string
+}
+
+func (a Actor) IsDevice() bool { return a.Device != nil }
+
+func (a Actor) IsAnonymous() bool { return a.UserID == valueobject.Nil }
+
+func (a Actor) IsAdmin() bool { return !a.IsAnonymous() && a.Role == entity.UserRoleAdmin }
+
+func (a Actor) HasRole(roles ...entity.UserRole) bool {
+	if a.IsAnonymous() {
+		return false
+	}
+	for _, r := range roles {
+		if a.Role == r {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireRole returns CodeUnauthorized for anonymous callers and CodeForbidden when the role is not allowed.
+func RequireRole(a Actor, roles ...entity.UserRole) error {
+	if a.IsAnonymous() {
+		return apperror.New(apperror.CodeUnauthorized, "authentication required", nil)
+	}
+	if !a.HasRole(roles...) {
+		return apperror.New(apperror.CodeForbidden, "insufficient role", nil)
+	}
+	return nil
+}
+
+// RequireSelfOrAdmin allows access to a user's own resources, or to any resource for admins.
+func RequireSelfOrAdmin(a Actor, userID valueobject.UUID) error {
+	if a.IsAnonymous() {
+		return apperror.New(apperror.CodeUnauthorized, "authentication required", nil)
+	}
+	if a.IsAdmin() || a.UserID == userID {
+		return nil
+	}
+	return apperror.New(apperror.CodeForbidden, "access to another user's data is not allowed", nil)
+}
+
+// RequireOwnerOrAdmin allows access to resources owned by the caller, or to any resource for admins.
+func RequireOwnerOrAdmin(a Actor, ownerID valueobject.UUID) error {
+	if a.IsAnonymous() {
+		return apperror.New(apperror.CodeUnauthorized, "authentication required", nil)
+	}
+	if a.IsAdmin() || a.UserID == ownerID {
+		return nil
+	}
+	return apperror.New(apperror.CodeForbidden, "resource belongs to another organizer", nil)
+}
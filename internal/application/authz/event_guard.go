@@ -0,0 +1,48 @@
+package authz
+
+import (
+	"context"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+)
+
+// EventGuard enforces event ownership for the event itself and for everything scoped to it
+// (ticket types, schedules, attendance reports). Admins bypass ownership checks.
+type EventGuard struct {
+	events repository.EventRepository
+}
+
+func NewEventGuard(events repository.EventRepository) *EventGuard {
+	return &EventGuard{events: events}
+}
+
+// RequireEventManager loads the event and allows its organizer or an admin.
+func (g *EventGuard) RequireEventManager(ctx context.Context, a Actor, eventID valueobject.UUID) (entity.Event, error) {
+	if err := RequireRole(a, entity.UserRoleAdmin, entity.UserRoleOrganizer); err != nil {
+		return entity.Event{}, err
+	}
+	e, err := g.events.GetByID(ctx, eventID)
+	if err != nil {
+		return entity.Event{}, err
+	}
+	if err := RequireOwnerOrAdmin(a, e.OrganizerID); err != nil {
+		return entity.Event{}, err
+	}
+	return e, nil
+}
+
+// RequireScanner allows check-in at an event: devices registered for that event, plus
+// everyone RequireEventManager allows.
+func (g *EventGuard) RequireScanner(ctx context.Context, a Actor, eventID valueobject.UUID) error {
+	if a.IsDevice() {
+		if a.Device.EventID != eventID {
+			return apperror.New(apperror.CodeForbidden, "device is registered for another event", nil)
+		}
+		return nil
+	}
+	_, err := g.RequireEventManager(ctx, a, eventID)
+	return err
+}
@@ -0,0 +1,31 @@
+package authtoken
+
+import (
+	"time"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/valueobject"
+)
+
+type Kind string
+
+const (
+	KindAccess  Kind = "access"
+	KindRefresh Kind = "refresh"
+	// KindClaim is mailed to the owner of a placeholder account (one without a password) and
+	// lets them set its first password.
+	KindClaim Kind = "claim"
+)
+
+type Claims struct {
+	UserID valueobject.UUID
+	Role   entity.UserRole
+	// SessionID is set for refresh tokens and points to the stored auth session.
+	SessionID valueobject.UUID
+	ExpiresAt time.Time
+}
+
+type Issuer interface {
+	Issue(kind Kind, claims Claims) (string, error)
+	Parse(kind Kind, token string) (Claims, error)
+}
@@ -20,8 +20,10 @@ type Result struct {
 }
 
 type ImportUsersItem struct {
-	Email        string `json:"email"`
-	PasswordHash string `json:"password_hash"`
+	Email    string `json:"email"`
+	Password string `json:"password,omitempty"`
+	// PasswordHash is only accepted in migration mode; otherwise it is computed from Password.
+	PasswordHash string `json:"password_hash,omitempty"`
 	FullName     string `json:"full_name"`
 	Phone        string `json:"phone"`
 	Role         string `json:"role"`
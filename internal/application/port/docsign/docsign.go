@@ -0,0 +1,11 @@
+package docsign
+
+// Signer signs documents handed to scanner devices, such as offline check-in manifests.
+// Devices verify them with the public key, which lets them sign nothing themselves.
+type Signer interface {
+	// Sign signs doc with the active key.
+	Sign(doc []byte) (keyID string, signature string, err error)
+	// PublicKey returns the active key id and its base64 public key, given to devices when
+	// they are registered.
+	PublicKey() (keyID string, publicKey string)
+}
@@ -0,0 +1,32 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"time2meet/internal/domain/valueobject"
+)
+
+// Record is what is stored under an idempotency key: the fingerprint of the request that
+// claimed it and, once that request finished, its response.
+type Record struct {
+	Fingerprint string
+	Completed   bool
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+// Store persists idempotency keys. Keys are scoped per user.
+type Store interface {
+	// Claim reserves key for a new request. When the key is already held by an unexpired
+	// record, claimed is false and the existing record is returned. Claims that were never
+	// completed are taken over once lockedUntil has passed.
+	Claim(ctx context.Context, userID valueobject.UUID, key, fingerprint string, lockedUntil, expiresAt time.Time) (existing Record, claimed bool, err error)
+	// Complete stores the response of the request that claimed key.
+	Complete(ctx context.Context, userID valueobject.UUID, key string, rec Record) error
+	// Release forgets an uncompleted claim so the request can be retried.
+	Release(ctx context.Context, userID valueobject.UUID, key string) error
+	// PurgeExpired deletes records whose retention has passed and returns how many were deleted.
+	PurgeExpired(ctx context.Context, now time.Time) (int, error)
+}
@@ -0,0 +1,34 @@
+package notify
+
+import (
+	"context"
+
+	"time2meet/internal/domain/valueobject"
+)
+
+type Kind string
+
+const (
+	// KindWaitlistOffer tells a waitlisted user that tickets are held for them.
+	KindWaitlistOffer Kind = "waitlist_offer"
+	// KindAccountClaim carries the token that lets a user set the first password of an
+	// account created for them, e.g. by comp ticket issuance.
+	KindAccountClaim Kind = "account_claim"
+)
+
+// Message is addressed to a user. Subject and Body are plain text; Data carries the ids and
+// deadlines behind them for channels that render their own templates.
+type Message struct {
+	Kind    Kind
+	UserID  valueobject.UUID
+	Email   valueobject.Email
+	Subject string
+	Body    string
+	Data    map[string]string
+}
+
+// Notifier delivers messages to users. Delivery is best effort: callers send after their
+// transaction has committed and never roll back because a message could not be sent.
+type Notifier interface {
+	Notify(ctx context.Context, m Message) error
+}
@@ -0,0 +1,8 @@
+package passwordhash
+
+type Hasher interface {
+	Hash(password string) (string, error)
+	Verify(hash, password string) (bool, error)
+	// NeedsRehash reports whether hash was produced by an outdated algorithm or parameters.
+	NeedsRehash(hash string) bool
+}
@@ -0,0 +1,82 @@
+package payment
+
+import (
+	"context"
+	"time"
+
+	"time2meet/internal/domain/valueobject"
+)
+
+type IntentStatus string
+
+const (
+	// IntentRequiresCapture means the intent exists but no money has moved yet.
+	IntentRequiresCapture IntentStatus = "requires_capture"
+	IntentSucceeded       IntentStatus = "succeeded"
+	// IntentFailed is a definitive decline.
+	IntentFailed IntentStatus = "failed"
+)
+
+type Intent struct {
+	ID       string
+	Status   IntentStatus
+	Amount   valueobject.Money
+	Currency valueobject.Currency
+	// FailureReason is the provider's decline reason for failed intents.
+	FailureReason string
+}
+
+type CreateIntentInput struct {
+	OrderID  valueobject.UUID
+	Amount   valueobject.Money
+	Currency valueobject.Currency
+	// IdempotencyKey lets the provider deduplicate retried creations.
+	IdempotencyKey string
+}
+
+type RefundStatus string
+
+const (
+	RefundSucceeded RefundStatus = "succeeded"
+	RefundFailed    RefundStatus = "failed"
+)
+
+type Refund struct {
+	ID       string
+	IntentID string
+	Amount   valueobject.Money
+	Status   RefundStatus
+}
+
+type EventType string
+
+const (
+	EventIntentSucceeded EventType = "intent.succeeded"
+	EventIntentFailed    EventType = "intent.failed"
+	EventRefundSucceeded EventType = "refund.succeeded"
+)
+
+// Event is a verified webhook notification.
+type Event struct {
+	ID         string
+	Type       EventType
+	IntentID   string
+	RefundID   string
+	Reason     string
+	OccurredAt time.Time
+}
+
+// Gateway is a payment provider. A returned error means the outcome is unknown (network
+// failure, timeout): the caller must not assume the operation failed and should settle it
+// later from a webhook or by reconciliation. Declines are reported in the result instead.
+type Gateway interface {
+	// Name identifies the provider in stored payments.
+	Name() string
+	// CreateIntent is idempotent on IdempotencyKey: repeating it returns the same intent.
+	CreateIntent(ctx context.Context, in CreateIntentInput) (Intent, error)
+	GetIntent(ctx context.Context, intentID string) (Intent, error)
+	Capture(ctx context.Context, intentID string) (Intent, error)
+	Refund(ctx context.Context, intentID string, amount valueobject.Money, idempotencyKey string) (Refund, error)
+	// VerifyWebhook authenticates a webhook body against its signature header and decodes it.
+	VerifyWebhook(payload []byte, signature string) (Event, error)
+}
@@ -0,0 +1,22 @@
+package qrtoken
+
+import (
+	"time"
+
+	"time2meet/internal/domain/valueobject"
+)
+
+// Payload is what a ticket QR code carries. It is signed by the server, so scanners can
+// trust it without a round trip, but the database remains the source of truth for state.
+type Payload struct {
+	TicketID valueobject.UUID
+	EventID  valueobject.UUID
+	IssuedAt time.Time
+}
+
+type Signer interface {
+	// Sign encodes the payload with the active key.
+	Sign(p Payload) (string, error)
+	// Verify checks the signature against every known key and decodes the payload.
+	Verify(token string) (Payload, error)
+}
@@ -4,15 +4,208 @@ import (
 	"context"
 	"time"
 
+	"time2meet/internal/domain/entity"
 	"time2meet/internal/domain/valueobject"
 
 	"github.com/jmoiron/sqlx"
 )
 
+// LockedTicketType is the ticket type row as seen under FOR UPDATE, together with the status
+// of its event (share-locked, so the event cannot be cancelled mid-purchase). Prices must be
+// taken from here, never from the client.
+type LockedTicketType struct {
+	ID            valueobject.UUID
+	EventID       valueobject.UUID
+	Price         valueobject.Money
+	PriceTiers    entity.PriceTiers
+	Currency      valueobject.Currency
+	QuantityTotal int
+	QuantitySold  int
+	QuantityHeld  int
+	SaleStart     *time.Time
+	SaleEnd       *time.Time
+	IsActive      bool
+	MaxPerOrder   *int
+	MaxPerUser    *int
+	// WaitlistWaiting is how many waitlist entries of the type are waiting for an offer, and
+	// WaitlistMinQuantity the smallest quantity one of them asks for (0 when none is waiting).
+	// While a waiting entry fits the available tickets, they go to the waitlist rather than to
+	// new buyers.
+	WaitlistWaiting     int
+	WaitlistMinQuantity int
+	EventStatus         valueobject.EventStatus
+	// EventStartsAt is the first non-cancelled session of the event, nil when none is scheduled.
+	EventStartsAt *time.Time
+
+	RefundPolicy       valueobject.RefundPolicy
+	RefundDeadlineDays int
+	RefundPercent      int
+}
+
+// LockedTicket is a ticket row as seen under FOR UPDATE, with the event it belongs to.
+type LockedTicket struct {
+	ID           valueobject.UUID
+	TicketTypeID valueobject.UUID
+	EventID      valueobject.UUID
+	BuyerID      valueobject.UUID
+	HolderID     valueobject.UUID
+	OrderID      *valueobject.UUID
+	Status       valueobject.TicketStatus
+	QRCode       string
+	AmountPaid   valueobject.Money
+	UsedAt       *time.Time
+	UsedBy       *valueobject.UUID
+	// UsedByDeviceID is the scanner device of the first admission; UsedBy is then nil.
+	UsedByDeviceID *valueobject.UUID
+	EntryCount     int
+	ReentryLimit   int
+	// RefundPending reports a refund of the ticket awaiting the provider. The ticket stays
+	// paid meanwhile but may not be scanned or transferred.
+	RefundPending bool
+}
+
+// Available is how many tickets can still be sold or held. quantity_held covers both active
+// holds and tickets awaiting payment.
+func (t LockedTicketType) Available() int { return t.QuantityTotal - t.QuantitySold - t.QuantityHeld }
+
+// Taken is how many tickets are sold or reserved. Price tier caps count against it, so buyers
+// still paying for the last tickets of a tier do not leave them to be sold again at its price.
+func (t LockedTicketType) Taken() int { return t.QuantitySold + t.QuantityHeld }
+
+// MaxEntries is how many times the ticket may be admitted in total.
+func (t LockedTicket) MaxEntries() int { return 1 + t.ReentryLimit }
+
 type Queries interface {
-	LockTicketTypeForUpdate(ctx context.Context, tx *sqlx.Tx, ticketTypeID valueobject.UUID) (qtyTotal int, qtySold int, err error)
+	LockTicketTypeForUpdate(ctx context.Context, tx *sqlx.Tx, ticketTypeID valueobject.UUID) (LockedTicketType, error)
+
+	// InsertHold reserves inventory; the holds trigger adds the quantity to quantity_held.
+	InsertHold(ctx context.Context, tx *sqlx.Tx, h entity.TicketHold) error
+
+	LockHoldForUpdate(ctx context.Context, tx *sqlx.Tx, holdID valueobject.UUID) (entity.TicketHold, error)
+
+	// FinishHold moves an active hold to a final status, returning its quantity to the pool.
+	// orderID is set for converted holds. It returns false when the hold is no longer active.
+	FinishHold(ctx context.Context, tx *sqlx.Tx, holdID valueobject.UUID, status valueobject.HoldStatus, orderID *valueobject.UUID) (bool, error)
+
+	// InsertOrder writes an order with its items. Tickets are inserted separately and point to it.
+	InsertOrder(ctx context.Context, tx *sqlx.Tx, o entity.Order) error
+
+	// LockOrderForUpdate returns the order row without items.
+	LockOrderForUpdate(ctx context.Context, tx *sqlx.Tx, orderID valueobject.UUID) (entity.Order, error)
+
+	SetOrderStatus(ctx context.Context, tx *sqlx.Tx, orderID valueobject.UUID, status valueobject.OrderStatus) error
+
+	// SetOrderTicketsStatus moves the order's tickets in status from to status to and returns
+	// how many changed. The order's ticket types are locked in id order first, matching the
+	// lock order of placing an order.
+	SetOrderTicketsStatus(ctx context.Context, tx *sqlx.Tx, orderID valueobject.UUID, from, to valueobject.TicketStatus) (int, error)
+
+	// InsertTicket takes a caller-generated id so the signed QR payload can reference it.
+	InsertTicket(ctx context.Context, tx *sqlx.Tx, t entity.Ticket) error
+
+	InsertPayment(ctx context.Context, tx *sqlx.Tx, p entity.Payment) error
+
+	LockPaymentForUpdate(ctx context.Context, tx *sqlx.Tx, paymentID valueobject.UUID) (entity.Payment, error)
+
+	// TransitionPayment moves a payment from status from to status to. It returns false when
+	// the payment is no longer in status from.
+	TransitionPayment(ctx context.Context, tx *sqlx.Tx, paymentID valueobject.UUID, from, to valueobject.PaymentStatus, failureReason string) (bool, error)
+
+	// RecordPaymentEvent stores a webhook event id. It returns false when the event was
+	// already recorded, i.e. the webhook is a redelivery.
+	RecordPaymentEvent(ctx context.Context, tx *sqlx.Tx, provider, eventID, eventType, intentID string) (bool, error)
+
+	// InsertRefund writes a refund ledger record. A second open refund for the same ticket is
+	// rejected with CodeConflict.
+	InsertRefund(ctx context.Context, tx *sqlx.Tx, r entity.Refund) error
+
+	// FinishRefund moves a pending refund to a final status. It returns false when the refund
+	// is no longer pending.
+	FinishRefund(ctx context.Context, tx *sqlx.Tx, refundID valueobject.UUID, status valueobject.RefundStatus, providerRefundID, failureReason string) (bool, error)
+
+	// ReleaseRefundedTicket moves a paid ticket to refunded once its refund has succeeded,
+	// locking its ticket type first like other writers do. It returns false when the ticket is
+	// no longer paid, e.g. it was scanned while the refund was pending.
+	ReleaseRefundedTicket(ctx context.Context, tx *sqlx.Tx, ticketID valueobject.UUID) (bool, error)
+
+	// LockPaymentRefunds returns the refund ledger records of a payment, oldest first.
+	LockPaymentRefunds(ctx context.Context, tx *sqlx.Tx, paymentID valueobject.UUID) ([]entity.Refund, error)
+
+	// SetTicketStatus moves a ticket from status from to status to. It returns false when the
+	// ticket is no longer in status from.
+	SetTicketStatus(ctx context.Context, tx *sqlx.Tx, ticketID valueobject.UUID, from, to valueobject.TicketStatus) (bool, error)
+
+	InsertTicketStatusChange(ctx context.Context, tx *sqlx.Tx, c entity.TicketStatusChange) error
+
+	LockTicketForUpdate(ctx context.Context, tx *sqlx.Tx, ticketID valueobject.UUID) (LockedTicket, error)
+
+	// MarkTicketUsed admits the ticket once more. The first admission records used_at and
+	// the scanner, a staff member or a device; later ones only bump entry_count. It returns
+	// false when the ticket is not admissible (not paid/used, or maxEntries already reached).
+	MarkTicketUsed(ctx context.Context, tx *sqlx.Tx, ticketID valueobject.UUID, usedAt time.Time, staffID, deviceID *valueobject.UUID, maxEntries int) (bool, error)
+
+	// InsertCheckin records a scan attempt in the same transaction as the ticket update.
+	InsertCheckin(ctx context.Context, tx *sqlx.Tx, c entity.Checkin) error
+
+	// InsertTransfer offers a ticket to another user. A second pending transfer of the same
+	// ticket is rejected with CodeConflict.
+	InsertTransfer(ctx context.Context, tx *sqlx.Tx, t entity.TicketTransfer) error
+
+	LockTransferForUpdate(ctx context.Context, tx *sqlx.Tx, transferID valueobject.UUID) (entity.TicketTransfer, error)
+
+	// FinishTransfer moves a pending transfer to a final status; toUserID and acceptedAt are
+	// set for accepted transfers. It returns false when the transfer is no longer pending.
+	FinishTransfer(ctx context.Context, tx *sqlx.Tx, transferID valueobject.UUID, status valueobject.TransferStatus, toUserID *valueobject.UUID, acceptedAt *time.Time) (bool, error)
+
+	// SetTicketHolder hands the ticket to a new holder together with its re-issued QR code.
+	SetTicketHolder(ctx context.Context, tx *sqlx.Tx, ticketID, holderID valueobject.UUID, qrCode string) error
+
+	// LockPromoCodeByCode finds a code of one of the events regardless of case; codes are only
+	// unique within an event. Callers lock it after the ticket types of the order. An unknown
+	// code, or one that several of the events have, is rejected with CodePromoInvalid.
+	LockPromoCodeByCode(ctx context.Context, tx *sqlx.Tx, code string, eventIDs []valueobject.UUID) (entity.PromoCode, error)
+
+	// CountPromoRedemptions returns how many orders of the user hold an applied redemption of
+	// the code.
+	CountPromoRedemptions(ctx context.Context, tx *sqlx.Tx, promoCodeID, userID valueobject.UUID) (int, error)
+
+	// InsertPromoRedemption records the redemption and counts it against the code.
+	InsertPromoRedemption(ctx context.Context, tx *sqlx.Tx, r entity.PromoRedemption) error
+
+	// ReleasePromoRedemption gives the order's applied redemption, if any, back to its code.
+	ReleasePromoRedemption(ctx context.Context, tx *sqlx.Tx, orderID valueobject.UUID) error
+
+	// CountBuyerTickets returns how many tickets of the type the user has bought or is paying
+	// for, plus what their active holds of the type reserve. Comp tickets are not counted.
+	// Callers lock the ticket type first.
+	CountBuyerTickets(ctx context.Context, tx *sqlx.Tx, buyerID, ticketTypeID valueobject.UUID) (int, error)
+
+	// CountIPOrders returns how many orders were placed from ip since the given time and by how
+	// many different buyers.
+	CountIPOrders(ctx context.Context, tx *sqlx.Tx, ip string, since time.Time) (orders, buyers int, err error)
+
+	InsertPurchaseFlag(ctx context.Context, tx *sqlx.Tx, f entity.PurchaseFlag) error
+
+	// InsertWaitlistEntry queues a user for a ticket type. A second open entry of the same user
+	// and ticket type is rejected with CodeConflict.
+	InsertWaitlistEntry(ctx context.Context, tx *sqlx.Tx, e entity.WaitlistEntry) error
+
+	LockWaitlistEntryForUpdate(ctx context.Context, tx *sqlx.Tx, entryID valueobject.UUID) (entity.WaitlistEntry, error)
+
+	// LockNextWaitlistEntry returns the first waiting entry of the ticket type that asks for at
+	// most maxQuantity tickets, skipping entries locked by another transaction (e.g. being left).
+	// It returns false when there is none. Callers lock the ticket type first.
+	LockNextWaitlistEntry(ctx context.Context, tx *sqlx.Tx, ticketTypeID valueobject.UUID, maxQuantity int) (entity.WaitlistEntry, bool, error)
+
+	// OfferWaitlistEntry records the hold offered to a waiting entry. It returns false when the
+	// entry is no longer waiting.
+	OfferWaitlistEntry(ctx context.Context, tx *sqlx.Tx, entryID, holdID valueobject.UUID, offeredAt, expiresAt time.Time) (bool, error)
 
-	InsertPaidTicket(ctx context.Context, tx *sqlx.Tx, ticketTypeID, buyerID valueobject.UUID, purchaseDate time.Time, qrCode string, amountPaid string) (valueobject.UUID, error)
+	// SetWaitlistEntryStatus moves an entry from status from to status to. It returns false when
+	// the entry is no longer in status from.
+	SetWaitlistEntryStatus(ctx context.Context, tx *sqlx.Tx, entryID valueobject.UUID, from, to valueobject.WaitlistStatus) (bool, error)
 
-	MarkTicketUsed(ctx context.Context, tx *sqlx.Tx, ticketID valueobject.UUID, usedAt time.Time) (bool, error)
+	// EnsureUserByEmail returns the id of the user with the email, creating an attendee
+	// account without a password when there is none. created reports whether it did.
+	EnsureUserByEmail(ctx context.Context, tx *sqlx.Tx, email valueobject.Email, fullName string) (id valueobject.UUID, created bool, err error)
 }
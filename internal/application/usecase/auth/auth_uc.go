@@ -0,0 +1,293 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"time2meet/internal/application/port/authtoken"
+	"time2meet/internal/application/port/notify"
+	"time2meet/internal/application/port/passwordhash"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+)
+
+type UseCase struct {
+	users      repository.UserRepository
+	sessions   repository.AuthSessionRepository
+	tokens     authtoken.Issuer
+	hasher     passwordhash.Hasher
+	notifier   notify.Notifier
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	claimTTL   time.Duration
+
+	dummyOnce sync.Once
+	dummy     string
+}
+
+func New(
+	users repository.UserRepository,
+	sessions repository.AuthSessionRepository,
+	tokens authtoken.Issuer,
+	hasher passwordhash.Hasher,
+	notifier notify.Notifier,
+	accessTTL, refreshTTL, claimTTL time.Duration,
+) *UseCase {
+	return &UseCase{
+		users:      users,
+		sessions:   sessions,
+		tokens:     tokens,
+		hasher:     hasher,
+		notifier:   notifier,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		claimTTL:   claimTTL,
+	}
+}
+
+type TokenPair struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+type LoginInput struct {
+	Email    string
+	Password string
+	IP       string
+}
+
+func (uc *UseCase) Login(ctx context.Context, in LoginInput) (TokenPair, error) {
+	email, err := valueobject.ParseEmail(in.Email)
+	if err != nil {
+		return TokenPair{}, apperror.New(apperror.CodeValidation, "invalid email", err)
+	}
+	if in.Password == "" {
+		return TokenPair{}, apperror.New(apperror.CodeValidation, "password is required", nil)
+	}
+	u, err := uc.users.GetByEmail(ctx, email.String())
+	if err != nil {
+		var ae *apperror.AppError
+		if errors.As(err, &ae) && ae.Code == apperror.CodeNotFound {
+			_, _ = uc.hasher.Verify(uc.dummyHash(), in.Password)
+			return TokenPair{}, errInvalidCredentials()
+		}
+		return TokenPair{}, err
+	}
+	if u.PasswordHash == "" {
+		// An unclaimed placeholder account has no password to check.
+		_, _ = uc.hasher.Verify(uc.dummyHash(), in.Password)
+		return TokenPair{}, errInvalidCredentials()
+	}
+	ok, err := uc.hasher.Verify(u.PasswordHash, in.Password)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if !ok {
+		return TokenPair{}, errInvalidCredentials()
+	}
+	if !u.IsActive {
+		return TokenPair{}, apperror.New(apperror.CodeForbidden, "user is inactive", nil)
+	}
+	if uc.hasher.NeedsRehash(u.PasswordHash) {
+		// Best effort: a failed upgrade must not block the login, it is retried next time.
+		if hash, err := uc.hasher.Hash(in.Password); err == nil {
+			_ = uc.users.UpdatePasswordHash(ctx, u.ID, hash)
+		}
+	}
+	return uc.issue(ctx, u, in.IP)
+}
+
+// dummyHash is verified in place of a missing password hash, so a login for an unknown email
+// takes as long to fail as a wrong password and does not reveal which accounts exist.
+func (uc *UseCase) dummyHash() string {
+	uc.dummyOnce.Do(func() {
+		uc.dummy, _ = uc.hasher.Hash("time2meet dummy password")
+	})
+	return uc.dummy
+}
+
+type RefreshInput struct {
+	RefreshToken string
+	IP           string
+}
+
+// Refresh rotates the refresh token: the presented session is revoked and a new pair is issued.
+func (uc *UseCase) Refresh(ctx context.Context, in RefreshInput) (TokenPair, error) {
+	claims, err := uc.tokens.Parse(authtoken.KindRefresh, in.RefreshToken)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if err := uc.checkSession(ctx, claims); err != nil {
+		return TokenPair{}, err
+	}
+	revoked, err := uc.sessions.Revoke(ctx, claims.SessionID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if !revoked {
+		// Lost a race with a concurrent refresh/logout of the same token.
+		return TokenPair{}, apperror.New(apperror.CodeUnauthorized, "session revoked", nil)
+	}
+	u, err := uc.users.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return TokenPair{}, apperror.New(apperror.CodeUnauthorized, "user not found", err)
+	}
+	if !u.IsActive {
+		return TokenPair{}, apperror.New(apperror.CodeForbidden, "user is inactive", nil)
+	}
+	return uc.issue(ctx, u, in.IP)
+}
+
+func (uc *UseCase) Logout(ctx context.Context, refreshToken string) error {
+	claims, err := uc.tokens.Parse(authtoken.KindRefresh, refreshToken)
+	if err != nil {
+		return err
+	}
+	if err := uc.checkSession(ctx, claims); err != nil {
+		return err
+	}
+	_, err = uc.sessions.Revoke(ctx, claims.SessionID)
+	return err
+}
+
+// RequestClaim mails a claim token to the owner of a placeholder account: one created without a
+// password, e.g. for comp tickets. Whether the email has such an account is not revealed, so
+// unknown emails and accounts that already have a password succeed without sending anything.
+func (uc *UseCase) RequestClaim(ctx context.Context, rawEmail string) error {
+	email, err := valueobject.ParseEmail(rawEmail)
+	if err != nil {
+		return apperror.New(apperror.CodeValidation, "invalid email", err)
+	}
+	u, err := uc.users.GetByEmail(ctx, email.String())
+	if err != nil {
+		var ae *apperror.AppError
+		if errors.As(err, &ae) && ae.Code == apperror.CodeNotFound {
+			return nil
+		}
+		return err
+	}
+	if u.PasswordHash != "" || !u.IsActive {
+		return nil
+	}
+	expiresAt := time.Now().UTC().Add(uc.claimTTL)
+	token, err := uc.tokens.Issue(authtoken.KindClaim, authtoken.Claims{UserID: u.ID, Role: u.Role, ExpiresAt: expiresAt})
+	if err != nil {
+		return err
+	}
+	deadline := expiresAt.Format(time.RFC3339)
+	return uc.notifier.Notify(ctx, notify.Message{
+		Kind:    notify.KindAccountClaim,
+		UserID:  u.ID,
+		Email:   u.Email,
+		Subject: "Set a password for your account",
+		Body:    fmt.Sprintf("An account with tickets was created for %s. Use the claim token before %s to set its password.", u.Email, deadline),
+		Data: map[string]string{
+			"claim_token": token,
+			"expires_at":  deadline,
+		},
+	})
+}
+
+type ClaimInput struct {
+	Token    string
+	Password string
+	IP       string
+}
+
+// Claim sets the first password of a placeholder account with a token from RequestClaim and
+// signs the user in. Holding the token proves the email is theirs; it stops working once the
+// account has a password.
+func (uc *UseCase) Claim(ctx context.Context, in ClaimInput) (TokenPair, error) {
+	if err := valueobject.ValidatePassword(in.Password); err != nil {
+		return TokenPair{}, apperror.New(apperror.CodeValidation, err.Error(), err)
+	}
+	claims, err := uc.tokens.Parse(authtoken.KindClaim, in.Token)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	hash, err := uc.hasher.Hash(in.Password)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	claimed, err := uc.users.ClaimPasswordHash(ctx, claims.UserID, hash)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if !claimed {
+		return TokenPair{}, apperror.New(apperror.CodeConflict, "account has already been claimed", nil)
+	}
+	u, err := uc.users.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return uc.issue(ctx, u, in.IP)
+}
+
+func (uc *UseCase) checkSession(ctx context.Context, claims authtoken.Claims) error {
+	if claims.SessionID == valueobject.Nil {
+		return apperror.New(apperror.CodeUnauthorized, "invalid refresh token", nil)
+	}
+	s, err := uc.sessions.GetByID(ctx, claims.SessionID)
+	if err != nil {
+		var ae *apperror.AppError
+		if errors.As(err, &ae) && ae.Code == apperror.CodeNotFound {
+			return apperror.New(apperror.CodeUnauthorized, "session not found", err)
+		}
+		return err
+	}
+	if s.UserID != claims.UserID {
+		return apperror.New(apperror.CodeUnauthorized, "invalid refresh token", nil)
+	}
+	if s.RevokedAt != nil {
+		return apperror.New(apperror.CodeUnauthorized, "session revoked", nil)
+	}
+	if time.Now().After(s.ExpiresAt) {
+		return apperror.New(apperror.CodeUnauthorized, "session expired", nil)
+	}
+	return nil
+}
+
+func (uc *UseCase) issue(ctx context.Context, u entity.User, ip string) (TokenPair, error) {
+	now := time.Now().UTC()
+	out := TokenPair{
+		AccessExpiresAt:  now.Add(uc.accessTTL),
+		RefreshExpiresAt: now.Add(uc.refreshTTL),
+	}
+	sid, err := uc.sessions.Create(ctx, entity.AuthSession{
+		UserID:    u.ID,
+		ExpiresAt: out.RefreshExpiresAt,
+		IP:        ip,
+	})
+	if err != nil {
+		return TokenPair{}, err
+	}
+	out.AccessToken, err = uc.tokens.Issue(authtoken.KindAccess, authtoken.Claims{
+		UserID:    u.ID,
+		Role:      u.Role,
+		ExpiresAt: out.AccessExpiresAt,
+	})
+	if err != nil {
+		return TokenPair{}, err
+	}
+	out.RefreshToken, err = uc.tokens.Issue(authtoken.KindRefresh, authtoken.Claims{
+		UserID:    u.ID,
+		Role:      u.Role,
+		SessionID: sid,
+		ExpiresAt: out.RefreshExpiresAt,
+	})
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return out, nil
+}
+
+func errInvalidCredentials() error {
+	return apperror.New(apperror.CodeUnauthorized, "invalid email or password", nil)
+}
@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"time2meet/internal/application/port/notify"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	authinfra "time2meet/internal/infrastructure/auth"
+	"time2meet/pkg/apperror"
+)
+
+type fakeUsers struct {
+	repository.UserRepository
+	byID map[valueobject.UUID]entity.User
+}
+
+func (f fakeUsers) GetByID(_ context.Context, id valueobject.UUID) (entity.User, error) {
+	u, ok := f.byID[id]
+	if !ok {
+		return entity.User{}, apperror.New(apperror.CodeNotFound, "user not found", nil)
+	}
+	return u, nil
+}
+
+func (f fakeUsers) GetByEmail(_ context.Context, email string) (entity.User, error) {
+	for _, u := range f.byID {
+		if u.Email.String() == email {
+			return u, nil
+		}
+	}
+	return entity.User{}, apperror.New(apperror.CodeNotFound, "user not found", nil)
+}
+
+func (f fakeUsers) ClaimPasswordHash(_ context.Context, id valueobject.UUID, hash string) (bool, error) {
+	u, ok := f.byID[id]
+	if !ok || u.PasswordHash != "" || !u.IsActive {
+		return false, nil
+	}
+	u.PasswordHash = hash
+	f.byID[id] = u
+	return true, nil
+}
+
+type fakeSessions struct {
+	repository.AuthSessionRepository
+}
+
+func (fakeSessions) Create(context.Context, entity.AuthSession) (valueobject.UUID, error) {
+	return valueobject.NewUUID(), nil
+}
+
+type sentMessages []notify.Message
+
+func (s *sentMessages) Notify(_ context.Context, m notify.Message) error {
+	*s = append(*s, m)
+	return nil
+}
+
+func TestClaimPlaceholderAccount(t *testing.T) {
+	ctx := context.Background()
+	hasher := authinfra.NewArgon2Hasher(authinfra.Argon2Params{Memory: 1024, Time: 1, Threads: 1, SaltLen: 16, KeyLen: 32})
+	placeholder := entity.User{ID: valueobject.NewUUID(), Email: "guest@example.com", Role: entity.UserRoleAttendee, IsActive: true}
+	member := entity.User{ID: valueobject.NewUUID(), Email: "member@example.com", PasswordHash: "set", Role: entity.UserRoleAttendee, IsActive: true}
+	users := fakeUsers{byID: map[valueobject.UUID]entity.User{placeholder.ID: placeholder, member.ID: member}}
+	var sent sentMessages
+	uc := New(users, fakeSessions{}, authinfra.NewJWTIssuer("secret"), hasher, &sent, time.Minute, time.Hour, time.Hour)
+
+	for _, email := range []string{"member@example.com", "nobody@example.com"} {
+		if err := uc.RequestClaim(ctx, email); err != nil {
+			t.Fatalf("RequestClaim(%s): %v", email, err)
+		}
+	}
+	if len(sent) != 0 {
+		t.Fatalf("claim tokens sent for accounts that are not placeholders: %v", sent)
+	}
+
+	if err := uc.RequestClaim(ctx, "guest@example.com"); err != nil {
+		t.Fatalf("RequestClaim: %v", err)
+	}
+	if len(sent) != 1 || sent[0].Kind != notify.KindAccountClaim || sent[0].UserID != placeholder.ID {
+		t.Fatalf("sent %v, want one claim message to the placeholder", sent)
+	}
+	token := sent[0].Data["claim_token"]
+
+	if _, err := uc.Claim(ctx, ClaimInput{Token: token, Password: "correct horse 42"}); err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	if _, err := uc.Login(ctx, LoginInput{Email: "guest@example.com", Password: "correct horse 42"}); err != nil {
+		t.Fatalf("Login after claim: %v", err)
+	}
+
+	_, err := uc.Claim(ctx, ClaimInput{Token: token, Password: "another password 7"})
+	var ae *apperror.AppError
+	if !errors.As(err, &ae) || ae.Code != apperror.CodeConflict {
+		t.Fatalf("second claim with the same token: err = %v, want conflict", err)
+	}
+}
+
+// countingHasher counts password checks.
+type countingHasher struct {
+	*authinfra.Argon2Hasher
+	verified int
+}
+
+func (h *countingHasher) Verify(hash, password string) (bool, error) {
+	h.verified++
+	return h.Argon2Hasher.Verify(hash, password)
+}
+
+func TestLoginChecksAPasswordForUnknownEmails(t *testing.T) {
+	ctx := context.Background()
+	hasher := &countingHasher{Argon2Hasher: authinfra.NewArgon2Hasher(authinfra.Argon2Params{Memory: 1024, Time: 1, Threads: 1, SaltLen: 16, KeyLen: 32})}
+	placeholder := entity.User{ID: valueobject.NewUUID(), Email: "guest@example.com", Role: entity.UserRoleAttendee, IsActive: true}
+	users := fakeUsers{byID: map[valueobject.UUID]entity.User{placeholder.ID: placeholder}}
+	uc := New(users, fakeSessions{}, authinfra.NewJWTIssuer("secret"), hasher, &sentMessages{}, time.Minute, time.Hour, time.Hour)
+
+	for _, email := range []string{"nobody@example.com", "guest@example.com"} {
+		hasher.verified = 0
+		_, err := uc.Login(ctx, LoginInput{Email: email, Password: "correct horse 42"})
+		var ae *apperror.AppError
+		if !errors.As(err, &ae) || ae.Code != apperror.CodeUnauthorized {
+			t.Fatalf("Login(%s): err = %v, want unauthorized", email, err)
+		}
+		if hasher.verified != 1 {
+			t.Errorf("Login(%s) checked %d passwords, want 1", email, hasher.verified)
+		}
+	}
+}
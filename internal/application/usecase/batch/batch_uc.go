@@ -2,36 +2,51 @@ package batch
 
 import (
 	"context"
+	"fmt"
 
+	"time2meet/internal/application/authz"
 	"time2meet/internal/application/port/auditctx"
 	"time2meet/internal/application/port/batchimport"
+	"time2meet/internal/application/port/passwordhash"
 	"time2meet/internal/application/tx"
+	"time2meet/internal/domain/entity"
 	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
 
 	"github.com/jmoiron/sqlx"
 )
 
 type UseCase struct {
-	tx    tx.Manager
-	audit auditctx.Setter
-	imp   batchimport.Importer
+	tx     tx.Manager
+	audit  auditctx.Setter
+	imp    batchimport.Importer
+	hasher passwordhash.Hasher
 }
 
-func New(txm tx.Manager, audit auditctx.Setter, imp batchimport.Importer) *UseCase {
-	return &UseCase{tx: txm, audit: audit, imp: imp}
+func New(txm tx.Manager, audit auditctx.Setter, imp batchimport.Importer, hasher passwordhash.Hasher) *UseCase {
+	return &UseCase{tx: txm, audit: audit, imp: imp, hasher: hasher}
 }
 
 type ImportUsersInput struct {
-	UserID          valueobject.UUID
+	Actor           authz.Actor
 	IP              string
 	ContinueOnError bool
-	Items           []batchimport.ImportUsersItem
+	// MigrationMode stores items' password_hash verbatim (for moving users from another system).
+	MigrationMode bool
+	Items         []batchimport.ImportUsersItem
 }
 
 func (uc *UseCase) ImportUsers(ctx context.Context, in ImportUsersInput) (batchimport.Result, error) {
+	if err := authz.RequireRole(in.Actor, entity.UserRoleAdmin); err != nil {
+		return batchimport.Result{}, err
+	}
+	if err := uc.preparePasswords(in.Items, in.MigrationMode); err != nil {
+		return batchimport.Result{}, err
+	}
+
 	var res batchimport.Result
 	err := uc.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
-		if err := uc.audit.Set(ctx, txx, in.UserID, in.IP); err != nil {
+		if err := uc.audit.Set(ctx, txx, in.Actor.UserID, in.IP); err != nil {
 			return err
 		}
 		out, err := uc.imp.ImportUsers(ctx, txx, in.Items, in.ContinueOnError)
@@ -41,17 +56,47 @@ func (uc *UseCase) ImportUsers(ctx context.Context, in ImportUsersInput) (batchi
 	return res, err
 }
 
+// preparePasswords validates every item up front and replaces plaintext passwords with hashes in place,
+// so the importer only ever sees password_hash.
+func (uc *UseCase) preparePasswords(items []batchimport.ImportUsersItem, migration bool) error {
+	for i := range items {
+		it := &items[i]
+		if migration {
+			if it.PasswordHash == "" || it.Password != "" {
+				return apperror.New(apperror.CodeValidation, fmt.Sprintf("items[%d]: migration mode requires password_hash only", i), nil)
+			}
+			continue
+		}
+		if it.PasswordHash != "" {
+			return apperror.New(apperror.CodeValidation, fmt.Sprintf("items[%d]: password_hash is only accepted in migration mode", i), nil)
+		}
+		if err := valueobject.ValidatePassword(it.Password); err != nil {
+			return apperror.New(apperror.CodeValidation, fmt.Sprintf("items[%d]: %s", i, err), err)
+		}
+		hash, err := uc.hasher.Hash(it.Password)
+		if err != nil {
+			return err
+		}
+		it.PasswordHash = hash
+		it.Password = ""
+	}
+	return nil
+}
+
 type ImportEventsInput struct {
-	UserID          valueobject.UUID
+	Actor           authz.Actor
 	IP              string
 	ContinueOnError bool
 	Items           []batchimport.ImportEventsItem
 }
 
 func (uc *UseCase) ImportEvents(ctx context.Context, in ImportEventsInput) (batchimport.Result, error) {
+	if err := authz.RequireRole(in.Actor, entity.UserRoleAdmin); err != nil {
+		return batchimport.Result{}, err
+	}
 	var res batchimport.Result
 	err := uc.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
-		if err := uc.audit.Set(ctx, txx, in.UserID, in.IP); err != nil {
+		if err := uc.audit.Set(ctx, txx, in.Actor.UserID, in.IP); err != nil {
 			return err
 		}
 		out, err := uc.imp.ImportEvents(ctx, txx, in.Items, in.ContinueOnError)
@@ -62,16 +107,19 @@ func (uc *UseCase) ImportEvents(ctx context.Context, in ImportEventsInput) (batc
 }
 
 type ImportTicketsInput struct {
-	UserID          valueobject.UUID
+	Actor           authz.Actor
 	IP              string
 	ContinueOnError bool
 	Items           []batchimport.ImportTicketsItem
 }
 
 func (uc *UseCase) ImportTickets(ctx context.Context, in ImportTicketsInput) (batchimport.Result, error) {
+	if err := authz.RequireRole(in.Actor, entity.UserRoleAdmin); err != nil {
+		return batchimport.Result{}, err
+	}
 	var res batchimport.Result
 	err := uc.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
-		if err := uc.audit.Set(ctx, txx, in.UserID, in.IP); err != nil {
+		if err := uc.audit.Set(ctx, txx, in.Actor.UserID, in.IP); err != nil {
 			return err
 		}
 		out, err := uc.imp.ImportTickets(ctx, txx, in.Items, in.ContinueOnError)
@@ -0,0 +1,173 @@
+package checkin
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"time2meet/internal/application/authz"
+	"time2meet/internal/application/port/docsign"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+)
+
+// UseCase manages scanner devices and exposes the check-in log.
+type UseCase struct {
+	devices     repository.CheckinDeviceRepository
+	checkins    repository.CheckinRepository
+	tickets     repository.TicketRepository
+	ticketTypes repository.TicketTypeRepository
+	manifests   docsign.Signer
+	guard       *authz.EventGuard
+}
+
+func New(devices repository.CheckinDeviceRepository, checkins repository.CheckinRepository, tickets repository.TicketRepository, ticketTypes repository.TicketTypeRepository, manifests docsign.Signer, guard *authz.EventGuard) *UseCase {
+	return &UseCase{devices: devices, checkins: checkins, tickets: tickets, ticketTypes: ticketTypes, manifests: manifests, guard: guard}
+}
+
+type RegisterDeviceInput struct {
+	EventID valueobject.UUID
+	Name    string
+	Gate    string
+}
+
+type RegisteredDevice struct {
+	Device entity.CheckinDevice
+	// Token is the device credential. Only its hash is stored, so it is returned once.
+	Token string
+	// ManifestKeyID and ManifestPublicKey verify the device's offline manifests.
+	ManifestKeyID     string
+	ManifestPublicKey string
+}
+
+func (uc *UseCase) RegisterDevice(ctx context.Context, actor authz.Actor, in RegisterDeviceInput) (RegisteredDevice, error) {
+	if _, err := uc.guard.RequireEventManager(ctx, actor, in.EventID); err != nil {
+		return RegisteredDevice{}, err
+	}
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		return RegisteredDevice{}, apperror.New(apperror.CodeValidation, "name is required", nil)
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return RegisteredDevice{}, apperror.New(apperror.CodeInternal, "generate device secret failed", err)
+	}
+	createdBy := actor.UserID
+	d := entity.CheckinDevice{
+		ID:         valueobject.NewUUID(),
+		EventID:    in.EventID,
+		Name:       name,
+		Gate:       strings.TrimSpace(in.Gate),
+		SecretHash: hashSecret(secret),
+		CreatedBy:  &createdBy,
+	}
+	if _, err := uc.devices.Create(ctx, d); err != nil {
+		return RegisteredDevice{}, err
+	}
+	keyID, publicKey := uc.manifests.PublicKey()
+	return RegisteredDevice{Device: d, Token: d.ID.String() + "." + secret, ManifestKeyID: keyID, ManifestPublicKey: publicKey}, nil
+}
+
+func (uc *UseCase) ListDevices(ctx context.Context, actor authz.Actor, eventID valueobject.UUID) ([]entity.CheckinDevice, error) {
+	if _, err := uc.guard.RequireEventManager(ctx, actor, eventID); err != nil {
+		return nil, err
+	}
+	return uc.devices.ListByEventID(ctx, eventID)
+}
+
+// RevokeDevice disables a device. Revoking an already revoked device is a no-op.
+func (uc *UseCase) RevokeDevice(ctx context.Context, actor authz.Actor, eventID, deviceID valueobject.UUID) error {
+	if _, err := uc.guard.RequireEventManager(ctx, actor, eventID); err != nil {
+		return err
+	}
+	d, err := uc.devices.GetByID(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+	if d.EventID != eventID {
+		return apperror.New(apperror.CodeNotFound, "checkin device not found", nil)
+	}
+	_, err = uc.devices.Revoke(ctx, deviceID)
+	return err
+}
+
+// AuthenticateDevice resolves a device token ("<device id>.<secret>") to the device identity.
+func (uc *UseCase) AuthenticateDevice(ctx context.Context, token string) (authz.Device, error) {
+	invalid := apperror.New(apperror.CodeUnauthorized, "invalid device token", nil)
+	rawID, secret, ok := strings.Cut(token, ".")
+	if !ok || secret == "" {
+		return authz.Device{}, invalid
+	}
+	id, err := valueobject.ParseUUID(rawID)
+	if err != nil {
+		return authz.Device{}, invalid
+	}
+	d, err := uc.devices.GetByID(ctx, id)
+	if err != nil {
+		var ae *apperror.AppError
+		if errors.As(err, &ae) && ae.Code == apperror.CodeNotFound {
+			return authz.Device{}, invalid
+		}
+		return authz.Device{}, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(d.SecretHash)) != 1 {
+		return authz.Device{}, invalid
+	}
+	if d.RevokedAt != nil {
+		return authz.Device{}, apperror.New(apperror.CodeUnauthorized, "device has been revoked", nil)
+	}
+	if err := uc.devices.TouchLastSeen(ctx, d.ID, time.Now().UTC()); err != nil {
+		return authz.Device{}, err
+	}
+	return authz.Device{ID: d.ID, EventID: d.EventID, Gate: d.Gate}, nil
+}
+
+func (uc *UseCase) EventHistory(ctx context.Context, actor authz.Actor, eventID valueobject.UUID, limit, offset int) ([]entity.Checkin, error) {
+	if _, err := uc.guard.RequireEventManager(ctx, actor, eventID); err != nil {
+		return nil, err
+	}
+	return uc.checkins.ListByEventID(ctx, eventID, limit, offset)
+}
+
+// TicketHistory is visible to the ticket's buyer and to the managers of its event.
+func (uc *UseCase) TicketHistory(ctx context.Context, actor authz.Actor, ticketID valueobject.UUID, limit, offset int) ([]entity.Checkin, error) {
+	if actor.IsAnonymous() {
+		return nil, apperror.New(apperror.CodeUnauthorized, "authentication required", nil)
+	}
+	t, err := uc.tickets.GetByID(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.IsAdmin() && actor.UserID != t.HolderID {
+		tt, err := uc.ticketTypes.GetByID(ctx, t.TicketTypeID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := uc.guard.RequireEventManager(ctx, actor, tt.EventID); err != nil {
+			return nil, err
+		}
+	}
+	return uc.checkins.ListByTicketID(ctx, ticketID, limit, offset)
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashSecret uses plain SHA-256: secrets are 256-bit random values, so a slow KDF adds nothing.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -3,6 +3,7 @@ package event
 import (
 	"context"
 
+	"time2meet/internal/application/authz"
 	"time2meet/internal/domain/entity"
 	"time2meet/internal/domain/repository"
 	"time2meet/internal/domain/valueobject"
@@ -11,9 +12,12 @@ import (
 
 type UseCase struct {
 	events repository.EventRepository
+	guard  *authz.EventGuard
 }
 
-func New(events repository.EventRepository) *UseCase { return &UseCase{events: events} }
+func New(events repository.EventRepository, guard *authz.EventGuard) *UseCase {
+	return &UseCase{events: events, guard: guard}
+}
 
 type CreateEventInput struct {
 	OrganizerID     valueobject.UUID
@@ -25,9 +29,15 @@ type CreateEventInput struct {
 	CoverImage      string
 }
 
-func (uc *UseCase) Create(ctx context.Context, in CreateEventInput) (valueobject.UUID, error) {
+func (uc *UseCase) Create(ctx context.Context, actor authz.Actor, in CreateEventInput) (valueobject.UUID, error) {
+	if err := requireManager(actor); err != nil {
+		return valueobject.Nil, err
+	}
 	if in.OrganizerID == valueobject.Nil {
-		return valueobject.Nil, apperror.New(apperror.CodeValidation, "organizer_id is required", nil)
+		in.OrganizerID = actor.UserID
+	}
+	if !actor.IsAdmin() && in.OrganizerID != actor.UserID {
+		return valueobject.Nil, apperror.New(apperror.CodeForbidden, "organizers can only create their own events", nil)
 	}
 	if in.Title == "" {
 		return valueobject.Nil, apperror.New(apperror.CodeValidation, "title is required", nil)
@@ -66,10 +76,13 @@ type UpdateEventInput struct {
 	CoverImage      string
 }
 
-func (uc *UseCase) Update(ctx context.Context, in UpdateEventInput) error {
+func (uc *UseCase) Update(ctx context.Context, actor authz.Actor, in UpdateEventInput) error {
 	if in.ID == valueobject.Nil {
 		return apperror.New(apperror.CodeValidation, "id is required", nil)
 	}
+	if _, err := uc.guard.RequireEventManager(ctx, actor, in.ID); err != nil {
+		return err
+	}
 	if in.Title == "" {
 		return apperror.New(apperror.CodeValidation, "title is required", nil)
 	}
@@ -89,12 +102,15 @@ func (uc *UseCase) Update(ctx context.Context, in UpdateEventInput) error {
 	return uc.events.Update(ctx, e)
 }
 
-func (uc *UseCase) Delete(ctx context.Context, id valueobject.UUID) error {
+func (uc *UseCase) Delete(ctx context.Context, actor authz.Actor, id valueobject.UUID) error {
+	if _, err := uc.guard.RequireEventManager(ctx, actor, id); err != nil {
+		return err
+	}
 	return uc.events.Delete(ctx, id)
 }
 
-func (uc *UseCase) Cancel(ctx context.Context, id valueobject.UUID) error {
-	e, err := uc.events.GetByID(ctx, id)
+func (uc *UseCase) Cancel(ctx context.Context, actor authz.Actor, id valueobject.UUID) error {
+	e, err := uc.guard.RequireEventManager(ctx, actor, id)
 	if err != nil {
 		return err
 	}
@@ -102,4 +118,6 @@ func (uc *UseCase) Cancel(ctx context.Context, id valueobject.UUID) error {
 	return uc.events.Update(ctx, e)
 }
 
-
+func requireManager(actor authz.Actor) error {
+	return authz.RequireRole(actor, entity.UserRoleAdmin, entity.UserRoleOrganizer)
+}
@@ -4,26 +4,60 @@ import (
 	"context"
 	"time"
 
+	"time2meet/internal/application/authz"
+	"time2meet/internal/domain/entity"
 	"time2meet/internal/domain/repository"
 	"time2meet/internal/domain/valueobject"
 )
 
 type UseCase struct {
 	reports repository.ReportRepository
+	guard   *authz.EventGuard
 }
 
-func New(reports repository.ReportRepository) *UseCase { return &UseCase{reports: reports} }
+func New(reports repository.ReportRepository, guard *authz.EventGuard) *UseCase {
+	return &UseCase{reports: reports, guard: guard}
+}
 
-func (uc *UseCase) Sales(ctx context.Context, start, end time.Time) ([]repository.SalesReportRow, error) {
+func (uc *UseCase) Sales(ctx context.Context, actor authz.Actor, start, end time.Time) ([]repository.SalesReportRow, error) {
+	if err := authz.RequireRole(actor, entity.UserRoleAdmin); err != nil {
+		return nil, err
+	}
 	return uc.reports.SalesReport(ctx, start, end)
 }
 
-func (uc *UseCase) Attendance(ctx context.Context, eventID valueobject.UUID) ([]repository.AttendanceRow, error) {
+func (uc *UseCase) Attendance(ctx context.Context, actor authz.Actor, eventID valueobject.UUID) ([]repository.AttendanceRow, error) {
+	if _, err := uc.guard.RequireEventManager(ctx, actor, eventID); err != nil {
+		return nil, err
+	}
 	return uc.reports.AttendanceStats(ctx, eventID)
 }
 
-func (uc *UseCase) Popular(ctx context.Context, limit, days int) ([]repository.PopularEventRow, error) {
-	return uc.reports.PopularEvents(ctx, limit, days)
+// Popular ranks events by recent registrations and sales. Organizers only see their own
+// events, as sales figures of other organizers' events are not theirs to see.
+func (uc *UseCase) Popular(ctx context.Context, actor authz.Actor, limit, days int) ([]repository.PopularEventRow, error) {
+	if err := authz.RequireRole(actor, entity.UserRoleAdmin, entity.UserRoleOrganizer); err != nil {
+		return nil, err
+	}
+	var organizerID *valueobject.UUID
+	if !actor.IsAdmin() {
+		organizerID = &actor.UserID
+	}
+	return uc.reports.PopularEvents(ctx, limit, days, organizerID)
 }
 
+// FlaggedPurchases lists orders that tripped a velocity check, for admins to review.
+func (uc *UseCase) FlaggedPurchases(ctx context.Context, actor authz.Actor, start, end time.Time) ([]repository.FlaggedPurchaseRow, error) {
+	if err := authz.RequireRole(actor, entity.UserRoleAdmin); err != nil {
+		return nil, err
+	}
+	return uc.reports.FlaggedPurchases(ctx, start, end)
+}
 
+// Waitlist summarises the waitlists of the event's ticket types and what became of their offers.
+func (uc *UseCase) Waitlist(ctx context.Context, actor authz.Actor, eventID valueobject.UUID) ([]repository.WaitlistReportRow, error) {
+	if _, err := uc.guard.RequireEventManager(ctx, actor, eventID); err != nil {
+		return nil, err
+	}
+	return uc.reports.WaitlistOffers(ctx, eventID)
+}
@@ -0,0 +1,44 @@
+package report
+
+import (
+	"context"
+	"testing"
+
+	"time2meet/internal/application/authz"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+)
+
+type fakeReports struct {
+	repository.ReportRepository
+	organizerID *valueobject.UUID
+}
+
+func (f *fakeReports) PopularEvents(_ context.Context, _, _ int, organizerID *valueobject.UUID) ([]repository.PopularEventRow, error) {
+	f.organizerID = organizerID
+	return nil, nil
+}
+
+func TestPopularScopesOrganizersToTheirEvents(t *testing.T) {
+	organizer := authz.Actor{UserID: valueobject.NewUUID(), Role: entity.UserRoleOrganizer}
+	admin := authz.Actor{UserID: valueobject.NewUUID(), Role: entity.UserRoleAdmin}
+
+	reports := &fakeReports{}
+	uc := New(reports, nil)
+	if _, err := uc.Popular(context.Background(), organizer, 10, 30); err != nil {
+		t.Fatalf("Popular as organizer: %v", err)
+	}
+	if reports.organizerID == nil || *reports.organizerID != organizer.UserID {
+		t.Errorf("organizer filter = %v, want %s", reports.organizerID, organizer.UserID)
+	}
+	if _, err := uc.Popular(context.Background(), admin, 10, 30); err != nil {
+		t.Fatalf("Popular as admin: %v", err)
+	}
+	if reports.organizerID != nil {
+		t.Errorf("admin results filtered by organizer %s", *reports.organizerID)
+	}
+	if _, err := uc.Popular(context.Background(), authz.Actor{UserID: valueobject.NewUUID(), Role: entity.UserRoleAttendee}, 10, 30); err == nil {
+		t.Error("attendee got popular events")
+	}
+}
@@ -0,0 +1,68 @@
+package ticket
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"time2meet/internal/application/authz"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+)
+
+// newAttempt starts the audit record of a scan. Devices fall back to the gate they were
+// registered with when the scan does not name one.
+func newAttempt(actor authz.Actor, eventID valueobject.UUID, gate string, offline bool, scannedAt time.Time) entity.Checkin {
+	c := entity.Checkin{
+		ID:        valueobject.NewUUID(),
+		Gate:      gate,
+		Offline:   offline,
+		ScannedAt: scannedAt,
+	}
+	if eventID != valueobject.Nil {
+		c.EventID = &eventID
+	}
+	if actor.IsDevice() {
+		deviceID := actor.Device.ID
+		c.DeviceID = &deviceID
+		if c.Gate == "" {
+			c.Gate = actor.Device.Gate
+		}
+	} else if !actor.IsAnonymous() {
+		staffID := actor.UserID
+		c.StaffID = &staffID
+	}
+	return c
+}
+
+// recordFailure stores a rejected scan attempt and returns err unchanged. Recording is best
+// effort: failing to write the audit row must not hide why the scan was rejected.
+func recordFailure(ctx context.Context, checkins repository.CheckinRepository, c entity.Checkin, err error) error {
+	c.Result, c.Message = checkinResult(err)
+	_, _ = checkins.Create(ctx, c)
+	return err
+}
+
+func checkinResult(err error) (entity.CheckinResult, string) {
+	var ae *apperror.AppError
+	if !errors.As(err, &ae) {
+		return entity.CheckinResultRejected, "internal error"
+	}
+	switch ae.Code {
+	case apperror.CodeAlreadyUsed:
+		return entity.CheckinResultDuplicate, ae.Message
+	case apperror.CodeWrongEvent:
+		return entity.CheckinResultWrongEvent, ae.Message
+	case apperror.CodeInvalidQRCode, apperror.CodeNotFound, apperror.CodeValidation:
+		return entity.CheckinResultInvalid, ae.Message
+	default:
+		return entity.CheckinResultRejected, ae.Message
+	}
+}
+
+func wrongEvent(ticketEventID valueobject.UUID) error {
+	return apperror.New(apperror.CodeWrongEvent, "ticket belongs to another event", nil).
+		WithDetails(map[string]any{"ticket_event_id": ticketEventID.String()})
+}
@@ -0,0 +1,165 @@
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"time2meet/internal/application/authz"
+	"time2meet/internal/application/port/auditctx"
+	"time2meet/internal/application/port/qrtoken"
+	"time2meet/internal/application/port/tickettx"
+	"time2meet/internal/application/tx"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// maxCompTickets caps one issuance; larger guest lists are issued in several requests.
+const maxCompTickets = 500
+
+// CompUseCase issues complimentary tickets. Comp tickets are paid tickets without an order:
+// they take capacity like any other ticket but are left out of revenue, purchase limits and
+// refunds.
+type CompUseCase struct {
+	tx    tx.Manager
+	audit auditctx.Setter
+	q     tickettx.Queries
+	qr    qrtoken.Signer
+	guard *authz.EventGuard
+}
+
+func NewComp(txm tx.Manager, audit auditctx.Setter, q tickettx.Queries, qr qrtoken.Signer, guard *authz.EventGuard) *CompUseCase {
+	return &CompUseCase{tx: txm, audit: audit, q: q, qr: qr, guard: guard}
+}
+
+type IssueCompInput struct {
+	Actor        authz.Actor
+	IP           string
+	EventID      valueobject.UUID
+	TicketTypeID valueobject.UUID
+	// Kind defaults to guest.
+	Kind   valueobject.CompKind
+	Emails []string
+	// QuantityEach is how many tickets every email gets; it defaults to 1. An email listed
+	// twice gets twice as many.
+	QuantityEach int
+}
+
+type CompRecipient struct {
+	Email  valueobject.Email
+	UserID valueobject.UUID
+	// Created reports that a placeholder account was made for the email.
+	Created bool
+	Tickets []entity.Ticket
+}
+
+// Issue gives every email QuantityEach comp tickets of the type, creating placeholder attendee
+// accounts for unknown emails. Sale windows and waitlists do not apply, but capacity does: the
+// whole issuance fails when the type has too few tickets left.
+func (uc *CompUseCase) Issue(ctx context.Context, in IssueCompInput) ([]CompRecipient, error) {
+	if in.TicketTypeID == valueobject.Nil {
+		return nil, apperror.New(apperror.CodeValidation, "ticket_type_id is required", nil)
+	}
+	if in.Kind == "" {
+		in.Kind = valueobject.CompKindGuest
+	}
+	if err := in.Kind.Validate(); err != nil {
+		return nil, apperror.New(apperror.CodeValidation, "kind must be guest, press or staff", err)
+	}
+	if in.QuantityEach == 0 {
+		in.QuantityEach = 1
+	}
+	if in.QuantityEach < 0 {
+		return nil, apperror.New(apperror.CodeValidation, "quantity_each must be positive", nil)
+	}
+	if len(in.Emails) == 0 {
+		return nil, apperror.New(apperror.CodeValidation, "emails are required", nil)
+	}
+	// Compared by division so a huge quantity_each cannot overflow the product.
+	if in.QuantityEach > maxCompTickets || len(in.Emails) > maxCompTickets/in.QuantityEach {
+		return nil, apperror.New(apperror.CodeValidation, fmt.Sprintf("at most %d comp tickets per request", maxCompTickets), nil)
+	}
+	var emails []valueobject.Email
+	counts := make(map[valueobject.Email]int, len(in.Emails))
+	for i, s := range in.Emails {
+		e, err := valueobject.ParseEmail(s)
+		if err != nil {
+			return nil, apperror.New(apperror.CodeValidation, "invalid email", err).
+				WithDetails(map[string]any{"index": i})
+		}
+		if counts[e] == 0 {
+			emails = append(emails, e)
+		}
+		counts[e] += in.QuantityEach
+	}
+	total := len(in.Emails) * in.QuantityEach
+
+	if _, err := uc.guard.RequireEventManager(ctx, in.Actor, in.EventID); err != nil {
+		return nil, err
+	}
+
+	var out []CompRecipient
+	err := uc.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
+		out = nil
+		if err := uc.audit.Set(ctx, txx, in.Actor.UserID, in.IP); err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		tt, err := uc.q.LockTicketTypeForUpdate(ctx, txx, in.TicketTypeID)
+		if err != nil {
+			return err
+		}
+		if tt.EventID != in.EventID {
+			return apperror.New(apperror.CodeNotFound, "ticket type not found", nil)
+		}
+		if tt.EventStatus == valueobject.EventStatusCancelled || tt.EventStatus == valueobject.EventStatusCompleted {
+			return apperror.New(apperror.CodeInvalidState, fmt.Sprintf("event is %s", tt.EventStatus), nil)
+		}
+		if available := tt.Available(); total > available {
+			return apperror.New(apperror.CodeSoldOut, fmt.Sprintf("only %d tickets left", available), nil).
+				WithDetails(map[string]any{"ticket_type_id": tt.ID.String(), "available": available, "requested": total})
+		}
+		for _, email := range emails {
+			name, _, _ := strings.Cut(email.String(), "@")
+			userID, created, err := uc.q.EnsureUserByEmail(ctx, txx, email, name)
+			if err != nil {
+				return err
+			}
+			r := CompRecipient{Email: email, UserID: userID, Created: created}
+			for n := 0; n < counts[email]; n++ {
+				ticketID := valueobject.NewUUID()
+				code, err := uc.qr.Sign(qrtoken.Payload{TicketID: ticketID, EventID: tt.EventID, IssuedAt: now})
+				if err != nil {
+					return err
+				}
+				t := entity.Ticket{
+					ID:           ticketID,
+					TicketTypeID: tt.ID,
+					BuyerID:      userID,
+					HolderID:     userID,
+					PurchaseDate: now,
+					Status:       valueobject.TicketStatusPaid,
+					QRCode:       code,
+					Comp:         true,
+					CompKind:     in.Kind,
+					CreatedAt:    now,
+					UpdatedAt:    now,
+				}
+				if err := uc.q.InsertTicket(ctx, txx, t); err != nil {
+					return err
+				}
+				r.Tickets = append(r.Tickets, t)
+			}
+			out = append(out, r)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
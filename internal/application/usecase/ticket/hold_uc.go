@@ -0,0 +1,201 @@
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"time2meet/internal/application/authz"
+	"time2meet/internal/application/port/auditctx"
+	"time2meet/internal/application/port/qrtoken"
+	"time2meet/internal/application/port/tickettx"
+	"time2meet/internal/application/tx"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// HoldUseCase reserves inventory for the duration of a checkout. A hold counts against the
+// ticket type's capacity (quantity_held) until it is confirmed, released or swept as expired.
+type HoldUseCase struct {
+	tx    tx.Manager
+	audit auditctx.Setter
+	q     tickettx.Queries
+	holds repository.TicketHoldRepository
+	qr    qrtoken.Signer
+	pay   *PaymentProcessor
+	ttl   time.Duration
+}
+
+func NewHold(txm tx.Manager, audit auditctx.Setter, q tickettx.Queries, holds repository.TicketHoldRepository, qr qrtoken.Signer, pay *PaymentProcessor, ttl time.Duration) *HoldUseCase {
+	return &HoldUseCase{tx: txm, audit: audit, q: q, holds: holds, qr: qr, pay: pay, ttl: ttl}
+}
+
+type CreateHoldInput struct {
+	Actor        authz.Actor
+	IP           string
+	TicketTypeID valueobject.UUID
+	Quantity     int
+}
+
+func (uc *HoldUseCase) Create(ctx context.Context, in CreateHoldInput) (entity.TicketHold, error) {
+	if in.Actor.IsAnonymous() {
+		return entity.TicketHold{}, apperror.New(apperror.CodeUnauthorized, "authentication required", nil)
+	}
+	if in.TicketTypeID == valueobject.Nil {
+		return entity.TicketHold{}, apperror.New(apperror.CodeValidation, "ticket_type_id is required", nil)
+	}
+	if in.Quantity <= 0 {
+		return entity.TicketHold{}, apperror.New(apperror.CodeValidation, "quantity must be positive", nil)
+	}
+	if in.Quantity > maxTicketsPerOrder {
+		return entity.TicketHold{}, apperror.New(apperror.CodeValidation, fmt.Sprintf("at most %d tickets per order", maxTicketsPerOrder), nil)
+	}
+
+	var out entity.TicketHold
+	err := uc.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
+		if err := uc.audit.Set(ctx, txx, in.Actor.UserID, in.IP); err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		tt, err := uc.q.LockTicketTypeForUpdate(ctx, txx, in.TicketTypeID)
+		if err != nil {
+			return err
+		}
+		if err := checkOnSale(tt, now); err != nil {
+			return err
+		}
+		if err := checkWaitlist(tt); err != nil {
+			return err
+		}
+		if available := tt.Available(); in.Quantity > available {
+			return apperror.New(apperror.CodeSoldOut, fmt.Sprintf("only %d tickets left", available), nil).
+				WithDetails(map[string]any{"ticket_type_id": tt.ID.String(), "available": available})
+		}
+		if err := checkPurchaseLimits(ctx, txx, uc.q, tt, in.Actor.UserID, in.Quantity); err != nil {
+			return err
+		}
+		h := entity.TicketHold{
+			ID:           valueobject.NewUUID(),
+			TicketTypeID: tt.ID,
+			UserID:       in.Actor.UserID,
+			Quantity:     in.Quantity,
+			Status:       valueobject.HoldStatusActive,
+			ExpiresAt:    now.Add(uc.ttl),
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+		if err := uc.q.InsertHold(ctx, txx, h); err != nil {
+			return err
+		}
+		out = h
+		return nil
+	})
+	if err != nil {
+		return entity.TicketHold{}, err
+	}
+	return out, nil
+}
+
+func (uc *HoldUseCase) Get(ctx context.Context, actor authz.Actor, id valueobject.UUID) (entity.TicketHold, error) {
+	h, err := uc.holds.GetByID(ctx, id)
+	if err != nil {
+		return entity.TicketHold{}, err
+	}
+	if err := authz.RequireSelfOrAdmin(actor, h.UserID); err != nil {
+		return entity.TicketHold{}, err
+	}
+	return h, nil
+}
+
+// Release gives the held tickets back before the hold expires.
+func (uc *HoldUseCase) Release(ctx context.Context, actor authz.Actor, ip string, id valueobject.UUID) error {
+	return uc.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
+		if err := uc.audit.Set(ctx, txx, actor.UserID, ip); err != nil {
+			return err
+		}
+		h, err := uc.q.LockHoldForUpdate(ctx, txx, id)
+		if err != nil {
+			return err
+		}
+		if err := authz.RequireSelfOrAdmin(actor, h.UserID); err != nil {
+			return err
+		}
+		ok, err := uc.q.FinishHold(ctx, txx, h.ID, valueobject.HoldStatusReleased, nil)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return apperror.New(apperror.CodeInvalidState, "hold is "+string(h.Status), nil)
+		}
+		return nil
+	})
+}
+
+type ConfirmHoldInput struct {
+	Actor  authz.Actor
+	IP     string
+	HoldID valueobject.UUID
+	// PromoCode, ExpectedTotal and Currency are optional, as in PlaceOrderInput.
+	PromoCode     string
+	ExpectedTotal string // decimal string
+	Currency      string
+}
+
+// Confirm converts an active hold into an order and charges it. The hold is finished before
+// the tickets are issued so its quantity is never counted twice against capacity.
+func (uc *HoldUseCase) Confirm(ctx context.Context, in ConfirmHoldInput) (OrderOutput, error) {
+	if in.Actor.IsAnonymous() {
+		return OrderOutput{}, apperror.New(apperror.CodeUnauthorized, "authentication required", nil)
+	}
+	var expected *decimal.Decimal
+	if in.ExpectedTotal != "" {
+		amt, err := decimal.NewFromString(in.ExpectedTotal)
+		if err != nil {
+			return OrderOutput{}, apperror.New(apperror.CodeValidation, "total_amount must be decimal string", err)
+		}
+		expected = &amt
+	}
+	var currency valueobject.Currency
+	if in.Currency != "" {
+		c, err := valueobject.ParseCurrency(in.Currency)
+		if err != nil {
+			return OrderOutput{}, apperror.New(apperror.CodeValidation, "invalid currency", err)
+		}
+		currency = c
+	}
+
+	return uc.pay.checkout(ctx, in.Actor, in.IP, func(ctx context.Context, txx *sqlx.Tx) (OrderOutput, error) {
+		now := time.Now().UTC()
+		h, err := uc.q.LockHoldForUpdate(ctx, txx, in.HoldID)
+		if err != nil {
+			return OrderOutput{}, err
+		}
+		if h.UserID != in.Actor.UserID {
+			return OrderOutput{}, apperror.New(apperror.CodeForbidden, "hold belongs to another user", nil)
+		}
+		if h.Status != valueobject.HoldStatusActive {
+			return OrderOutput{}, apperror.New(apperror.CodeInvalidState, "hold is "+string(h.Status), nil)
+		}
+		if !now.Before(h.ExpiresAt) {
+			return OrderOutput{}, apperror.New(apperror.CodeHoldExpired, fmt.Sprintf("hold expired at %s", h.ExpiresAt.UTC().Format(time.RFC3339)), nil)
+		}
+		orderID := valueobject.NewUUID()
+		if _, err := uc.q.FinishHold(ctx, txx, h.ID, valueobject.HoldStatusConverted, &orderID); err != nil {
+			return OrderOutput{}, err
+		}
+		lines := []OrderLine{{TicketTypeID: h.TicketTypeID, Quantity: h.Quantity, Reserved: true}}
+		res, err := placeOrder(ctx, txx, uc.q, uc.qr, orderID, h.UserID, in.IP, lines, currency, in.PromoCode, now)
+		if err != nil {
+			return OrderOutput{}, err
+		}
+		if err := checkExpectedTotal(res.Order, expected); err != nil {
+			return OrderOutput{}, err
+		}
+		return res, nil
+	})
+}
@@ -0,0 +1,297 @@
+package ticket
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"time2meet/internal/application/authz"
+	"time2meet/internal/application/port/auditctx"
+	"time2meet/internal/application/port/docsign"
+	"time2meet/internal/application/port/qrtoken"
+	"time2meet/internal/application/port/tickettx"
+	"time2meet/internal/application/tx"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	maxOfflineScans = 1000
+	// offlineClockSkew tolerates scanner clocks running slightly ahead of the server.
+	offlineClockSkew = 5 * time.Minute
+)
+
+// OfflineCheckinUseCase lets scanners work without connectivity: they download a signed
+// manifest of admissible tickets and later upload the scans they accepted locally.
+type OfflineCheckinUseCase struct {
+	tx        tx.Manager
+	audit     auditctx.Setter
+	q         tickettx.Queries
+	tickets   repository.TicketRepository
+	checkins  repository.CheckinRepository
+	devices   repository.CheckinDeviceRepository
+	qr        qrtoken.Signer
+	manifests docsign.Signer
+	guard     *authz.EventGuard
+}
+
+func NewOfflineCheckin(txm tx.Manager, audit auditctx.Setter, q tickettx.Queries, tickets repository.TicketRepository, checkins repository.CheckinRepository, devices repository.CheckinDeviceRepository, qr qrtoken.Signer, manifests docsign.Signer, guard *authz.EventGuard) *OfflineCheckinUseCase {
+	return &OfflineCheckinUseCase{tx: txm, audit: audit, q: q, tickets: tickets, checkins: checkins, devices: devices, qr: qr, manifests: manifests, guard: guard}
+}
+
+type ManifestEntry struct {
+	TicketID valueobject.UUID
+	// CodeHash is the hex SHA-256 of the QR payload. Raw codes are never exported, so a
+	// leaked manifest cannot be used to print tickets.
+	CodeHash   string
+	Status     valueobject.TicketStatus
+	EntryCount int
+	MaxEntries int
+}
+
+type Manifest struct {
+	EventID valueobject.UUID
+	// DeviceID is the scanner the manifest was issued to.
+	DeviceID    valueobject.UUID
+	GeneratedAt time.Time
+	Entries     []ManifestEntry
+	KeyID       string
+	Signature   string
+}
+
+// Manifest builds the event's manifest for one scanner device: a device gets its own, and
+// organizers fetch one on behalf of a registered device of the event. It is signed with the
+// document key, whose public half devices receive on registration. The signature covers the
+// canonical form:
+//
+//	<event_id>\n<device_id>\n<generated_at unix>\n
+//	<ticket_id>:<code_hash>:<status>:<entry_count>:<max_entries>\n   (one line per entry, by ticket id)
+func (uc *OfflineCheckinUseCase) Manifest(ctx context.Context, actor authz.Actor, eventID, deviceID valueobject.UUID) (Manifest, error) {
+	if err := uc.guard.RequireScanner(ctx, actor, eventID); err != nil {
+		return Manifest{}, err
+	}
+	if actor.IsDevice() {
+		if deviceID != valueobject.Nil && deviceID != actor.Device.ID {
+			return Manifest{}, apperror.New(apperror.CodeForbidden, "devices may only fetch their own manifest", nil)
+		}
+		deviceID = actor.Device.ID
+	} else {
+		if deviceID == valueobject.Nil {
+			return Manifest{}, apperror.New(apperror.CodeValidation, "device_id is required", nil)
+		}
+		d, err := uc.devices.GetByID(ctx, deviceID)
+		if err != nil {
+			return Manifest{}, err
+		}
+		if d.EventID != eventID {
+			return Manifest{}, apperror.New(apperror.CodeNotFound, "device not found", nil)
+		}
+		if d.RevokedAt != nil {
+			return Manifest{}, apperror.New(apperror.CodeInvalidState, "device has been revoked", nil)
+		}
+	}
+	rows, err := uc.tickets.ListCheckinEntries(ctx, eventID)
+	if err != nil {
+		return Manifest{}, err
+	}
+	m := Manifest{
+		EventID:     eventID,
+		DeviceID:    deviceID,
+		GeneratedAt: time.Now().UTC().Truncate(time.Second),
+		Entries:     make([]ManifestEntry, 0, len(rows)),
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n%s\n%d\n", m.EventID, m.DeviceID, m.GeneratedAt.Unix())
+	for _, r := range rows {
+		sum := sha256.Sum256([]byte(r.QRCode))
+		e := ManifestEntry{
+			TicketID:   r.TicketID,
+			CodeHash:   hex.EncodeToString(sum[:]),
+			Status:     r.Status,
+			EntryCount: r.EntryCount,
+			MaxEntries: 1 + r.ReentryLimit,
+		}
+		fmt.Fprintf(&b, "%s:%s:%s:%d:%d\n", e.TicketID, e.CodeHash, e.Status, e.EntryCount, e.MaxEntries)
+		m.Entries = append(m.Entries, e)
+	}
+	m.KeyID, m.Signature, err = uc.manifests.Sign([]byte(b.String()))
+	if err != nil {
+		return Manifest{}, err
+	}
+	return m, nil
+}
+
+type OfflineScan struct {
+	Payload   string
+	ScannedAt time.Time
+	Gate      string
+}
+
+type SyncInput struct {
+	Actor   authz.Actor
+	IP      string
+	EventID valueobject.UUID
+	Scans   []OfflineScan
+}
+
+type ScanOutcome string
+
+const (
+	ScanAccepted ScanOutcome = "accepted"
+	// ScanConflict means the ticket was admitted elsewhere first (another gate, or online).
+	ScanConflict ScanOutcome = "conflict"
+	ScanRejected ScanOutcome = "rejected"
+)
+
+type SyncItem struct {
+	Index    int
+	TicketID valueobject.UUID
+	Gate     string
+	Outcome  ScanOutcome
+	Code     apperror.Code
+	Message  string
+	// For conflicts: the admission that won, by a staff member or a device.
+	UsedAt         *time.Time
+	UsedBy         *valueobject.UUID
+	UsedByDeviceID *valueobject.UUID
+	// FirstGate is set when the winning admission came from the same upload.
+	FirstGate string
+}
+
+type SyncResult struct {
+	Total     int
+	Accepted  int
+	Conflicts int
+	Rejected  int
+	Items     []SyncItem
+}
+
+// Sync replays offline scans in scan-time order, each in its own transaction so one bad
+// scan does not discard the rest. Scans of the same ticket beyond its entry allowance are
+// reported as conflicts together with the admission that was recorded first. Every scan is
+// also written to the check-in log with the offline flag set.
+func (uc *OfflineCheckinUseCase) Sync(ctx context.Context, in SyncInput) (SyncResult, error) {
+	if err := uc.guard.RequireScanner(ctx, in.Actor, in.EventID); err != nil {
+		return SyncResult{}, err
+	}
+	if len(in.Scans) == 0 {
+		return SyncResult{}, apperror.New(apperror.CodeValidation, "scans are required", nil)
+	}
+	if len(in.Scans) > maxOfflineScans {
+		return SyncResult{}, apperror.New(apperror.CodeValidation, fmt.Sprintf("at most %d scans per upload", maxOfflineScans), nil)
+	}
+
+	order := make([]int, len(in.Scans))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return in.Scans[order[a]].ScannedAt.Before(in.Scans[order[b]].ScannedAt)
+	})
+
+	res := SyncResult{Total: len(in.Scans), Items: make([]SyncItem, len(in.Scans))}
+	firstGate := make(map[valueobject.UUID]string)
+	now := time.Now().UTC()
+	for _, i := range order {
+		scan := in.Scans[i]
+		item := uc.syncOne(ctx, in, scan, now)
+		item.Index = i
+		if item.Gate == "" {
+			item.Gate = scan.Gate
+		}
+		switch item.Outcome {
+		case ScanAccepted:
+			res.Accepted++
+			if _, ok := firstGate[item.TicketID]; !ok {
+				firstGate[item.TicketID] = item.Gate
+			}
+		case ScanConflict:
+			res.Conflicts++
+			item.FirstGate = firstGate[item.TicketID]
+		default:
+			res.Rejected++
+		}
+		res.Items[i] = item
+	}
+	return res, nil
+}
+
+func (uc *OfflineCheckinUseCase) syncOne(ctx context.Context, in SyncInput, scan OfflineScan, now time.Time) SyncItem {
+	if scan.ScannedAt.IsZero() || scan.ScannedAt.After(now.Add(offlineClockSkew)) {
+		attempt := newAttempt(in.Actor, in.EventID, scan.Gate, true, now)
+		return uc.rejected(ctx, attempt, valueobject.Nil, apperror.New(apperror.CodeValidation, "invalid scanned_at", nil))
+	}
+	attempt := newAttempt(in.Actor, in.EventID, scan.Gate, true, scan.ScannedAt.UTC())
+	p, err := uc.qr.Verify(scan.Payload)
+	if err != nil {
+		return uc.rejected(ctx, attempt, valueobject.Nil, err)
+	}
+	attempt.TicketID = &p.TicketID
+	if p.EventID != in.EventID {
+		return uc.rejected(ctx, attempt, p.TicketID, wrongEvent(p.EventID))
+	}
+
+	item := SyncItem{TicketID: p.TicketID, Gate: attempt.Gate, Outcome: ScanAccepted}
+	err = uc.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
+		if err := uc.audit.Set(ctx, txx, in.Actor.UserID, in.IP); err != nil {
+			return err
+		}
+		t, err := uc.q.LockTicketForUpdate(ctx, txx, p.TicketID)
+		if err != nil {
+			return err
+		}
+		if t.QRCode != scan.Payload || t.EventID != p.EventID {
+			return apperror.New(apperror.CodeInvalidQRCode, "qr code has been superseded", nil)
+		}
+		if err := checkAdmissible(t); err != nil {
+			if t.Status == valueobject.TicketStatusUsed {
+				item.UsedAt, item.UsedBy, item.UsedByDeviceID = t.UsedAt, t.UsedBy, t.UsedByDeviceID
+			}
+			return err
+		}
+		ok, err := uc.q.MarkTicketUsed(ctx, txx, t.ID, scan.ScannedAt.UTC(), attempt.StaffID, attempt.DeviceID, t.MaxEntries())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return apperror.New(apperror.CodeInvalidState, "ticket is "+string(t.Status), nil)
+		}
+		attempt.Result = entity.CheckinResultAdmitted
+		return uc.q.InsertCheckin(ctx, txx, attempt)
+	})
+	if err == nil {
+		return item
+	}
+	_ = recordFailure(ctx, uc.checkins, attempt, err)
+	var ae *apperror.AppError
+	if errors.As(err, &ae) {
+		item.Code, item.Message = ae.Code, ae.Message
+		if ae.Code == apperror.CodeAlreadyUsed {
+			item.Outcome = ScanConflict
+			return item
+		}
+	} else {
+		item.Code, item.Message = apperror.CodeInternal, "internal error"
+	}
+	item.Outcome = ScanRejected
+	return item
+}
+
+func (uc *OfflineCheckinUseCase) rejected(ctx context.Context, attempt entity.Checkin, ticketID valueobject.UUID, err error) SyncItem {
+	_ = recordFailure(ctx, uc.checkins, attempt, err)
+	item := SyncItem{TicketID: ticketID, Gate: attempt.Gate, Outcome: ScanRejected, Code: apperror.CodeInternal, Message: "internal error"}
+	var ae *apperror.AppError
+	if errors.As(err, &ae) {
+		item.Code, item.Message = ae.Code, ae.Message
+	}
+	return item
+}
@@ -0,0 +1,77 @@
+package ticket
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+
+	"time2meet/internal/application/authz"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/internal/infrastructure/docsign"
+	"time2meet/pkg/apperror"
+)
+
+type fakeCheckinEntries struct {
+	repository.TicketRepository
+	rows []repository.CheckinEntry
+}
+
+func (f fakeCheckinEntries) ListCheckinEntries(context.Context, valueobject.UUID) ([]repository.CheckinEntry, error) {
+	return f.rows, nil
+}
+
+// TestManifestIsSignedForTheDevice checks that a device can verify its manifest with the public
+// key it was given, that the signature covers its device id, and that it cannot fetch another
+// device's manifest.
+func TestManifestIsSignedForTheDevice(t *testing.T) {
+	ctx := context.Background()
+	key := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	signer := docsign.NewEd25519Signer("m1", key)
+	tickets := fakeCheckinEntries{rows: []repository.CheckinEntry{
+		{TicketID: valueobject.NewUUID(), QRCode: "code", Status: valueobject.TicketStatusPaid},
+	}}
+	uc := NewOfflineCheckin(fakeTx{}, fakeAudit{}, nil, tickets, nil, nil, nil, signer, authz.NewEventGuard(nil))
+
+	eventID := valueobject.NewUUID()
+	device := authz.Device{ID: valueobject.NewUUID(), EventID: eventID}
+	actor := authz.Actor{Device: &device}
+
+	m, err := uc.Manifest(ctx, actor, eventID, valueobject.Nil)
+	if err != nil {
+		t.Fatalf("Manifest: %v", err)
+	}
+	if m.DeviceID != device.ID || m.KeyID != "m1" {
+		t.Fatalf("manifest for device %s with key %q, want %s and m1", m.DeviceID, m.KeyID, device.ID)
+	}
+
+	_, pub := signer.PublicKey()
+	pubKey, err := base64.RawURLEncoding.DecodeString(pub)
+	if err != nil {
+		t.Fatalf("decode public key: %v", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(m.Signature)
+	if err != nil {
+		t.Fatalf("decode signature: %v", err)
+	}
+	e := m.Entries[0]
+	doc := func(deviceID valueobject.UUID) []byte {
+		return []byte(fmt.Sprintf("%s\n%s\n%d\n%s:%s:%s:%d:%d\n", m.EventID, deviceID, m.GeneratedAt.Unix(),
+			e.TicketID, e.CodeHash, e.Status, e.EntryCount, e.MaxEntries))
+	}
+	if !ed25519.Verify(pubKey, doc(device.ID), sig) {
+		t.Fatal("manifest signature does not verify with the device's public key")
+	}
+	if ed25519.Verify(pubKey, doc(valueobject.NewUUID()), sig) {
+		t.Fatal("manifest signature verifies for another device")
+	}
+
+	_, err = uc.Manifest(ctx, actor, eventID, valueobject.NewUUID())
+	var ae *apperror.AppError
+	if !errors.As(err, &ae) || ae.Code != apperror.CodeForbidden {
+		t.Fatalf("fetching another device's manifest: err = %v, want forbidden", err)
+	}
+}
@@ -0,0 +1,310 @@
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"time2meet/internal/application/authz"
+	"time2meet/internal/application/port/qrtoken"
+	"time2meet/internal/application/port/tickettx"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+const maxTicketsPerOrder = 100
+
+type OrderUseCase struct {
+	q       tickettx.Queries
+	orders  repository.OrderRepository
+	tickets repository.TicketRepository
+	qr      qrtoken.Signer
+	pay     *PaymentProcessor
+}
+
+func NewOrder(q tickettx.Queries, orders repository.OrderRepository, tickets repository.TicketRepository, qr qrtoken.Signer, pay *PaymentProcessor) *OrderUseCase {
+	return &OrderUseCase{q: q, orders: orders, tickets: tickets, qr: qr, pay: pay}
+}
+
+type OrderLine struct {
+	TicketTypeID valueobject.UUID
+	Quantity     int
+	// Reserved marks a line bought from a hold: its tickets were already set aside for the
+	// buyer, so the waitlist does not apply. Client carts never set it; see normalizeLines.
+	Reserved bool
+}
+
+type PlaceOrderInput struct {
+	Actor authz.Actor
+	IP    string
+	Items []OrderLine
+	// PromoCode is optional; it is matched regardless of case.
+	PromoCode string
+	// ExpectedTotal and Currency are optional checks against the server-computed total,
+	// as in PurchaseInput.
+	ExpectedTotal string // decimal string
+	Currency      string
+}
+
+// OrderOutput is an order together with the tickets issued for it.
+type OrderOutput struct {
+	Order   entity.Order
+	Tickets []entity.Ticket
+}
+
+// Place buys every line of the cart in one transaction: either all tickets are issued or none.
+// The order is then charged; see PaymentProcessor.checkout for the possible outcomes.
+func (uc *OrderUseCase) Place(ctx context.Context, in PlaceOrderInput) (OrderOutput, error) {
+	if in.Actor.IsAnonymous() {
+		return OrderOutput{}, apperror.New(apperror.CodeUnauthorized, "authentication required", nil)
+	}
+	lines, err := normalizeLines(in.Items)
+	if err != nil {
+		return OrderOutput{}, err
+	}
+	var expected *decimal.Decimal
+	if in.ExpectedTotal != "" {
+		amt, err := decimal.NewFromString(in.ExpectedTotal)
+		if err != nil {
+			return OrderOutput{}, apperror.New(apperror.CodeValidation, "total_amount must be decimal string", err)
+		}
+		expected = &amt
+	}
+	var currency valueobject.Currency
+	if in.Currency != "" {
+		c, err := valueobject.ParseCurrency(in.Currency)
+		if err != nil {
+			return OrderOutput{}, apperror.New(apperror.CodeValidation, "invalid currency", err)
+		}
+		currency = c
+	}
+
+	return uc.pay.checkout(ctx, in.Actor, in.IP, func(ctx context.Context, txx *sqlx.Tx) (OrderOutput, error) {
+		res, err := placeOrder(ctx, txx, uc.q, uc.qr, valueobject.NewUUID(), in.Actor.UserID, in.IP, lines, currency, in.PromoCode, time.Now().UTC())
+		if err != nil {
+			return OrderOutput{}, err
+		}
+		if err := checkExpectedTotal(res.Order, expected); err != nil {
+			return OrderOutput{}, err
+		}
+		return res, nil
+	})
+}
+
+func (uc *OrderUseCase) Get(ctx context.Context, actor authz.Actor, id valueobject.UUID) (OrderOutput, error) {
+	o, err := uc.orders.GetByID(ctx, id)
+	if err != nil {
+		return OrderOutput{}, err
+	}
+	if err := authz.RequireSelfOrAdmin(actor, o.BuyerID); err != nil {
+		return OrderOutput{}, err
+	}
+	tickets, err := uc.tickets.ListByOrderID(ctx, id)
+	if err != nil {
+		return OrderOutput{}, err
+	}
+	// A transferred ticket's code belongs to its new holder.
+	for i := range tickets {
+		if tickets[i].HolderID != o.BuyerID {
+			tickets[i].QRCode = ""
+		}
+	}
+	return OrderOutput{Order: o, Tickets: tickets}, nil
+}
+
+// ListByBuyer lists the caller's orders; admins may pass another buyer.
+func (uc *OrderUseCase) ListByBuyer(ctx context.Context, actor authz.Actor, buyerID valueobject.UUID, limit, offset int) ([]entity.Order, error) {
+	if buyerID == valueobject.Nil {
+		buyerID = actor.UserID
+	}
+	if err := authz.RequireSelfOrAdmin(actor, buyerID); err != nil {
+		return nil, err
+	}
+	return uc.orders.ListByBuyerID(ctx, buyerID, limit, offset)
+}
+
+// checkExpectedTotal compares the client's expected total, when given, with the computed one.
+func checkExpectedTotal(o entity.Order, expected *decimal.Decimal) error {
+	if expected != nil && !expected.Equal(o.TotalAmount.Amount) {
+		return apperror.New(apperror.CodeConflict, fmt.Sprintf("total mismatch: current total is %s %s", o.TotalAmount.Amount.StringFixed(2), o.Currency), nil)
+	}
+	return nil
+}
+
+// normalizeLines validates the cart, merges repeated ticket types and sorts lines by ticket
+// type id. The sort order is the lock order, see placeOrder.
+func normalizeLines(items []OrderLine) ([]OrderLine, error) {
+	if len(items) == 0 {
+		return nil, apperror.New(apperror.CodeValidation, "items are required", nil)
+	}
+	qty := make(map[valueobject.UUID]int, len(items))
+	total := 0
+	for _, it := range items {
+		if it.TicketTypeID == valueobject.Nil {
+			return nil, apperror.New(apperror.CodeValidation, "ticket_type_id is required", nil)
+		}
+		if it.Quantity <= 0 {
+			return nil, apperror.New(apperror.CodeValidation, "quantity must be positive", nil)
+		}
+		qty[it.TicketTypeID] += it.Quantity
+		total += it.Quantity
+	}
+	if total > maxTicketsPerOrder {
+		return nil, apperror.New(apperror.CodeValidation, fmt.Sprintf("at most %d tickets per order", maxTicketsPerOrder), nil)
+	}
+	lines := make([]OrderLine, 0, len(qty))
+	for id, n := range qty {
+		lines = append(lines, OrderLine{TicketTypeID: id, Quantity: n})
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i].TicketTypeID.String() < lines[j].TicketTypeID.String() })
+	return lines, nil
+}
+
+// placeOrder must run inside a transaction and expects lines from normalizeLines. Ticket
+// types are locked one by one in id order, so two carts sharing types always lock them in
+// the same order and cannot deadlock each other; the promo code, if any, is locked after all
+// of them. Purchase limits are checked under each type's lock. Orders with a total are left
+// in pending_payment for the PaymentProcessor; free orders are paid right away.
+func placeOrder(ctx context.Context, txx *sqlx.Tx, q tickettx.Queries, signer qrtoken.Signer, orderID, buyerID valueobject.UUID, ip string, lines []OrderLine, currency valueobject.Currency, promoCode string, now time.Time) (OrderOutput, error) {
+	order := entity.Order{
+		ID:        orderID,
+		BuyerID:   buyerID,
+		ClientIP:  ip,
+		Items:     make([]entity.OrderItem, 0, len(lines)),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	types := make([]tickettx.LockedTicketType, 0, len(lines))
+	for _, l := range lines {
+		tt, err := q.LockTicketTypeForUpdate(ctx, txx, l.TicketTypeID)
+		if err != nil {
+			return OrderOutput{}, err
+		}
+		if err := checkOnSale(tt, now); err != nil {
+			return OrderOutput{}, err
+		}
+		if !l.Reserved {
+			if err := checkWaitlist(tt); err != nil {
+				return OrderOutput{}, err
+			}
+		}
+		if available := tt.Available(); l.Quantity > available {
+			return OrderOutput{}, apperror.New(apperror.CodeSoldOut, fmt.Sprintf("only %d tickets left", available), nil).
+				WithDetails(map[string]any{"ticket_type_id": tt.ID.String(), "available": available})
+		}
+		if err := checkPurchaseLimits(ctx, txx, q, tt, buyerID, l.Quantity); err != nil {
+			return OrderOutput{}, err
+		}
+		if order.Currency == "" {
+			order.Currency = tt.Currency
+		} else if tt.Currency != order.Currency {
+			return OrderOutput{}, apperror.New(apperror.CodeValidation, "all items of an order must be priced in one currency", nil)
+		}
+		types = append(types, tt)
+	}
+	if currency != "" && currency != order.Currency {
+		return OrderOutput{}, apperror.New(apperror.CodeConflict, fmt.Sprintf("currency mismatch: order is priced in %s", order.Currency), nil)
+	}
+	var promo *entity.PromoCode
+	if promoCode != "" {
+		eventIDs := make([]valueobject.UUID, 0, len(types))
+		for _, tt := range types {
+			eventIDs = append(eventIDs, tt.EventID)
+		}
+		p, err := lockRedeemablePromo(ctx, txx, q, promoCode, eventIDs, buyerID, now)
+		if err != nil {
+			return OrderOutput{}, err
+		}
+		promo = &p
+		order.PromoCodeID = &p.ID
+	}
+
+	// A line that crosses a price tier's cap becomes one item per price.
+	total, discount := decimal.Zero, decimal.Zero
+	var itemEvents []valueobject.UUID
+	var unitDiscounts []valueobject.Money
+	for i, l := range lines {
+		tt := types[i]
+		for _, part := range quote(tt, l.Quantity, promo, now) {
+			qty := decimal.NewFromInt(int64(part.Quantity))
+			lineTotal := valueobject.Money{Amount: part.Price.Amount.Mul(qty)}
+			lineDiscount := valueobject.Money{Amount: part.Discount.Amount.Mul(qty)}
+			order.Items = append(order.Items, entity.OrderItem{
+				ID:             valueobject.NewUUID(),
+				OrderID:        order.ID,
+				TicketTypeID:   tt.ID,
+				Quantity:       part.Quantity,
+				UnitPrice:      part.Price,
+				LineTotal:      lineTotal,
+				DiscountAmount: lineDiscount,
+			})
+			itemEvents = append(itemEvents, tt.EventID)
+			unitDiscounts = append(unitDiscounts, part.Discount)
+			total = total.Add(lineTotal.Amount)
+			discount = discount.Add(lineDiscount.Amount)
+		}
+		order.TicketCount += l.Quantity
+	}
+	if promo != nil && discount.IsZero() {
+		return OrderOutput{}, apperror.New(apperror.CodePromoInvalid, "promo code does not apply to this order", nil)
+	}
+	order.TotalAmount = valueobject.Money{Amount: total}
+	order.DiscountAmount = valueobject.Money{Amount: discount}
+	ticketStatus := valueobject.TicketStatusPendingPayment
+	order.Status = valueobject.OrderStatusPendingPayment
+	if total.IsZero() {
+		order.Status, ticketStatus = valueobject.OrderStatusPaid, valueobject.TicketStatusPaid
+	}
+	if err := q.InsertOrder(ctx, txx, order); err != nil {
+		return OrderOutput{}, err
+	}
+	if promo != nil {
+		if err := q.InsertPromoRedemption(ctx, txx, entity.PromoRedemption{
+			ID:             valueobject.NewUUID(),
+			PromoCodeID:    promo.ID,
+			OrderID:        order.ID,
+			UserID:         buyerID,
+			DiscountAmount: order.DiscountAmount,
+			Currency:       order.Currency,
+			Status:         valueobject.RedemptionStatusApplied,
+			CreatedAt:      now,
+		}); err != nil {
+			return OrderOutput{}, err
+		}
+	}
+
+	tickets := make([]entity.Ticket, 0, order.TicketCount)
+	for i, item := range order.Items {
+		for n := 0; n < item.Quantity; n++ {
+			ticketID := valueobject.NewUUID()
+			code, err := signer.Sign(qrtoken.Payload{TicketID: ticketID, EventID: itemEvents[i], IssuedAt: now})
+			if err != nil {
+				return OrderOutput{}, err
+			}
+			t := entity.Ticket{
+				ID:             ticketID,
+				TicketTypeID:   item.TicketTypeID,
+				BuyerID:        buyerID,
+				OrderID:        &order.ID,
+				PurchaseDate:   now,
+				Status:         ticketStatus,
+				QRCode:         code,
+				AmountPaid:     item.UnitPrice,
+				DiscountAmount: unitDiscounts[i],
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			}
+			if err := q.InsertTicket(ctx, txx, t); err != nil {
+				return OrderOutput{}, err
+			}
+			tickets = append(tickets, t)
+		}
+	}
+	return OrderOutput{Order: order, Tickets: tickets}, nil
+}
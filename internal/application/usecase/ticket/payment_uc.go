@@ -0,0 +1,408 @@
+package ticket
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"time2meet/internal/application/authz"
+	"time2meet/internal/application/port/auditctx"
+	"time2meet/internal/application/port/payment"
+	"time2meet/internal/application/port/tickettx"
+	"time2meet/internal/application/tx"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// gatewayTimeout bounds the provider calls made while the buyer waits for checkout.
+const gatewayTimeout = 15 * time.Second
+
+// PaymentProcessor charges orders that were committed in pending_payment and settles them.
+// Gateway calls are never made inside a database transaction, so a slow provider cannot hold
+// ticket type locks.
+type PaymentProcessor struct {
+	tx       tx.Manager
+	audit    auditctx.Setter
+	q        tickettx.Queries
+	payments repository.PaymentRepository
+	refunds  repository.RefundRepository
+	gw       payment.Gateway
+	velocity VelocityLimits
+}
+
+func NewPaymentProcessor(txm tx.Manager, audit auditctx.Setter, q tickettx.Queries, payments repository.PaymentRepository, refunds repository.RefundRepository, gw payment.Gateway, velocity VelocityLimits) *PaymentProcessor {
+	return &PaymentProcessor{tx: txm, audit: audit, q: q, payments: payments, refunds: refunds, gw: gw, velocity: velocity}
+}
+
+// checkout runs place in a transaction that also screens the order's IP and records the
+// pending payment, then charges the order. A decline is returned as CodePaymentFailed. When
+// the gateway outcome is unknown the order is returned in pending_payment and settles later.
+func (p *PaymentProcessor) checkout(ctx context.Context, actor authz.Actor, ip string, place func(ctx context.Context, txx *sqlx.Tx) (OrderOutput, error)) (OrderOutput, error) {
+	var out OrderOutput
+	var pay entity.Payment
+	err := p.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
+		if err := p.audit.Set(ctx, txx, actor.UserID, ip); err != nil {
+			return err
+		}
+		res, err := place(ctx, txx)
+		if err != nil {
+			return err
+		}
+		if err := p.velocity.screen(ctx, txx, p.q, res.Order); err != nil {
+			return err
+		}
+		if res.Order.Status == valueobject.OrderStatusPendingPayment {
+			pay = entity.Payment{
+				ID:        valueobject.NewUUID(),
+				OrderID:   res.Order.ID,
+				Provider:  p.gw.Name(),
+				Status:    valueobject.PaymentStatusPending,
+				Amount:    res.Order.TotalAmount,
+				Currency:  res.Order.Currency,
+				CreatedAt: res.Order.CreatedAt,
+				UpdatedAt: res.Order.CreatedAt,
+			}
+			if err := p.q.InsertPayment(ctx, txx, pay); err != nil {
+				return err
+			}
+		}
+		out = res
+		return nil
+	})
+	if err != nil {
+		return OrderOutput{}, err
+	}
+	if out.Order.Status != valueobject.OrderStatusPendingPayment {
+		return out, nil
+	}
+
+	status, reason := p.resume(ctx, actor.UserID, ip, pay)
+	out.setStatus(status)
+	if status == valueobject.OrderStatusPaymentFailed {
+		return OrderOutput{}, apperror.New(apperror.CodePaymentFailed, "payment was declined", nil).
+			WithDetails(map[string]any{"order_id": out.Order.ID.String(), "reason": reason})
+	}
+	return out, nil
+}
+
+// resume drives a pending payment as far as the provider allows: it creates (or recovers)
+// the intent, captures it if nobody has, and settles the outcome. It serves both checkout and
+// reconciliation. Any failure short of a definitive answer from the provider leaves the
+// order pending rather than guessing.
+func (p *PaymentProcessor) resume(ctx context.Context, userID valueobject.UUID, ip string, pay entity.Payment) (status valueobject.OrderStatus, reason string) {
+	gctx, cancel := context.WithTimeout(ctx, gatewayTimeout)
+	defer cancel()
+	var (
+		intent payment.Intent
+		err    error
+	)
+	if pay.IntentID == "" {
+		intent, err = p.gw.CreateIntent(gctx, payment.CreateIntentInput{
+			OrderID:        pay.OrderID,
+			Amount:         pay.Amount,
+			Currency:       pay.Currency,
+			IdempotencyKey: pay.ID.String(),
+		})
+		if err != nil {
+			return valueobject.OrderStatusPendingPayment, ""
+		}
+		if err := p.payments.SetIntentID(ctx, pay.ID, intent.ID); err != nil {
+			return valueobject.OrderStatusPendingPayment, ""
+		}
+	} else if intent, err = p.gw.GetIntent(gctx, pay.IntentID); err != nil {
+		return valueobject.OrderStatusPendingPayment, ""
+	}
+	if intent.Status == payment.IntentRequiresCapture {
+		if intent, err = p.gw.Capture(gctx, intent.ID); err != nil {
+			return valueobject.OrderStatusPendingPayment, ""
+		}
+	}
+	err = p.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
+		if err := p.audit.Set(ctx, txx, userID, ip); err != nil {
+			return err
+		}
+		status, err = p.applyIntent(ctx, txx, pay.ID, intent.Status, intent.FailureReason)
+		return err
+	})
+	if err != nil {
+		return valueobject.OrderStatusPendingPayment, ""
+	}
+	return status, intent.FailureReason
+}
+
+// applyIntent applies a final intent outcome to a pending payment, its order and the order's
+// tickets, and returns the resulting order status. Applying it to an already settled payment
+// changes nothing, so checkout, webhooks and reconciliation may race safely.
+func (p *PaymentProcessor) applyIntent(ctx context.Context, txx *sqlx.Tx, paymentID valueobject.UUID, intent payment.IntentStatus, reason string) (valueobject.OrderStatus, error) {
+	pay, err := p.q.LockPaymentForUpdate(ctx, txx, paymentID)
+	if err != nil {
+		return "", err
+	}
+	o, err := p.q.LockOrderForUpdate(ctx, txx, pay.OrderID)
+	if err != nil {
+		return "", err
+	}
+	if pay.Status != valueobject.PaymentStatusPending {
+		return o.Status, nil
+	}
+
+	var (
+		payStatus   valueobject.PaymentStatus
+		orderStatus valueobject.OrderStatus
+		ticketTo    valueobject.TicketStatus
+	)
+	switch intent {
+	case payment.IntentSucceeded:
+		payStatus, orderStatus, ticketTo = valueobject.PaymentStatusSucceeded, valueobject.OrderStatusPaid, valueobject.TicketStatusPaid
+	case payment.IntentFailed:
+		payStatus, orderStatus, ticketTo = valueobject.PaymentStatusFailed, valueobject.OrderStatusPaymentFailed, valueobject.TicketStatusVoid
+	default:
+		return o.Status, nil
+	}
+	if _, err := p.q.TransitionPayment(ctx, txx, pay.ID, valueobject.PaymentStatusPending, payStatus, reason); err != nil {
+		return "", err
+	}
+	if _, err := p.q.SetOrderTicketsStatus(ctx, txx, o.ID, valueobject.TicketStatusPendingPayment, ticketTo); err != nil {
+		return "", err
+	}
+	// An unpaid order does not use up its promo code. The code is locked after the ticket
+	// types, as when the order was placed.
+	if orderStatus == valueobject.OrderStatusPaymentFailed && o.PromoCodeID != nil {
+		if err := p.q.ReleasePromoRedemption(ctx, txx, o.ID); err != nil {
+			return "", err
+		}
+	}
+	if err := p.q.SetOrderStatus(ctx, txx, o.ID, orderStatus); err != nil {
+		return "", err
+	}
+	return orderStatus, nil
+}
+
+// settleRefund asks the provider to return a pending ledger refund and records the answer.
+// The refund id is the idempotency key, so retries never refund twice. Like resume, it leaves
+// the refund pending when the provider's answer is unknown.
+func (p *PaymentProcessor) settleRefund(ctx context.Context, userID valueobject.UUID, ip string, r entity.Refund, intentID string) valueobject.RefundStatus {
+	gctx, cancel := context.WithTimeout(ctx, gatewayTimeout)
+	defer cancel()
+	res, err := p.gw.Refund(gctx, intentID, r.Amount, r.ID.String())
+	if err != nil {
+		return valueobject.RefundStatusPending
+	}
+	status, reason := valueobject.RefundStatusSucceeded, ""
+	switch res.Status {
+	case payment.RefundSucceeded:
+	case payment.RefundFailed:
+		status, reason = valueobject.RefundStatusFailed, "declined"
+	default:
+		return valueobject.RefundStatusPending
+	}
+	err = p.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
+		if err := p.audit.Set(ctx, txx, userID, ip); err != nil {
+			return err
+		}
+		return p.finishRefund(ctx, txx, r, status, res.ID, reason)
+	})
+	if err != nil {
+		return valueobject.RefundStatusPending
+	}
+	return status
+}
+
+// finishRefund records the provider's answer to a pending refund. Its ticket stays paid until
+// the refund succeeds and is only then refunded and returned to the pool; after a decline it
+// stays paid and the refund may be requested again. Scans and transfers are refused while the
+// refund is pending, so the ticket is still with its buyer when it is released.
+func (p *PaymentProcessor) finishRefund(ctx context.Context, txx *sqlx.Tx, r entity.Refund, status valueobject.RefundStatus, providerRefundID, reason string) error {
+	ok, err := p.q.FinishRefund(ctx, txx, r.ID, status, providerRefundID, reason)
+	if err != nil || !ok || status != valueobject.RefundStatusSucceeded {
+		return err
+	}
+	_, err = p.q.ReleaseRefundedTicket(ctx, txx, r.TicketID)
+	return err
+}
+
+// applyRefundEvent settles the ledger refund the provider reported. A refund we have no
+// record of was issued at the provider directly and refunds the whole order; one we cannot
+// match yet (its provider id is still unknown) is left to reconciliation.
+func (p *PaymentProcessor) applyRefundEvent(ctx context.Context, txx *sqlx.Tx, paymentID valueobject.UUID, providerRefundID string) (bool, error) {
+	ledger, err := p.q.LockPaymentRefunds(ctx, txx, paymentID)
+	if err != nil {
+		return false, err
+	}
+	if len(ledger) == 0 {
+		return true, p.applyRefund(ctx, txx, paymentID)
+	}
+	for _, r := range ledger {
+		if providerRefundID != "" && r.ProviderRefundID == providerRefundID {
+			return true, p.finishRefund(ctx, txx, r, valueobject.RefundStatusSucceeded, providerRefundID, "")
+		}
+	}
+	return false, nil
+}
+
+// applyRefund marks a paid order refunded after the provider reported a refund of its
+// payment. Used tickets stay used; paid ones are refunded and return to the pool.
+func (p *PaymentProcessor) applyRefund(ctx context.Context, txx *sqlx.Tx, paymentID valueobject.UUID) error {
+	pay, err := p.q.LockPaymentForUpdate(ctx, txx, paymentID)
+	if err != nil {
+		return err
+	}
+	o, err := p.q.LockOrderForUpdate(ctx, txx, pay.OrderID)
+	if err != nil {
+		return err
+	}
+	ok, err := p.q.TransitionPayment(ctx, txx, pay.ID, valueobject.PaymentStatusSucceeded, valueobject.PaymentStatusRefunded, "")
+	if err != nil || !ok {
+		return err
+	}
+	if _, err := p.q.SetOrderTicketsStatus(ctx, txx, o.ID, valueobject.TicketStatusPaid, valueobject.TicketStatusRefunded); err != nil {
+		return err
+	}
+	return p.q.SetOrderStatus(ctx, txx, o.ID, valueobject.OrderStatusRefunded)
+}
+
+type WebhookOutcome string
+
+const (
+	WebhookProcessed WebhookOutcome = "processed"
+	// WebhookDuplicate is a redelivery of an event that was already processed.
+	WebhookDuplicate WebhookOutcome = "duplicate"
+	// WebhookIgnored covers events for unknown payments and event types we do not handle.
+	WebhookIgnored WebhookOutcome = "ignored"
+)
+
+// HandleWebhook verifies a provider notification and applies it. The event id is recorded in
+// the same transaction as the state change, so a redelivered event is applied at most once.
+func (p *PaymentProcessor) HandleWebhook(ctx context.Context, ip string, payload []byte, signature string) (WebhookOutcome, error) {
+	ev, err := p.gw.VerifyWebhook(payload, signature)
+	if err != nil {
+		return "", err
+	}
+	pay, err := p.payments.GetByIntentID(ctx, p.gw.Name(), ev.IntentID)
+	if err != nil {
+		var ae *apperror.AppError
+		if errors.As(err, &ae) && ae.Code == apperror.CodeNotFound {
+			return WebhookIgnored, nil
+		}
+		return "", err
+	}
+
+	outcome := WebhookProcessed
+	err = p.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
+		if err := p.audit.Set(ctx, txx, valueobject.Nil, ip); err != nil {
+			return err
+		}
+		fresh, err := p.q.RecordPaymentEvent(ctx, txx, p.gw.Name(), ev.ID, string(ev.Type), ev.IntentID)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			outcome = WebhookDuplicate
+			return nil
+		}
+		switch ev.Type {
+		case payment.EventIntentSucceeded:
+			_, err = p.applyIntent(ctx, txx, pay.ID, payment.IntentSucceeded, "")
+		case payment.EventIntentFailed:
+			_, err = p.applyIntent(ctx, txx, pay.ID, payment.IntentFailed, ev.Reason)
+		case payment.EventRefundSucceeded:
+			var matched bool
+			if matched, err = p.applyRefundEvent(ctx, txx, pay.ID, ev.RefundID); err == nil && !matched {
+				outcome = WebhookIgnored
+			}
+		default:
+			outcome = WebhookIgnored
+		}
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return outcome, nil
+}
+
+type ReconcileResult struct {
+	Checked      int
+	Paid         int
+	Failed       int
+	StillPending int
+
+	RefundsChecked      int
+	RefundsSucceeded    int
+	RefundsFailed       int
+	RefundsStillPending int
+}
+
+// Reconcile re-queries the provider for payments and refunds that have been pending for
+// longer than olderThan, e.g. because the gateway timed out or a webhook was lost.
+func (p *PaymentProcessor) Reconcile(ctx context.Context, olderThan time.Duration, limit int) (ReconcileResult, error) {
+	pending, err := p.payments.ListPending(ctx, time.Now().UTC().Add(-olderThan), limit)
+	if err != nil {
+		return ReconcileResult{}, err
+	}
+	var res ReconcileResult
+	for _, pay := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+		res.Checked++
+		switch status, _ := p.resume(ctx, valueobject.Nil, "", pay); status {
+		case valueobject.OrderStatusPaid:
+			res.Paid++
+		case valueobject.OrderStatusPaymentFailed:
+			res.Failed++
+		default:
+			res.StillPending++
+		}
+	}
+
+	refunds, err := p.refunds.ListPending(ctx, time.Now().UTC().Add(-olderThan), limit)
+	if err != nil {
+		return res, err
+	}
+	for _, r := range refunds {
+		if ctx.Err() != nil {
+			break
+		}
+		if r.PaymentID == nil {
+			continue
+		}
+		pay, err := p.payments.GetByID(ctx, *r.PaymentID)
+		if err != nil {
+			return res, err
+		}
+		res.RefundsChecked++
+		switch p.settleRefund(ctx, valueobject.Nil, "", r, pay.IntentID) {
+		case valueobject.RefundStatusSucceeded:
+			res.RefundsSucceeded++
+		case valueobject.RefundStatusFailed:
+			res.RefundsFailed++
+		default:
+			res.RefundsStillPending++
+		}
+	}
+	return res, nil
+}
+
+// setStatus mirrors a settlement on the in-memory output.
+func (o *OrderOutput) setStatus(status valueobject.OrderStatus) {
+	o.Order.Status = status
+	var to valueobject.TicketStatus
+	switch status {
+	case valueobject.OrderStatusPaid:
+		to = valueobject.TicketStatusPaid
+	case valueobject.OrderStatusPaymentFailed:
+		to = valueobject.TicketStatusVoid
+	default:
+		return
+	}
+	for i := range o.Tickets {
+		if o.Tickets[i].Status == valueobject.TicketStatusPendingPayment {
+			o.Tickets[i].Status = to
+		}
+	}
+}
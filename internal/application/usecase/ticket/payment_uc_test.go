@@ -0,0 +1,173 @@
+package ticket
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"time2meet/internal/application/authz"
+	"time2meet/internal/application/port/tickettx"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/internal/infrastructure/fakepay"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// paymentLedger keeps orders, their tickets' status, payments and refunds in memory.
+type paymentLedger struct {
+	orders        map[valueobject.UUID]entity.Order
+	ticketsStatus map[valueobject.UUID]valueobject.TicketStatus // by order
+	payments      map[valueobject.UUID]entity.Payment
+	refunds       map[valueobject.UUID]entity.Refund
+}
+
+func newPaymentLedger() *paymentLedger {
+	return &paymentLedger{
+		orders:        make(map[valueobject.UUID]entity.Order),
+		ticketsStatus: make(map[valueobject.UUID]valueobject.TicketStatus),
+		payments:      make(map[valueobject.UUID]entity.Payment),
+		refunds:       make(map[valueobject.UUID]entity.Refund),
+	}
+}
+
+type fakePaymentQueries struct {
+	tickettx.Queries
+	l *paymentLedger
+}
+
+func (f fakePaymentQueries) InsertPayment(_ context.Context, _ *sqlx.Tx, p entity.Payment) error {
+	f.l.payments[p.ID] = p
+	return nil
+}
+
+func (f fakePaymentQueries) LockPaymentForUpdate(_ context.Context, _ *sqlx.Tx, id valueobject.UUID) (entity.Payment, error) {
+	return f.l.payments[id], nil
+}
+
+func (f fakePaymentQueries) LockOrderForUpdate(_ context.Context, _ *sqlx.Tx, id valueobject.UUID) (entity.Order, error) {
+	return f.l.orders[id], nil
+}
+
+func (f fakePaymentQueries) TransitionPayment(_ context.Context, _ *sqlx.Tx, id valueobject.UUID, from, to valueobject.PaymentStatus, reason string) (bool, error) {
+	p := f.l.payments[id]
+	if p.Status != from {
+		return false, nil
+	}
+	p.Status, p.FailureReason = to, reason
+	f.l.payments[id] = p
+	return true, nil
+}
+
+func (f fakePaymentQueries) SetOrderTicketsStatus(_ context.Context, _ *sqlx.Tx, orderID valueobject.UUID, from, to valueobject.TicketStatus) (int, error) {
+	if f.l.ticketsStatus[orderID] != from {
+		return 0, nil
+	}
+	f.l.ticketsStatus[orderID] = to
+	return f.l.orders[orderID].TicketCount, nil
+}
+
+func (f fakePaymentQueries) SetOrderStatus(_ context.Context, _ *sqlx.Tx, orderID valueobject.UUID, status valueobject.OrderStatus) error {
+	o := f.l.orders[orderID]
+	o.Status = status
+	f.l.orders[orderID] = o
+	return nil
+}
+
+type fakePaymentRepo struct {
+	repository.PaymentRepository
+	l *paymentLedger
+}
+
+func (f fakePaymentRepo) GetByID(_ context.Context, id valueobject.UUID) (entity.Payment, error) {
+	return f.l.payments[id], nil
+}
+
+func (f fakePaymentRepo) GetByOrderID(_ context.Context, orderID valueobject.UUID) (entity.Payment, error) {
+	for _, p := range f.l.payments {
+		if p.OrderID == orderID {
+			return p, nil
+		}
+	}
+	return entity.Payment{}, apperror.New(apperror.CodeNotFound, "payment not found", nil)
+}
+
+func (f fakePaymentRepo) SetIntentID(_ context.Context, id valueobject.UUID, intentID string) error {
+	p := f.l.payments[id]
+	p.IntentID = intentID
+	f.l.payments[id] = p
+	return nil
+}
+
+func (f fakePaymentRepo) ListPending(_ context.Context, createdBefore time.Time, limit int) ([]entity.Payment, error) {
+	var out []entity.Payment
+	for _, p := range f.l.payments {
+		if p.Status == valueobject.PaymentStatusPending && p.CreatedAt.Before(createdBefore) && len(out) < limit {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+type fakeRefundRepo struct {
+	repository.RefundRepository
+	l *paymentLedger
+}
+
+func (f fakeRefundRepo) ListPending(context.Context, time.Time, int) ([]entity.Refund, error) {
+	var out []entity.Refund
+	for _, r := range f.l.refunds {
+		if r.Status == valueobject.RefundStatusPending {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// TestReconcileSettlesTimedOutCheckout charges an order while the provider's answers are lost,
+// then reconciles with the same gateway, as the API's reconciler worker does.
+func TestReconcileSettlesTimedOutCheckout(t *testing.T) {
+	ctx := context.Background()
+	l := newPaymentLedger()
+	gw := fakepay.New(fakepay.ModeTimeout, "")
+	p := NewPaymentProcessor(fakeTx{}, fakeAudit{}, fakePaymentQueries{l: l}, fakePaymentRepo{l: l}, fakeRefundRepo{l: l}, gw, VelocityLimits{})
+
+	buyer := valueobject.NewUUID()
+	out, err := p.checkout(ctx, authz.Actor{UserID: buyer, Role: entity.UserRoleAttendee}, "", func(context.Context, *sqlx.Tx) (OrderOutput, error) {
+		o := entity.Order{
+			ID:          valueobject.NewUUID(),
+			BuyerID:     buyer,
+			Status:      valueobject.OrderStatusPendingPayment,
+			Currency:    valueobject.DefaultCurrency,
+			TotalAmount: valueobject.Money{Amount: decimal.NewFromInt(40)},
+			TicketCount: 2,
+			CreatedAt:   time.Now().UTC().Add(-time.Minute),
+		}
+		l.orders[o.ID] = o
+		l.ticketsStatus[o.ID] = valueobject.TicketStatusPendingPayment
+		return OrderOutput{Order: o}, nil
+	})
+	if err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+	if out.Order.Status != valueobject.OrderStatusPendingPayment {
+		t.Fatalf("order after a timed-out capture is %s, want pending_payment", out.Order.Status)
+	}
+
+	res, err := p.Reconcile(ctx, 0, 10)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if res.Checked != 1 || res.Paid != 1 {
+		t.Fatalf("reconcile result %+v, want one payment checked and paid", res)
+	}
+	if got := l.orders[out.Order.ID].Status; got != valueobject.OrderStatusPaid {
+		t.Errorf("order is %s, want paid", got)
+	}
+	if got := l.ticketsStatus[out.Order.ID]; got != valueobject.TicketStatusPaid {
+		t.Errorf("tickets are %s, want paid", got)
+	}
+}
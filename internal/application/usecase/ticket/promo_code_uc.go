@@ -0,0 +1,245 @@
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"time2meet/internal/application/authz"
+	"time2meet/internal/application/port/tickettx"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+const maxPromoCodeLen = 32
+
+// PromoCodeUseCase manages an event's promo codes. Codes are secret until handed out, so only
+// the event's managers may list them.
+type PromoCodeUseCase struct {
+	promos      repository.PromoCodeRepository
+	ticketTypes repository.TicketTypeRepository
+	guard       *authz.EventGuard
+}
+
+func NewPromoCodeUC(promos repository.PromoCodeRepository, ticketTypes repository.TicketTypeRepository, guard *authz.EventGuard) *PromoCodeUseCase {
+	return &PromoCodeUseCase{promos: promos, ticketTypes: ticketTypes, guard: guard}
+}
+
+type PromoCodeInput struct {
+	Code          string
+	DiscountType  string
+	DiscountValue string // decimal string: a percent or an amount in Currency
+	// Currency is required for fixed discounts and ignored for percentages.
+	Currency string
+	// TicketTypeIDs limits the code to some of the event's ticket types; empty means all.
+	TicketTypeIDs  []valueobject.UUID
+	MaxUses        *int
+	MaxUsesPerUser *int
+	ValidFrom      *time.Time
+	ValidUntil     *time.Time
+	IsActive       bool
+}
+
+type CreatePromoCodeInput struct {
+	EventID valueobject.UUID
+	PromoCodeInput
+}
+
+type UpdatePromoCodeInput struct {
+	EventID     valueobject.UUID
+	PromoCodeID valueobject.UUID
+	PromoCodeInput
+}
+
+func (uc *PromoCodeUseCase) Create(ctx context.Context, actor authz.Actor, in CreatePromoCodeInput) (entity.PromoCode, error) {
+	if _, err := uc.guard.RequireEventManager(ctx, actor, in.EventID); err != nil {
+		return entity.PromoCode{}, err
+	}
+	p, err := uc.build(ctx, in.EventID, in.PromoCodeInput)
+	if err != nil {
+		return entity.PromoCode{}, err
+	}
+	p.CreatedBy = actor.UserID
+	id, err := uc.promos.Create(ctx, p)
+	if err != nil {
+		return entity.PromoCode{}, err
+	}
+	return uc.promos.GetByID(ctx, id)
+}
+
+func (uc *PromoCodeUseCase) Get(ctx context.Context, actor authz.Actor, eventID, promoCodeID valueobject.UUID) (entity.PromoCode, error) {
+	if _, err := uc.guard.RequireEventManager(ctx, actor, eventID); err != nil {
+		return entity.PromoCode{}, err
+	}
+	return uc.load(ctx, eventID, promoCodeID)
+}
+
+func (uc *PromoCodeUseCase) List(ctx context.Context, actor authz.Actor, eventID valueobject.UUID) ([]entity.PromoCode, error) {
+	if _, err := uc.guard.RequireEventManager(ctx, actor, eventID); err != nil {
+		return nil, err
+	}
+	return uc.promos.ListByEventID(ctx, eventID)
+}
+
+// Update rewrites the code's rules. Lowering max_uses below the current usage only stops
+// further redemptions.
+func (uc *PromoCodeUseCase) Update(ctx context.Context, actor authz.Actor, in UpdatePromoCodeInput) (entity.PromoCode, error) {
+	if _, err := uc.guard.RequireEventManager(ctx, actor, in.EventID); err != nil {
+		return entity.PromoCode{}, err
+	}
+	cur, err := uc.load(ctx, in.EventID, in.PromoCodeID)
+	if err != nil {
+		return entity.PromoCode{}, err
+	}
+	p, err := uc.build(ctx, in.EventID, in.PromoCodeInput)
+	if err != nil {
+		return entity.PromoCode{}, err
+	}
+	p.ID = cur.ID
+	if err := uc.promos.Update(ctx, p); err != nil {
+		return entity.PromoCode{}, err
+	}
+	return uc.promos.GetByID(ctx, cur.ID)
+}
+
+func (uc *PromoCodeUseCase) Delete(ctx context.Context, actor authz.Actor, eventID, promoCodeID valueobject.UUID) error {
+	if _, err := uc.guard.RequireEventManager(ctx, actor, eventID); err != nil {
+		return err
+	}
+	cur, err := uc.load(ctx, eventID, promoCodeID)
+	if err != nil {
+		return err
+	}
+	return uc.promos.Delete(ctx, cur.ID)
+}
+
+// load fetches a promo code and hides codes that belong to a different event.
+func (uc *PromoCodeUseCase) load(ctx context.Context, eventID, promoCodeID valueobject.UUID) (entity.PromoCode, error) {
+	p, err := uc.promos.GetByID(ctx, promoCodeID)
+	if err != nil {
+		return entity.PromoCode{}, err
+	}
+	if p.EventID != eventID {
+		return entity.PromoCode{}, apperror.New(apperror.CodeNotFound, "promo code not found", nil)
+	}
+	return p, nil
+}
+
+func (uc *PromoCodeUseCase) build(ctx context.Context, eventID valueobject.UUID, in PromoCodeInput) (entity.PromoCode, error) {
+	code := strings.TrimSpace(in.Code)
+	if code == "" {
+		return entity.PromoCode{}, apperror.New(apperror.CodeValidation, "code is required", nil)
+	}
+	if len(code) > maxPromoCodeLen {
+		return entity.PromoCode{}, apperror.New(apperror.CodeValidation, fmt.Sprintf("code must be at most %d characters", maxPromoCodeLen), nil)
+	}
+	for _, r := range code {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return entity.PromoCode{}, apperror.New(apperror.CodeValidation, "code may only contain latin letters, digits, '-' and '_'", nil)
+		}
+	}
+	dt := valueobject.DiscountType(in.DiscountType)
+	if err := dt.Validate(); err != nil {
+		return entity.PromoCode{}, apperror.New(apperror.CodeValidation, "discount_type must be percent or fixed", err)
+	}
+	value, err := decimal.NewFromString(strings.TrimSpace(in.DiscountValue))
+	if err != nil {
+		return entity.PromoCode{}, apperror.New(apperror.CodeValidation, "invalid discount_value", err)
+	}
+	if value.Exponent() < -2 {
+		return entity.PromoCode{}, apperror.New(apperror.CodeValidation, "discount_value must have at most 2 decimal places", nil)
+	}
+	if !value.IsPositive() {
+		return entity.PromoCode{}, apperror.New(apperror.CodeValidation, "discount_value must be positive", nil)
+	}
+	var currency valueobject.Currency
+	if dt == valueobject.DiscountTypePercent {
+		if value.GreaterThan(decimal.NewFromInt(100)) {
+			return entity.PromoCode{}, apperror.New(apperror.CodeValidation, "percent discount_value must be at most 100", nil)
+		}
+	} else {
+		currency = valueobject.DefaultCurrency
+		if in.Currency != "" {
+			if currency, err = valueobject.ParseCurrency(in.Currency); err != nil {
+				return entity.PromoCode{}, apperror.New(apperror.CodeValidation, "invalid currency", err)
+			}
+		}
+	}
+	if in.MaxUses != nil && *in.MaxUses <= 0 {
+		return entity.PromoCode{}, apperror.New(apperror.CodeValidation, "max_uses must be positive", nil)
+	}
+	if in.MaxUsesPerUser != nil && *in.MaxUsesPerUser <= 0 {
+		return entity.PromoCode{}, apperror.New(apperror.CodeValidation, "max_uses_per_user must be positive", nil)
+	}
+	if in.ValidFrom != nil && in.ValidUntil != nil && !in.ValidUntil.After(*in.ValidFrom) {
+		return entity.PromoCode{}, apperror.New(apperror.CodeValidation, "valid_until must be after valid_from", nil)
+	}
+	ttIDs := make([]valueobject.UUID, 0, len(in.TicketTypeIDs))
+	seen := make(map[valueobject.UUID]bool, len(in.TicketTypeIDs))
+	for _, id := range in.TicketTypeIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		tt, err := uc.ticketTypes.GetByID(ctx, id)
+		if err != nil {
+			return entity.PromoCode{}, err
+		}
+		if tt.EventID != eventID {
+			return entity.PromoCode{}, apperror.New(apperror.CodeValidation, "ticket type "+id.String()+" belongs to another event", nil)
+		}
+		ttIDs = append(ttIDs, id)
+	}
+	return entity.PromoCode{
+		EventID:        eventID,
+		Code:           code,
+		DiscountType:   dt,
+		DiscountValue:  value,
+		Currency:       currency,
+		TicketTypeIDs:  ttIDs,
+		MaxUses:        in.MaxUses,
+		MaxUsesPerUser: in.MaxUsesPerUser,
+		ValidFrom:      in.ValidFrom,
+		ValidUntil:     in.ValidUntil,
+		IsActive:       in.IsActive,
+	}, nil
+}
+
+// lockRedeemablePromo locks the code of one of the order's events and checks that the buyer
+// may redeem it now. The lock serializes redemptions of one code, so the caps cannot be
+// overrun by concurrent orders. Whether it applies to the order's ticket types is left to
+// placeOrder.
+func lockRedeemablePromo(ctx context.Context, txx *sqlx.Tx, q tickettx.Queries, code string, eventIDs []valueobject.UUID, buyerID valueobject.UUID, now time.Time) (entity.PromoCode, error) {
+	p, err := q.LockPromoCodeByCode(ctx, txx, strings.TrimSpace(code), eventIDs)
+	if err != nil {
+		return entity.PromoCode{}, err
+	}
+	if !p.IsActive {
+		return entity.PromoCode{}, apperror.New(apperror.CodePromoInvalid, "promo code is not active", nil)
+	}
+	if p.ValidFrom != nil && now.Before(*p.ValidFrom) {
+		return entity.PromoCode{}, apperror.New(apperror.CodePromoInvalid, fmt.Sprintf("promo code is valid from %s", p.ValidFrom.UTC().Format(time.RFC3339)), nil)
+	}
+	if p.ValidUntil != nil && !now.Before(*p.ValidUntil) {
+		return entity.PromoCode{}, apperror.New(apperror.CodePromoInvalid, fmt.Sprintf("promo code expired at %s", p.ValidUntil.UTC().Format(time.RFC3339)), nil)
+	}
+	if p.MaxUses != nil && p.UsedCount >= *p.MaxUses {
+		return entity.PromoCode{}, apperror.New(apperror.CodePromoInvalid, "promo code has been used up", nil)
+	}
+	if p.MaxUsesPerUser != nil {
+		n, err := q.CountPromoRedemptions(ctx, txx, p.ID, buyerID)
+		if err != nil {
+			return entity.PromoCode{}, err
+		}
+		if n >= *p.MaxUsesPerUser {
+			return entity.PromoCode{}, apperror.New(apperror.CodePromoInvalid, "you have already used this promo code", nil)
+		}
+	}
+	return p, nil
+}
@@ -2,11 +2,13 @@ package ticket
 
 import (
 	"context"
+	"fmt"
 	"time"
 
-	"time2meet/internal/application/port/auditctx"
+	"time2meet/internal/application/authz"
+	"time2meet/internal/application/port/qrtoken"
 	"time2meet/internal/application/port/tickettx"
-	"time2meet/internal/application/tx"
+	"time2meet/internal/domain/entity"
 	"time2meet/internal/domain/valueobject"
 	"time2meet/pkg/apperror"
 
@@ -15,72 +17,178 @@ import (
 )
 
 type PurchaseUseCase struct {
-	tx    tx.Manager
-	audit auditctx.Setter
-	q     tickettx.Queries
+	q   tickettx.Queries
+	qr  qrtoken.Signer
+	pay *PaymentProcessor
 }
 
-func NewPurchase(txm tx.Manager, audit auditctx.Setter, q tickettx.Queries) *PurchaseUseCase {
-	return &PurchaseUseCase{tx: txm, audit: audit, q: q}
+func NewPurchase(q tickettx.Queries, qr qrtoken.Signer, pay *PaymentProcessor) *PurchaseUseCase {
+	return &PurchaseUseCase{q: q, qr: qr, pay: pay}
 }
 
 type PurchaseInput struct {
-	UserID       valueobject.UUID
+	Actor        authz.Actor
 	IP           string
 	TicketTypeID valueobject.UUID
-	QRCode       string
-	AmountPaid   string // decimal string
-	Currency     string
+	// PromoCode is optional, as in PlaceOrderInput.
+	PromoCode string
+	// ExpectedAmount is optional: when set, the purchase fails with a conflict if the
+	// server-computed price differs (e.g. the price changed after the client rendered it).
+	ExpectedAmount string // decimal string
+	Currency       string
 }
 
+// PurchaseOutput describes the single ticket bought. Purchases are recorded as one-line orders.
 type PurchaseOutput struct {
-	TicketID valueobject.UUID
+	OrderID valueobject.UUID
+	// Status is pending_payment when the payment outcome is not known yet.
+	Status         valueobject.OrderStatus
+	TicketID       valueobject.UUID
+	QRCode         string
+	AmountPaid     valueobject.Money
+	DiscountAmount valueobject.Money
+	Currency       valueobject.Currency
 }
 
 func (uc *PurchaseUseCase) Purchase(ctx context.Context, in PurchaseInput) (PurchaseOutput, error) {
-	if in.UserID == valueobject.Nil {
-		return PurchaseOutput{}, apperror.New(apperror.CodeValidation, "user_id is required", nil)
+	if in.Actor.IsAnonymous() {
+		return PurchaseOutput{}, apperror.New(apperror.CodeUnauthorized, "authentication required", nil)
 	}
 	if in.TicketTypeID == valueobject.Nil {
 		return PurchaseOutput{}, apperror.New(apperror.CodeValidation, "ticket_type_id is required", nil)
 	}
-	if in.QRCode == "" {
-		return PurchaseOutput{}, apperror.New(apperror.CodeValidation, "qr_code is required", nil)
-	}
-	amt, err := decimal.NewFromString(in.AmountPaid)
-	if err != nil {
-		return PurchaseOutput{}, apperror.New(apperror.CodeValidation, "amount_paid must be decimal string", err)
-	}
-	money, err := valueobject.NewMoney(amt)
-	if err != nil {
-		return PurchaseOutput{}, apperror.New(apperror.CodeValidation, "invalid money", err)
-	}
-
-	var out PurchaseOutput
-	err = uc.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
-		if err := uc.audit.Set(ctx, txx, in.UserID, in.IP); err != nil {
-			return err
-		}
-
-		qtyTotal, qtySold, err := uc.q.LockTicketTypeForUpdate(ctx, txx, in.TicketTypeID)
+	var expected *decimal.Decimal
+	if in.ExpectedAmount != "" {
+		amt, err := decimal.NewFromString(in.ExpectedAmount)
 		if err != nil {
-			return err
+			return PurchaseOutput{}, apperror.New(apperror.CodeValidation, "amount_paid must be decimal string", err)
 		}
-		if qtySold >= qtyTotal {
-			return apperror.New(apperror.CodeConflict, "sold out", nil)
+		expected = &amt
+	}
+	var currency valueobject.Currency
+	if in.Currency != "" {
+		c, err := valueobject.ParseCurrency(in.Currency)
+		if err != nil {
+			return PurchaseOutput{}, apperror.New(apperror.CodeValidation, "invalid currency", err)
 		}
+		currency = c
+	}
 
-		ticketID, err := uc.q.InsertPaidTicket(ctx, txx, in.TicketTypeID, in.UserID, time.Now().UTC(), in.QRCode, money.Amount.StringFixed(2))
+	res, err := uc.pay.checkout(ctx, in.Actor, in.IP, func(ctx context.Context, txx *sqlx.Tx) (OrderOutput, error) {
+		lines := []OrderLine{{TicketTypeID: in.TicketTypeID, Quantity: 1}}
+		res, err := placeOrder(ctx, txx, uc.q, uc.qr, valueobject.NewUUID(), in.Actor.UserID, in.IP, lines, currency, in.PromoCode, time.Now().UTC())
 		if err != nil {
-			return err
+			return OrderOutput{}, err
 		}
-		out.TicketID = ticketID
-		return nil
+		if t := res.Tickets[0]; expected != nil && !expected.Equal(t.AmountPaid.Amount) {
+			return OrderOutput{}, apperror.New(apperror.CodeConflict, fmt.Sprintf("price mismatch: current price is %s %s", t.AmountPaid.Amount.StringFixed(2), res.Order.Currency), nil)
+		}
+		return res, nil
 	})
 	if err != nil {
 		return PurchaseOutput{}, err
 	}
-	return out, nil
+	t := res.Tickets[0]
+	return PurchaseOutput{
+		OrderID:        res.Order.ID,
+		Status:         res.Order.Status,
+		TicketID:       t.ID,
+		QRCode:         t.QRCode,
+		AmountPaid:     t.AmountPaid,
+		DiscountAmount: t.DiscountAmount,
+		Currency:       res.Order.Currency,
+	}, nil
+}
+
+// checkOnSale reports why a locked ticket type cannot be sold at the given moment.
+func checkOnSale(tt tickettx.LockedTicketType, now time.Time) error {
+	if err := checkSaleOpen(tt, now); err != nil {
+		return err
+	}
+	if tt.Available() <= 0 {
+		return apperror.New(apperror.CodeSoldOut, "sold out", nil)
+	}
+	return nil
+}
+
+// checkSaleOpen is checkOnSale without the inventory check: the ticket type may be sold at the
+// given moment, whether or not any tickets are left.
+func checkSaleOpen(tt tickettx.LockedTicketType, now time.Time) error {
+	if tt.EventStatus != valueobject.EventStatusPublished {
+		return apperror.New(apperror.CodeEventNotOnSale, fmt.Sprintf("event is %s", tt.EventStatus), nil)
+	}
+	if !tt.IsActive {
+		return apperror.New(apperror.CodeTicketTypeInactive, "ticket type is not available for sale", nil)
+	}
+	if tt.SaleStart != nil && now.Before(*tt.SaleStart) {
+		return apperror.New(apperror.CodeSaleNotStarted, fmt.Sprintf("sale starts at %s", tt.SaleStart.UTC().Format(time.RFC3339)), nil)
+	}
+	if tt.SaleEnd != nil && !now.Before(*tt.SaleEnd) {
+		return apperror.New(apperror.CodeSaleEnded, fmt.Sprintf("sale ended at %s", tt.SaleEnd.UTC().Format(time.RFC3339)), nil)
+	}
+	return nil
+}
+
+// checkWaitlist keeps freed tickets for the waitlist: while a waiting entry fits the available
+// tickets, only holds already reserved (e.g. waitlist offers) may be turned into tickets. Tickets
+// too few for every waiting entry stay on sale, so a large entry cannot freeze the type.
+func checkWaitlist(tt tickettx.LockedTicketType) error {
+	if waitlistClaims(tt) {
+		return apperror.New(apperror.CodeSoldOut, "tickets are reserved for the waitlist", nil).
+			WithDetails(map[string]any{"ticket_type_id": tt.ID.String(), "waitlist_waiting": tt.WaitlistWaiting})
+	}
+	return nil
+}
+
+// waitlistClaims reports whether the available tickets are owed to a waiting entry.
+func waitlistClaims(tt tickettx.LockedTicketType) bool {
+	return tt.WaitlistWaiting > 0 && tt.WaitlistMinQuantity <= tt.Available()
+}
+
+// checkPurchaseLimits enforces the ticket type's per-order and per-buyer limits on qty more
+// tickets. The caller holds the ticket type's lock, so concurrent checkouts of one buyer cannot
+// both slip under the per-buyer limit.
+func checkPurchaseLimits(ctx context.Context, txx *sqlx.Tx, q tickettx.Queries, tt tickettx.LockedTicketType, buyerID valueobject.UUID, qty int) error {
+	if tt.MaxPerOrder != nil && qty > *tt.MaxPerOrder {
+		return apperror.New(apperror.CodePurchaseLimit, fmt.Sprintf("at most %d tickets of this type per order", *tt.MaxPerOrder), nil).
+			WithDetails(map[string]any{"ticket_type_id": tt.ID.String(), "max_per_order": *tt.MaxPerOrder})
+	}
+	if tt.MaxPerUser == nil {
+		return nil
+	}
+	n, err := q.CountBuyerTickets(ctx, txx, buyerID, tt.ID)
+	if err != nil {
+		return err
+	}
+	if n+qty > *tt.MaxPerUser {
+		remaining := max(*tt.MaxPerUser-n, 0)
+		return apperror.New(apperror.CodePurchaseLimit, fmt.Sprintf("at most %d tickets of this type per buyer; %d more allowed", *tt.MaxPerUser, remaining), nil).
+			WithDetails(map[string]any{"ticket_type_id": tt.ID.String(), "max_per_user": *tt.MaxPerUser, "remaining": remaining})
+	}
+	return nil
 }
 
+// quotedQuantity is part of an order line charged at one price.
+type quotedQuantity struct {
+	Quantity int
+	// Price is charged per ticket; Discount is what the promo code took off each one.
+	Price    valueobject.Money
+	Discount valueobject.Money
+}
 
+// quote prices quantity tickets from the locked ticket type row: the price tier is resolved
+// from the tickets already taken, then the promo code, if any, is applied. Every discount must
+// be applied here so that amount_paid is always server-derived.
+func quote(tt tickettx.LockedTicketType, quantity int, promo *entity.PromoCode, now time.Time) []quotedQuantity {
+	parts := tt.PriceTiers.Split(tt.Price, tt.Taken(), quantity, now)
+	out := make([]quotedQuantity, 0, len(parts))
+	for _, p := range parts {
+		q := quotedQuantity{Quantity: p.Quantity, Price: p.Price, Discount: valueobject.Money{Amount: decimal.Zero}}
+		if promo != nil && promo.AppliesTo(tt.EventID, tt.ID, tt.Currency) {
+			q.Discount = promo.DiscountOn(p.Price)
+			q.Price = valueobject.Money{Amount: p.Price.Amount.Sub(q.Discount.Amount)}
+		}
+		out = append(out, q)
+	}
+	return out
+}
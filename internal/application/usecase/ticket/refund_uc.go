@@ -0,0 +1,201 @@
+package ticket
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"time2meet/internal/application/authz"
+	"time2meet/internal/application/port/auditctx"
+	"time2meet/internal/application/port/tickettx"
+	"time2meet/internal/application/tx"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// RefundUseCase returns tickets under their ticket type's refund policy. A ticket stays paid
+// while the money is returned through the payment provider and is only released once the
+// provider confirms; tickets not charged through a provider are released right away.
+type RefundUseCase struct {
+	tx       tx.Manager
+	audit    auditctx.Setter
+	q        tickettx.Queries
+	tickets  repository.TicketRepository
+	refunds  repository.RefundRepository
+	payments repository.PaymentRepository
+	pay      *PaymentProcessor
+}
+
+func NewRefund(txm tx.Manager, audit auditctx.Setter, q tickettx.Queries, tickets repository.TicketRepository, refunds repository.RefundRepository, payments repository.PaymentRepository, pay *PaymentProcessor) *RefundUseCase {
+	return &RefundUseCase{tx: txm, audit: audit, q: q, tickets: tickets, refunds: refunds, payments: payments, pay: pay}
+}
+
+type RefundInput struct {
+	Actor    authz.Actor
+	IP       string
+	TicketID valueobject.UUID
+	// Amount is a decimal string; empty means the most the policy allows.
+	Amount string
+	Reason string
+}
+
+// Refund returns a paid ticket. A declined refund is returned as CodePaymentFailed and leaves
+// the ticket paid, so it may be requested again; when the provider's answer is unknown the
+// refund is returned in pending and settles during reconciliation. Only one refund of a
+// ticket can be pending at a time.
+func (uc *RefundUseCase) Refund(ctx context.Context, in RefundInput) (entity.Refund, error) {
+	t, err := uc.tickets.GetByID(ctx, in.TicketID)
+	if err != nil {
+		return entity.Refund{}, err
+	}
+	if err := authz.RequireSelfOrAdmin(in.Actor, t.BuyerID); err != nil {
+		return entity.Refund{}, err
+	}
+	if t.Comp {
+		return entity.Refund{}, apperror.New(apperror.CodeRefundNotAllowed, "complimentary tickets cannot be refunded", nil)
+	}
+	// The money goes back to the purchaser, so a ticket given away cannot be refunded by them.
+	if t.HolderID != t.BuyerID && !in.Actor.IsAdmin() {
+		return entity.Refund{}, apperror.New(apperror.CodeRefundNotAllowed, "ticket has been transferred", nil)
+	}
+	var requested *valueobject.Money
+	if s := strings.TrimSpace(in.Amount); s != "" {
+		amt, err := decimal.NewFromString(s)
+		if err != nil || !amt.IsPositive() || amt.Exponent() < -2 {
+			return entity.Refund{}, apperror.New(apperror.CodeValidation, "amount must be a positive amount with at most 2 decimal places", err)
+		}
+		m, _ := valueobject.NewMoney(amt)
+		requested = &m
+	}
+	var pay *entity.Payment
+	if t.OrderID != nil {
+		p, err := uc.payments.GetByOrderID(ctx, *t.OrderID)
+		var ae *apperror.AppError
+		switch {
+		case err == nil:
+			pay = &p
+		case !errors.As(err, &ae) || ae.Code != apperror.CodeNotFound:
+			return entity.Refund{}, err
+		}
+	}
+
+	now := time.Now().UTC()
+	var r entity.Refund
+	err = uc.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
+		if err := uc.audit.Set(ctx, txx, in.Actor.UserID, in.IP); err != nil {
+			return err
+		}
+		// Ticket type first: settling an order locks its ticket types before its tickets.
+		tt, err := uc.q.LockTicketTypeForUpdate(ctx, txx, t.TicketTypeID)
+		if err != nil {
+			return err
+		}
+		lt, err := uc.q.LockTicketForUpdate(ctx, txx, t.ID)
+		if err != nil {
+			return err
+		}
+		if lt.Status != valueobject.TicketStatusPaid {
+			return apperror.New(apperror.CodeInvalidState, "ticket is "+string(lt.Status), nil)
+		}
+		limit, err := refundableAmount(tt, lt, in.Actor, now)
+		if err != nil {
+			return err
+		}
+		amount := limit
+		if requested != nil {
+			if requested.Amount.GreaterThan(limit.Amount) {
+				return apperror.New(apperror.CodeValidation, "amount exceeds the refundable amount", nil).
+					WithDetails(map[string]any{"refundable": limit.Amount.StringFixed(2)})
+			}
+			amount = *requested
+		}
+		r = entity.Refund{
+			ID:          valueobject.NewUUID(),
+			TicketID:    lt.ID,
+			OrderID:     lt.OrderID,
+			Amount:      amount,
+			Currency:    tt.Currency,
+			Status:      valueobject.RefundStatusSucceeded,
+			Reason:      strings.TrimSpace(in.Reason),
+			RequestedBy: in.Actor.UserID,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		// Nothing to send back when nothing was charged through the provider.
+		if amount.Amount.IsPositive() && pay != nil {
+			if pay.Status != valueobject.PaymentStatusSucceeded {
+				return apperror.New(apperror.CodeInvalidState, "order payment is "+string(pay.Status), nil)
+			}
+			r.PaymentID = &pay.ID
+			r.Status = valueobject.RefundStatusPending
+		}
+		if err := uc.q.InsertRefund(ctx, txx, r); err != nil {
+			return err
+		}
+		if r.Status == valueobject.RefundStatusPending {
+			return nil // released by the PaymentProcessor once the provider confirms
+		}
+		ok, err := uc.q.SetTicketStatus(ctx, txx, lt.ID, valueobject.TicketStatusPaid, valueobject.TicketStatusRefunded)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return apperror.New(apperror.CodeInvalidState, "ticket is no longer paid", nil)
+		}
+		return nil
+	})
+	if err != nil {
+		return entity.Refund{}, err
+	}
+	if r.Status != valueobject.RefundStatusPending {
+		return r, nil
+	}
+
+	r.Status = uc.pay.settleRefund(ctx, in.Actor.UserID, in.IP, r, pay.IntentID)
+	if r.Status == valueobject.RefundStatusFailed {
+		return entity.Refund{}, apperror.New(apperror.CodePaymentFailed, "refund was declined", nil).
+			WithDetails(map[string]any{"refund_id": r.ID.String()})
+	}
+	return r, nil
+}
+
+func (uc *RefundUseCase) ListByTicket(ctx context.Context, actor authz.Actor, ticketID valueobject.UUID) ([]entity.Refund, error) {
+	t, err := uc.tickets.GetByID(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authz.RequireSelfOrAdmin(actor, t.BuyerID); err != nil {
+		return nil, err
+	}
+	return uc.refunds.ListByTicketID(ctx, ticketID)
+}
+
+// refundableAmount is the most that may be returned for the ticket now. Admins may refund up
+// to the amount paid regardless of the policy, e.g. when an event is called off. Without a
+// scheduled session there is no deadline.
+func refundableAmount(tt tickettx.LockedTicketType, t tickettx.LockedTicket, actor authz.Actor, now time.Time) (valueobject.Money, error) {
+	if actor.IsAdmin() {
+		return t.AmountPaid, nil
+	}
+	if tt.RefundPolicy == valueobject.RefundPolicyNone {
+		return valueobject.Money{}, apperror.New(apperror.CodeRefundNotAllowed, "ticket type does not allow refunds", nil)
+	}
+	if tt.EventStartsAt != nil {
+		deadline := tt.EventStartsAt.AddDate(0, 0, -tt.RefundDeadlineDays)
+		if !now.Before(deadline) {
+			return valueobject.Money{}, apperror.New(apperror.CodeRefundNotAllowed, "refund deadline has passed", nil).
+				WithDetails(map[string]any{"deadline": deadline.UTC()})
+		}
+	}
+	if tt.RefundPolicy == valueobject.RefundPolicyPartial {
+		share := t.AmountPaid.Amount.Mul(decimal.NewFromInt(int64(tt.RefundPercent))).Div(decimal.NewFromInt(100))
+		return valueobject.NewMoney(share.RoundDown(2))
+	}
+	return t.AmountPaid, nil
+}
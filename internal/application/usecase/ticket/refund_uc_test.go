@@ -0,0 +1,286 @@
+package ticket
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"time2meet/internal/application/authz"
+	"time2meet/internal/application/port/payment"
+	"time2meet/internal/application/port/qrtoken"
+	"time2meet/internal/application/port/tickettx"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/internal/infrastructure/fakepay"
+	"time2meet/internal/infrastructure/qrsign"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+func money(s string) valueobject.Money {
+	return valueobject.Money{Amount: decimal.RequireFromString(s)}
+}
+
+func TestRefundableAmount(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	in := func(d time.Duration) *time.Time { at := now.Add(d); return &at }
+	attendee := authz.Actor{UserID: valueobject.NewUUID(), Role: entity.UserRoleAttendee}
+	admin := authz.Actor{UserID: valueobject.NewUUID(), Role: entity.UserRoleAdmin}
+	ticket := tickettx.LockedTicket{AmountPaid: money("33.33")}
+
+	tests := []struct {
+		name    string
+		actor   authz.Actor
+		tt      tickettx.LockedTicketType
+		want    string
+		wantErr apperror.Code
+	}{
+		{name: "full", actor: attendee, tt: tickettx.LockedTicketType{RefundPolicy: valueobject.RefundPolicyFull}, want: "33.33"},
+		{name: "partial rounds down", actor: attendee, tt: tickettx.LockedTicketType{RefundPolicy: valueobject.RefundPolicyPartial, RefundPercent: 50}, want: "16.66"},
+		{name: "partial at zero percent", actor: attendee, tt: tickettx.LockedTicketType{RefundPolicy: valueobject.RefundPolicyPartial}, want: "0"},
+		{name: "none", actor: attendee, tt: tickettx.LockedTicketType{RefundPolicy: valueobject.RefundPolicyNone}, wantErr: apperror.CodeRefundNotAllowed},
+		{name: "before the deadline", actor: attendee, tt: tickettx.LockedTicketType{RefundPolicy: valueobject.RefundPolicyFull, RefundDeadlineDays: 2, EventStartsAt: in(49 * time.Hour)}, want: "33.33"},
+		{name: "at the deadline", actor: attendee, tt: tickettx.LockedTicketType{RefundPolicy: valueobject.RefundPolicyFull, RefundDeadlineDays: 2, EventStartsAt: in(48 * time.Hour)}, wantErr: apperror.CodeRefundNotAllowed},
+		{name: "event started, no deadline days", actor: attendee, tt: tickettx.LockedTicketType{RefundPolicy: valueobject.RefundPolicyFull, EventStartsAt: in(-time.Minute)}, wantErr: apperror.CodeRefundNotAllowed},
+		{name: "no session scheduled", actor: attendee, tt: tickettx.LockedTicketType{RefundPolicy: valueobject.RefundPolicyPartial, RefundPercent: 10, RefundDeadlineDays: 30}, want: "3.33"},
+		{name: "admin overrides none", actor: admin, tt: tickettx.LockedTicketType{RefundPolicy: valueobject.RefundPolicyNone}, want: "33.33"},
+		{name: "admin overrides partial after the deadline", actor: admin, tt: tickettx.LockedTicketType{RefundPolicy: valueobject.RefundPolicyPartial, RefundPercent: 50, RefundDeadlineDays: 7, EventStartsAt: in(-time.Hour)}, want: "33.33"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := refundableAmount(tc.tt, ticket, tc.actor, now)
+			if tc.wantErr != "" {
+				var ae *apperror.AppError
+				if !errors.As(err, &ae) || ae.Code != tc.wantErr {
+					t.Fatalf("err = %v, want %s", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("refundableAmount: %v", err)
+			}
+			if !got.Amount.Equal(decimal.RequireFromString(tc.want)) {
+				t.Errorf("refundable = %s, want %s", got.Amount, tc.want)
+			}
+		})
+	}
+}
+
+// refundFixture is one paid ticket of a charged order; its payment and refunds live in l.
+type refundFixture struct {
+	tickettx.Queries
+	ticket tickettx.LockedTicket
+	l      *paymentLedger
+}
+
+func (f *refundFixture) LockTicketTypeForUpdate(context.Context, *sqlx.Tx, valueobject.UUID) (tickettx.LockedTicketType, error) {
+	return tickettx.LockedTicketType{ID: f.ticket.TicketTypeID, Currency: valueobject.DefaultCurrency, RefundPolicy: valueobject.RefundPolicyFull}, nil
+}
+
+func (f *refundFixture) LockTicketForUpdate(context.Context, *sqlx.Tx, valueobject.UUID) (tickettx.LockedTicket, error) {
+	t := f.ticket
+	for _, r := range f.l.refunds {
+		if r.TicketID == t.ID && r.Status == valueobject.RefundStatusPending {
+			t.RefundPending = true
+		}
+	}
+	return t, nil
+}
+
+func (f *refundFixture) InsertRefund(_ context.Context, _ *sqlx.Tx, r entity.Refund) error {
+	for _, open := range f.l.refunds {
+		if open.TicketID == r.TicketID && open.Status != valueobject.RefundStatusFailed {
+			return apperror.New(apperror.CodeConflict, "ticket already has an open refund", nil)
+		}
+	}
+	f.l.refunds[r.ID] = r
+	return nil
+}
+
+func (f *refundFixture) FinishRefund(_ context.Context, _ *sqlx.Tx, id valueobject.UUID, status valueobject.RefundStatus, _, reason string) (bool, error) {
+	r := f.l.refunds[id]
+	if r.Status != valueobject.RefundStatusPending {
+		return false, nil
+	}
+	r.Status, r.FailureReason = status, reason
+	f.l.refunds[id] = r
+	return true, nil
+}
+
+func (f *refundFixture) ReleaseRefundedTicket(_ context.Context, _ *sqlx.Tx, id valueobject.UUID) (bool, error) {
+	return f.SetTicketStatus(context.Background(), nil, id, valueobject.TicketStatusPaid, valueobject.TicketStatusRefunded)
+}
+
+func (f *refundFixture) SetTicketStatus(_ context.Context, _ *sqlx.Tx, _ valueobject.UUID, from, to valueobject.TicketStatus) (bool, error) {
+	if f.ticket.Status != from {
+		return false, nil
+	}
+	f.ticket.Status = to
+	return true, nil
+}
+
+func (f *refundFixture) MarkTicketUsed(context.Context, *sqlx.Tx, valueobject.UUID, time.Time, *valueobject.UUID, *valueobject.UUID, int) (bool, error) {
+	f.ticket.Status = valueobject.TicketStatusUsed
+	f.ticket.EntryCount++
+	return true, nil
+}
+
+func (f *refundFixture) InsertCheckin(context.Context, *sqlx.Tx, entity.Checkin) error {
+	return nil
+}
+
+type refundFixtureTickets struct {
+	repository.TicketRepository
+	f *refundFixture
+}
+
+func (r refundFixtureTickets) GetByID(context.Context, valueobject.UUID) (entity.Ticket, error) {
+	t := r.f.ticket
+	return entity.Ticket{ID: t.ID, TicketTypeID: t.TicketTypeID, BuyerID: t.BuyerID, HolderID: t.HolderID, OrderID: t.OrderID, Status: t.Status}, nil
+}
+
+type fakeCheckins struct {
+	repository.CheckinRepository
+	logged []entity.Checkin
+}
+
+func (f *fakeCheckins) Create(_ context.Context, c entity.Checkin) (valueobject.UUID, error) {
+	f.logged = append(f.logged, c)
+	return valueobject.NewUUID(), nil
+}
+
+// newRefundFixture charges a 25.00 order of one ticket through gw.
+func newRefundFixture(t *testing.T, gw *fakepay.Gateway) (*refundFixture, *RefundUseCase, *PaymentProcessor) {
+	t.Helper()
+	ctx := context.Background()
+	orderID, buyer := valueobject.NewUUID(), valueobject.NewUUID()
+	it, err := gw.CreateIntent(ctx, payment.CreateIntentInput{OrderID: orderID, Amount: money("25"), Currency: valueobject.DefaultCurrency})
+	if err != nil {
+		t.Fatalf("CreateIntent: %v", err)
+	}
+	if _, err := gw.Capture(ctx, it.ID); err != nil {
+		t.Fatalf("Capture: %v", err)
+	}
+	f := &refundFixture{
+		ticket: tickettx.LockedTicket{
+			ID:           valueobject.NewUUID(),
+			TicketTypeID: valueobject.NewUUID(),
+			EventID:      valueobject.NewUUID(),
+			BuyerID:      buyer,
+			HolderID:     buyer,
+			OrderID:      &orderID,
+			Status:       valueobject.TicketStatusPaid,
+			AmountPaid:   money("25"),
+		},
+		l: newPaymentLedger(),
+	}
+	pay := entity.Payment{ID: valueobject.NewUUID(), OrderID: orderID, IntentID: it.ID, Status: valueobject.PaymentStatusSucceeded, Amount: money("25")}
+	f.l.payments[pay.ID] = pay
+	payments, refunds := fakePaymentRepo{l: f.l}, fakeRefundRepo{l: f.l}
+	p := NewPaymentProcessor(fakeTx{}, fakeAudit{}, f, payments, refunds, gw, VelocityLimits{})
+	uc := NewRefund(fakeTx{}, fakeAudit{}, f, refundFixtureTickets{f: f}, refunds, payments, p)
+	return f, uc, p
+}
+
+func TestRefundKeepsTicketPaidUntilProviderConfirms(t *testing.T) {
+	ctx := context.Background()
+	gw := fakepay.New(fakepay.ModeSucceed, "")
+	f, uc, p := newRefundFixture(t, gw)
+	gw.SetMode(fakepay.ModeTimeout)
+	in := RefundInput{Actor: authz.Actor{UserID: f.ticket.BuyerID, Role: entity.UserRoleAttendee}, TicketID: f.ticket.ID}
+
+	r, err := uc.Refund(ctx, in)
+	if err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	if r.Status != valueobject.RefundStatusPending || f.ticket.Status != valueobject.TicketStatusPaid {
+		t.Fatalf("refund %s, ticket %s after a timed-out refund; want pending and paid", r.Status, f.ticket.Status)
+	}
+	if _, err := uc.Refund(ctx, in); err == nil {
+		t.Fatal("a second refund was accepted while the first is pending")
+	}
+
+	res, err := p.Reconcile(ctx, 0, 10)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if res.RefundsSucceeded != 1 {
+		t.Fatalf("reconcile result %+v, want one refund succeeded", res)
+	}
+	if got := f.l.refunds[r.ID].Status; got != valueobject.RefundStatusSucceeded {
+		t.Errorf("refund is %s, want succeeded", got)
+	}
+	if f.ticket.Status != valueobject.TicketStatusRefunded {
+		t.Errorf("ticket is %s, want refunded", f.ticket.Status)
+	}
+}
+
+func TestDeclinedRefundLeavesTicketPaidForRetry(t *testing.T) {
+	ctx := context.Background()
+	gw := fakepay.New(fakepay.ModeSucceed, "")
+	f, uc, _ := newRefundFixture(t, gw)
+	gw.SetMode(fakepay.ModeFail)
+	in := RefundInput{Actor: authz.Actor{UserID: f.ticket.BuyerID, Role: entity.UserRoleAttendee}, TicketID: f.ticket.ID}
+
+	_, err := uc.Refund(ctx, in)
+	var ae *apperror.AppError
+	if !errors.As(err, &ae) || ae.Code != apperror.CodePaymentFailed {
+		t.Fatalf("err = %v, want payment_failed", err)
+	}
+	if f.ticket.Status != valueobject.TicketStatusPaid {
+		t.Fatalf("ticket is %s after a declined refund, want paid", f.ticket.Status)
+	}
+
+	gw.SetMode(fakepay.ModeSucceed)
+	r, err := uc.Refund(ctx, in)
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if r.Status != valueobject.RefundStatusSucceeded || f.ticket.Status != valueobject.TicketStatusRefunded {
+		t.Errorf("retry left refund %s and ticket %s, want succeeded and refunded", r.Status, f.ticket.Status)
+	}
+}
+
+// TestPendingRefundBlocksScansAndTransfers keeps a ticket whose refund awaits the provider from
+// being admitted or handed over while it still shows as paid.
+func TestPendingRefundBlocksScansAndTransfers(t *testing.T) {
+	ctx := context.Background()
+	gw := fakepay.New(fakepay.ModeSucceed, "")
+	f, uc, _ := newRefundFixture(t, gw)
+	gw.SetMode(fakepay.ModeTimeout)
+	if _, err := uc.Refund(ctx, RefundInput{Actor: authz.Actor{UserID: f.ticket.BuyerID, Role: entity.UserRoleAttendee}, TicketID: f.ticket.ID}); err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+
+	qr := qrsign.NewHMACSigner(map[string]string{"k1": "secret"}, "k1")
+	code, err := qr.Sign(qrtoken.Payload{TicketID: f.ticket.ID, EventID: f.ticket.EventID, IssuedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	f.ticket.QRCode = code
+	checkins := &fakeCheckins{}
+	validate := NewValidate(fakeTx{}, fakeAudit{}, f, checkins, qr, authz.NewEventGuard(nil))
+	device := authz.Device{ID: valueobject.NewUUID(), EventID: f.ticket.EventID}
+
+	_, err = validate.Validate(ctx, ValidateInput{Actor: authz.Actor{Device: &device}, Payload: code})
+	var ae *apperror.AppError
+	if !errors.As(err, &ae) || ae.Code != apperror.CodeInvalidState {
+		t.Fatalf("scan during a pending refund: err = %v, want invalid_state", err)
+	}
+	if f.ticket.Status != valueobject.TicketStatusPaid {
+		t.Fatalf("ticket is %s after the scan, want paid", f.ticket.Status)
+	}
+	if len(checkins.logged) != 1 || checkins.logged[0].Result != entity.CheckinResultRejected {
+		t.Errorf("check-in log %v, want one rejected attempt", checkins.logged)
+	}
+
+	transfer := NewTransfer(fakeTx{}, fakeAudit{}, f, nil, nil, nil, qr, time.Hour)
+	_, err = transfer.Initiate(ctx, InitiateTransferInput{Actor: authz.Actor{UserID: f.ticket.HolderID, Role: entity.UserRoleAttendee}, TicketID: f.ticket.ID, ToEmail: "friend@example.com"})
+	if !errors.As(err, &ae) || ae.Code != apperror.CodeInvalidState {
+		t.Fatalf("transfer during a pending refund: err = %v, want invalid_state", err)
+	}
+}
@@ -0,0 +1,260 @@
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"time2meet/internal/application/authz"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/shopspring/decimal"
+)
+
+// maxPriceTiers bounds how many tiers a ticket type may have.
+const maxPriceTiers = 10
+
+type TicketTypeUseCase struct {
+	ticketTypes repository.TicketTypeRepository
+	guard       *authz.EventGuard
+}
+
+func NewTicketTypeUC(ticketTypes repository.TicketTypeRepository, guard *authz.EventGuard) *TicketTypeUseCase {
+	return &TicketTypeUseCase{ticketTypes: ticketTypes, guard: guard}
+}
+
+type TicketTypeInput struct {
+	Name          string
+	Price         string // decimal string; the base price once every tier has run out
+	PriceTiers    []PriceTierInput
+	Currency      string // ISO 4217, defaults to RUB
+	QuantityTotal int
+	SaleStart     *time.Time
+	SaleEnd       *time.Time
+	Description   string
+	IsActive      bool
+	ReentryLimit  int
+	// MaxPerOrder and MaxPerUser are optional purchase limits.
+	MaxPerOrder *int
+	MaxPerUser  *int
+	// RefundPolicy defaults to full; RefundPercent is only used by the partial policy.
+	RefundPolicy       string
+	RefundDeadlineDays int
+	RefundPercent      int
+}
+
+// PriceTierInput is one price tier. Tiers apply in order and need at least one limit.
+type PriceTierInput struct {
+	Price        string // decimal string
+	UpToQuantity *int
+	EndsAt       *time.Time
+}
+
+type CreateTicketTypeInput struct {
+	EventID valueobject.UUID
+	TicketTypeInput
+}
+
+type UpdateTicketTypeInput struct {
+	EventID      valueobject.UUID
+	TicketTypeID valueobject.UUID
+	TicketTypeInput
+}
+
+func (uc *TicketTypeUseCase) Create(ctx context.Context, actor authz.Actor, in CreateTicketTypeInput) (valueobject.UUID, error) {
+	if _, err := uc.guard.RequireEventManager(ctx, actor, in.EventID); err != nil {
+		return valueobject.Nil, err
+	}
+	tt, err := buildTicketType(in.TicketTypeInput)
+	if err != nil {
+		return valueobject.Nil, err
+	}
+	tt.EventID = in.EventID
+	return uc.ticketTypes.Create(ctx, tt)
+}
+
+func (uc *TicketTypeUseCase) Get(ctx context.Context, eventID, ticketTypeID valueobject.UUID) (entity.TicketType, error) {
+	return uc.load(ctx, eventID, ticketTypeID)
+}
+
+func (uc *TicketTypeUseCase) List(ctx context.Context, eventID valueobject.UUID) ([]entity.TicketType, error) {
+	return uc.ticketTypes.ListByEventID(ctx, eventID)
+}
+
+func (uc *TicketTypeUseCase) Update(ctx context.Context, actor authz.Actor, in UpdateTicketTypeInput) error {
+	if _, err := uc.guard.RequireEventManager(ctx, actor, in.EventID); err != nil {
+		return err
+	}
+	cur, err := uc.load(ctx, in.EventID, in.TicketTypeID)
+	if err != nil {
+		return err
+	}
+	tt, err := buildTicketType(in.TicketTypeInput)
+	if err != nil {
+		return err
+	}
+	if taken := cur.QuantitySold + cur.QuantityHeld; tt.QuantityTotal < taken {
+		return apperror.New(apperror.CodeConflict, fmt.Sprintf("quantity_total cannot be less than sold plus held tickets (%d)", taken), nil)
+	}
+	tt.ID = cur.ID
+	tt.EventID = cur.EventID
+	return uc.ticketTypes.Update(ctx, tt)
+}
+
+func (uc *TicketTypeUseCase) Delete(ctx context.Context, actor authz.Actor, eventID, ticketTypeID valueobject.UUID) error {
+	if _, err := uc.guard.RequireEventManager(ctx, actor, eventID); err != nil {
+		return err
+	}
+	cur, err := uc.load(ctx, eventID, ticketTypeID)
+	if err != nil {
+		return err
+	}
+	if cur.QuantitySold > 0 {
+		return apperror.New(apperror.CodeConflict, "ticket type has sold tickets; deactivate it instead", nil)
+	}
+	if cur.QuantityHeld > 0 {
+		return apperror.New(apperror.CodeConflict, "ticket type has active holds; deactivate it instead", nil)
+	}
+	return uc.ticketTypes.Delete(ctx, cur.ID)
+}
+
+// load fetches a ticket type and hides types that belong to a different event.
+func (uc *TicketTypeUseCase) load(ctx context.Context, eventID, ticketTypeID valueobject.UUID) (entity.TicketType, error) {
+	tt, err := uc.ticketTypes.GetByID(ctx, ticketTypeID)
+	if err != nil {
+		return entity.TicketType{}, err
+	}
+	if tt.EventID != eventID {
+		return entity.TicketType{}, apperror.New(apperror.CodeNotFound, "ticket type not found", nil)
+	}
+	return tt, nil
+}
+
+func buildTicketType(in TicketTypeInput) (entity.TicketType, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		return entity.TicketType{}, apperror.New(apperror.CodeValidation, "name is required", nil)
+	}
+	price, err := parsePrice(in.Price, "price")
+	if err != nil {
+		return entity.TicketType{}, err
+	}
+	currency := valueobject.DefaultCurrency
+	if in.Currency != "" {
+		currency, err = valueobject.ParseCurrency(in.Currency)
+		if err != nil {
+			return entity.TicketType{}, apperror.New(apperror.CodeValidation, "invalid currency", err)
+		}
+	}
+	if in.QuantityTotal <= 0 {
+		return entity.TicketType{}, apperror.New(apperror.CodeValidation, "quantity_total must be positive", nil)
+	}
+	tiers, err := buildPriceTiers(in.PriceTiers, in.QuantityTotal)
+	if err != nil {
+		return entity.TicketType{}, err
+	}
+	if in.ReentryLimit < 0 {
+		return entity.TicketType{}, apperror.New(apperror.CodeValidation, "reentry_limit must be >= 0", nil)
+	}
+	if in.MaxPerOrder != nil && *in.MaxPerOrder <= 0 {
+		return entity.TicketType{}, apperror.New(apperror.CodeValidation, "max_per_order must be positive", nil)
+	}
+	if in.MaxPerUser != nil && *in.MaxPerUser <= 0 {
+		return entity.TicketType{}, apperror.New(apperror.CodeValidation, "max_per_user must be positive", nil)
+	}
+	if in.SaleStart != nil && in.SaleEnd != nil && !in.SaleEnd.After(*in.SaleStart) {
+		return entity.TicketType{}, apperror.New(apperror.CodeValidation, "sale_end must be after sale_start", nil)
+	}
+	policy := valueobject.RefundPolicyFull
+	if in.RefundPolicy != "" {
+		policy = valueobject.RefundPolicy(in.RefundPolicy)
+		if err := policy.Validate(); err != nil {
+			return entity.TicketType{}, apperror.New(apperror.CodeValidation, "refund_policy must be full, partial or none", err)
+		}
+	}
+	if in.RefundDeadlineDays < 0 {
+		return entity.TicketType{}, apperror.New(apperror.CodeValidation, "refund_deadline_days must be >= 0", nil)
+	}
+	percent := 100
+	if policy == valueobject.RefundPolicyPartial {
+		if in.RefundPercent <= 0 || in.RefundPercent >= 100 {
+			return entity.TicketType{}, apperror.New(apperror.CodeValidation, "refund_percent must be between 1 and 99 for the partial policy", nil)
+		}
+		percent = in.RefundPercent
+	}
+	return entity.TicketType{
+		Name:          name,
+		Price:         price,
+		PriceTiers:    tiers,
+		Currency:      currency,
+		QuantityTotal: in.QuantityTotal,
+		SaleStart:     in.SaleStart,
+		SaleEnd:       in.SaleEnd,
+		Description:   in.Description,
+		IsActive:      in.IsActive,
+		ReentryLimit:  in.ReentryLimit,
+		MaxPerOrder:   in.MaxPerOrder,
+		MaxPerUser:    in.MaxPerUser,
+
+		RefundPolicy:       policy,
+		RefundDeadlineDays: in.RefundDeadlineDays,
+		RefundPercent:      percent,
+	}, nil
+}
+
+func parsePrice(s, field string) (valueobject.Money, error) {
+	amt, err := decimal.NewFromString(strings.TrimSpace(s))
+	if err != nil {
+		return valueobject.Money{}, apperror.New(apperror.CodeValidation, "invalid "+field, err)
+	}
+	if amt.Exponent() < -2 {
+		return valueobject.Money{}, apperror.New(apperror.CodeValidation, field+" must have at most 2 decimal places", nil)
+	}
+	price, err := valueobject.NewMoney(amt)
+	if err != nil {
+		return valueobject.Money{}, apperror.New(apperror.CodeValidation, "invalid "+field, err)
+	}
+	return price, nil
+}
+
+// buildPriceTiers validates tiers in the order they apply: quantity caps and end dates must
+// grow from tier to tier, otherwise a later tier could never be reached.
+func buildPriceTiers(in []PriceTierInput, quantityTotal int) (entity.PriceTiers, error) {
+	if len(in) > maxPriceTiers {
+		return nil, apperror.New(apperror.CodeValidation, fmt.Sprintf("at most %d price tiers are allowed", maxPriceTiers), nil)
+	}
+	tiers := make(entity.PriceTiers, 0, len(in))
+	var lastCap *int
+	var lastEnd *time.Time
+	for i, t := range in {
+		field := fmt.Sprintf("price_tiers[%d]", i)
+		if t.UpToQuantity == nil && t.EndsAt == nil {
+			return nil, apperror.New(apperror.CodeValidation, field+" needs up_to_quantity or ends_at", nil)
+		}
+		price, err := parsePrice(t.Price, field+".price")
+		if err != nil {
+			return nil, err
+		}
+		if t.UpToQuantity != nil {
+			if *t.UpToQuantity <= 0 || *t.UpToQuantity > quantityTotal {
+				return nil, apperror.New(apperror.CodeValidation, field+".up_to_quantity must be between 1 and quantity_total", nil)
+			}
+			if lastCap != nil && *t.UpToQuantity <= *lastCap {
+				return nil, apperror.New(apperror.CodeValidation, field+".up_to_quantity must be greater than in earlier tiers", nil)
+			}
+			lastCap = t.UpToQuantity
+		}
+		if t.EndsAt != nil {
+			if lastEnd != nil && !t.EndsAt.After(*lastEnd) {
+				return nil, apperror.New(apperror.CodeValidation, field+".ends_at must be after earlier tiers' ends_at", nil)
+			}
+			lastEnd = t.EndsAt
+		}
+		tiers = append(tiers, entity.PriceTier{Price: price, UpToQuantity: t.UpToQuantity, EndsAt: t.EndsAt})
+	}
+	return tiers, nil
+}
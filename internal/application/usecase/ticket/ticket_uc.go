@@ -2,41 +2,125 @@ package ticket
 
 import (
 	"context"
+	"strings"
+	"time"
 
+	"time2meet/internal/application/authz"
+	"time2meet/internal/application/port/auditctx"
+	"time2meet/internal/application/port/tickettx"
+	"time2meet/internal/application/tx"
 	"time2meet/internal/domain/entity"
 	"time2meet/internal/domain/repository"
 	"time2meet/internal/domain/valueobject"
 	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
 )
 
 type TicketUseCase struct {
 	tickets repository.TicketRepository
+	tx      tx.Manager
+	audit   auditctx.Setter
+	q       tickettx.Queries
 }
 
-func NewTicketUC(tickets repository.TicketRepository) *TicketUseCase {
-	return &TicketUseCase{tickets: tickets}
+func NewTicketUC(tickets repository.TicketRepository, txm tx.Manager, audit auditctx.Setter, q tickettx.Queries) *TicketUseCase {
+	return &TicketUseCase{tickets: tickets, tx: txm, audit: audit, q: q}
 }
 
-func (uc *TicketUseCase) Get(ctx context.Context, id valueobject.UUID) (entity.Ticket, error) {
-	return uc.tickets.GetByID(ctx, id)
+func (uc *TicketUseCase) Get(ctx context.Context, actor authz.Actor, id valueobject.UUID) (entity.Ticket, error) {
+	t, err := uc.tickets.GetByID(ctx, id)
+	if err != nil {
+		return entity.Ticket{}, err
+	}
+	if err := authz.RequireSelfOrAdmin(actor, t.HolderID); err != nil {
+		return entity.Ticket{}, err
+	}
+	return t, nil
 }
 
-func (uc *TicketUseCase) ListByBuyer(ctx context.Context, buyerID valueobject.UUID, limit, offset int) ([]entity.Ticket, error) {
-	if buyerID == valueobject.Nil {
-		return nil, apperror.New(apperror.CodeValidation, "buyer_id is required", nil)
+// ListByHolder lists the tickets the caller holds; admins may pass another holder.
+func (uc *TicketUseCase) ListByHolder(ctx context.Context, actor authz.Actor, holderID valueobject.UUID, limit, offset int) ([]entity.Ticket, error) {
+	if holderID == valueobject.Nil {
+		holderID = actor.UserID
+	}
+	if err := authz.RequireSelfOrAdmin(actor, holderID); err != nil {
+		return nil, err
 	}
-	return uc.tickets.ListByBuyerID(ctx, buyerID, limit, offset)
+	return uc.tickets.ListByHolderID(ctx, holderID, limit, offset)
 }
 
-func (uc *TicketUseCase) UpdateStatus(ctx context.Context, id valueobject.UUID, status string) error {
-	if id == valueobject.Nil {
+type UpdateStatusInput struct {
+	Actor    authz.Actor
+	IP       string
+	TicketID valueobject.UUID
+	Status   string
+	Reason   string
+}
+
+// UpdateStatus is the manual override for admins. Only transitions allowed by
+// TicketStatus.CanTransitionTo are accepted, and each change is recorded with its reason.
+func (uc *TicketUseCase) UpdateStatus(ctx context.Context, in UpdateStatusInput) error {
+	if err := authz.RequireRole(in.Actor, entity.UserRoleAdmin); err != nil {
+		return err
+	}
+	if in.TicketID == valueobject.Nil {
 		return apperror.New(apperror.CodeValidation, "id is required", nil)
 	}
-	return uc.tickets.UpdateStatus(ctx, id, status)
+	to := valueobject.TicketStatus(in.Status)
+	if err := to.Validate(); err != nil {
+		return apperror.New(apperror.CodeValidation, "invalid ticket status", err)
+	}
+	// Refunds must go through RefundUseCase so the policy is applied and the money recorded.
+	if to == valueobject.TicketStatusRefunded {
+		return apperror.New(apperror.CodeValidation, "use POST /tickets/{id}/refund to refund a ticket", nil)
+	}
+	reason := strings.TrimSpace(in.Reason)
+	if reason == "" {
+		return apperror.New(apperror.CodeValidation, "reason is required", nil)
+	}
+	t, err := uc.tickets.GetByID(ctx, in.TicketID)
+	if err != nil {
+		return err
+	}
+	// Only the PaymentProcessor settles an unpaid ticket, once the provider has answered.
+	if t.Status == valueobject.TicketStatusPendingPayment {
+		return apperror.New(apperror.CodeInvalidState, "ticket is awaiting payment", nil)
+	}
+	if !t.Status.CanTransitionTo(to) {
+		return apperror.New(apperror.CodeInvalidState, "ticket cannot move from "+string(t.Status)+" to "+string(to), nil).
+			WithDetails(map[string]any{"from": t.Status, "to": to})
+	}
+	return uc.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
+		if err := uc.audit.Set(ctx, txx, in.Actor.UserID, in.IP); err != nil {
+			return err
+		}
+		// The sales trigger updates the ticket type, so lock it first as every other writer does.
+		if _, err := uc.q.LockTicketTypeForUpdate(ctx, txx, t.TicketTypeID); err != nil {
+			return err
+		}
+		ok, err := uc.q.SetTicketStatus(ctx, txx, t.ID, t.Status, to)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return apperror.New(apperror.CodeConflict, "ticket status changed concurrently; reload and retry", nil)
+		}
+		return uc.q.InsertTicketStatusChange(ctx, txx, entity.TicketStatusChange{
+			ID:        valueobject.NewUUID(),
+			TicketID:  t.ID,
+			From:      t.Status,
+			To:        to,
+			Reason:    reason,
+			ChangedBy: in.Actor.UserID,
+			CreatedAt: time.Now().UTC(),
+		})
+	})
 }
 
-func (uc *TicketUseCase) Delete(ctx context.Context, id valueobject.UUID) error {
+func (uc *TicketUseCase) Delete(ctx context.Context, actor authz.Actor, id valueobject.UUID) error {
+	if err := authz.RequireRole(actor, entity.UserRoleAdmin); err != nil {
+		return err
+	}
 	return uc.tickets.Delete(ctx, id)
 }
-
-
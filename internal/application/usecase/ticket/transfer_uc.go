@@ -0,0 +1,245 @@
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"time2meet/internal/application/authz"
+	"time2meet/internal/application/port/auditctx"
+	"time2meet/internal/application/port/qrtoken"
+	"time2meet/internal/application/port/tickettx"
+	"time2meet/internal/application/tx"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// TransferUseCase hands tickets from their holder to another user. The holder offers the
+// ticket to an email address; the user registered with that address accepts before the
+// offer expires. buyer_id never changes, so sales are still counted against the purchaser.
+type TransferUseCase struct {
+	tx        tx.Manager
+	audit     auditctx.Setter
+	q         tickettx.Queries
+	tickets   repository.TicketRepository
+	transfers repository.TicketTransferRepository
+	users     repository.UserRepository
+	qr        qrtoken.Signer
+	ttl       time.Duration
+}
+
+func NewTransfer(txm tx.Manager, audit auditctx.Setter, q tickettx.Queries, tickets repository.TicketRepository, transfers repository.TicketTransferRepository, users repository.UserRepository, qr qrtoken.Signer, ttl time.Duration) *TransferUseCase {
+	return &TransferUseCase{tx: txm, audit: audit, q: q, tickets: tickets, transfers: transfers, users: users, qr: qr, ttl: ttl}
+}
+
+type InitiateTransferInput struct {
+	Actor    authz.Actor
+	IP       string
+	TicketID valueobject.UUID
+	ToEmail  string
+}
+
+// Initiate offers a paid ticket to the given email. The ticket stays usable by its holder
+// until the transfer is accepted.
+func (uc *TransferUseCase) Initiate(ctx context.Context, in InitiateTransferInput) (entity.TicketTransfer, error) {
+	if in.Actor.IsAnonymous() {
+		return entity.TicketTransfer{}, apperror.New(apperror.CodeUnauthorized, "authentication required", nil)
+	}
+	email, err := valueobject.ParseEmail(in.ToEmail)
+	if err != nil {
+		return entity.TicketTransfer{}, apperror.New(apperror.CodeValidation, "invalid email", err)
+	}
+	var out entity.TicketTransfer
+	err = uc.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
+		if err := uc.audit.Set(ctx, txx, in.Actor.UserID, in.IP); err != nil {
+			return err
+		}
+		t, err := uc.q.LockTicketForUpdate(ctx, txx, in.TicketID)
+		if err != nil {
+			return err
+		}
+		if err := authz.RequireSelfOrAdmin(in.Actor, t.HolderID); err != nil {
+			return err
+		}
+		if t.Status != valueobject.TicketStatusPaid {
+			return apperror.New(apperror.CodeInvalidState, "ticket is "+string(t.Status), nil)
+		}
+		if t.RefundPending {
+			return errRefundPending()
+		}
+		holder, err := uc.users.GetByID(ctx, t.HolderID)
+		if err != nil {
+			return err
+		}
+		if holder.Email == email {
+			return apperror.New(apperror.CodeValidation, "ticket cannot be transferred to its holder", nil)
+		}
+		now := time.Now().UTC()
+		out = entity.TicketTransfer{
+			ID:         valueobject.NewUUID(),
+			TicketID:   t.ID,
+			FromUserID: t.HolderID,
+			ToEmail:    email,
+			Status:     valueobject.TransferStatusPending,
+			ExpiresAt:  now.Add(uc.ttl),
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		return uc.q.InsertTransfer(ctx, txx, out)
+	})
+	if err != nil {
+		return entity.TicketTransfer{}, err
+	}
+	return out, nil
+}
+
+// Accept makes the caller the ticket's holder and re-issues its QR code, so the code the
+// previous holder kept no longer admits anyone.
+func (uc *TransferUseCase) Accept(ctx context.Context, actor authz.Actor, ip string, id valueobject.UUID) (entity.TicketTransfer, error) {
+	if actor.IsAnonymous() {
+		return entity.TicketTransfer{}, apperror.New(apperror.CodeUnauthorized, "authentication required", nil)
+	}
+	me, err := uc.users.GetByID(ctx, actor.UserID)
+	if err != nil {
+		return entity.TicketTransfer{}, err
+	}
+	tr, err := uc.transfers.GetByID(ctx, id)
+	if err != nil {
+		return entity.TicketTransfer{}, err
+	}
+	if tr.ToEmail != me.Email {
+		return entity.TicketTransfer{}, apperror.New(apperror.CodeForbidden, "transfer is addressed to another user", nil)
+	}
+
+	var out entity.TicketTransfer
+	err = uc.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
+		if err := uc.audit.Set(ctx, txx, actor.UserID, ip); err != nil {
+			return err
+		}
+		// Ticket before transfer, as in Initiate.
+		t, err := uc.q.LockTicketForUpdate(ctx, txx, tr.TicketID)
+		if err != nil {
+			return err
+		}
+		tr, err := uc.q.LockTransferForUpdate(ctx, txx, id)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		if tr.Status != valueobject.TransferStatusPending {
+			return apperror.New(apperror.CodeInvalidState, "transfer is "+string(tr.Status), nil)
+		}
+		if !now.Before(tr.ExpiresAt) {
+			return apperror.New(apperror.CodeTransferExpired, fmt.Sprintf("transfer expired at %s", tr.ExpiresAt.UTC().Format(time.RFC3339)), nil)
+		}
+		if t.Status != valueobject.TicketStatusPaid {
+			return apperror.New(apperror.CodeInvalidState, "ticket is "+string(t.Status), nil)
+		}
+		if t.RefundPending {
+			return errRefundPending()
+		}
+		if t.HolderID != tr.FromUserID {
+			return apperror.New(apperror.CodeInvalidState, "ticket has changed hands since the transfer was offered", nil)
+		}
+		code, err := uc.reissue(t, now)
+		if err != nil {
+			return err
+		}
+		if err := uc.q.SetTicketHolder(ctx, txx, t.ID, actor.UserID, code); err != nil {
+			return err
+		}
+		if _, err := uc.q.FinishTransfer(ctx, txx, tr.ID, valueobject.TransferStatusAccepted, &actor.UserID, &now); err != nil {
+			return err
+		}
+		tr.Status = valueobject.TransferStatusAccepted
+		tr.ToUserID = &actor.UserID
+		tr.AcceptedAt = &now
+		tr.UpdatedAt = now
+		out = tr
+		return nil
+	})
+	if err != nil {
+		return entity.TicketTransfer{}, err
+	}
+	return out, nil
+}
+
+// Cancel withdraws a pending transfer. The sender, the recipient (declining) and admins may
+// cancel it.
+func (uc *TransferUseCase) Cancel(ctx context.Context, actor authz.Actor, ip string, id valueobject.UUID) error {
+	if actor.IsAnonymous() {
+		return apperror.New(apperror.CodeUnauthorized, "authentication required", nil)
+	}
+	me, err := uc.users.GetByID(ctx, actor.UserID)
+	if err != nil {
+		return err
+	}
+	return uc.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
+		if err := uc.audit.Set(ctx, txx, actor.UserID, ip); err != nil {
+			return err
+		}
+		tr, err := uc.q.LockTransferForUpdate(ctx, txx, id)
+		if err != nil {
+			return err
+		}
+		if tr.ToEmail != me.Email {
+			if err := authz.RequireSelfOrAdmin(actor, tr.FromUserID); err != nil {
+				return err
+			}
+		}
+		ok, err := uc.q.FinishTransfer(ctx, txx, tr.ID, valueobject.TransferStatusCancelled, nil, nil)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return apperror.New(apperror.CodeInvalidState, "transfer is "+string(tr.Status), nil)
+		}
+		return nil
+	})
+}
+
+// ListByTicket returns the ticket's transfer chain. The original buyer and the current
+// holder may see it.
+func (uc *TransferUseCase) ListByTicket(ctx context.Context, actor authz.Actor, ticketID valueobject.UUID) ([]entity.TicketTransfer, error) {
+	t, err := uc.tickets.GetByID(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if actor.UserID != t.BuyerID {
+		if err := authz.RequireSelfOrAdmin(actor, t.HolderID); err != nil {
+			return nil, err
+		}
+	}
+	return uc.transfers.ListByTicketID(ctx, ticketID)
+}
+
+// ListIncoming returns the pending transfers offered to the caller's email.
+func (uc *TransferUseCase) ListIncoming(ctx context.Context, actor authz.Actor) ([]entity.TicketTransfer, error) {
+	if actor.IsAnonymous() {
+		return nil, apperror.New(apperror.CodeUnauthorized, "authentication required", nil)
+	}
+	me, err := uc.users.GetByID(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.transfers.ListPendingByEmail(ctx, me.Email, time.Now().UTC())
+}
+
+// reissue signs a new QR code for the ticket. Payloads carry the issue time in seconds, so a
+// code issued within the same second as the current one is moved forward to differ from it.
+func (uc *TransferUseCase) reissue(t tickettx.LockedTicket, now time.Time) (string, error) {
+	p := qrtoken.Payload{TicketID: t.ID, EventID: t.EventID, IssuedAt: now}
+	code, err := uc.qr.Sign(p)
+	if err != nil {
+		return "", err
+	}
+	if code == t.QRCode {
+		p.IssuedAt = now.Add(time.Second)
+		return uc.qr.Sign(p)
+	}
+	return code, nil
+}
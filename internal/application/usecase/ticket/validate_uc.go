@@ -4,9 +4,13 @@ import (
 	"context"
 	"time"
 
+	"time2meet/internal/application/authz"
 	"time2meet/internal/application/port/auditctx"
+	"time2meet/internal/application/port/qrtoken"
 	"time2meet/internal/application/port/tickettx"
 	"time2meet/internal/application/tx"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
 	"time2meet/internal/domain/valueobject"
 	"time2meet/pkg/apperror"
 
@@ -14,42 +18,151 @@ import (
 )
 
 type ValidateUseCase struct {
-	tx    tx.Manager
-	audit auditctx.Setter
-	q     tickettx.Queries
+	tx       tx.Manager
+	audit    auditctx.Setter
+	q        tickettx.Queries
+	checkins repository.CheckinRepository
+	qr       qrtoken.Signer
+	guard    *authz.EventGuard
 }
 
-func NewValidate(txm tx.Manager, audit auditctx.Setter, q tickettx.Queries) *ValidateUseCase {
-	return &ValidateUseCase{tx: txm, audit: audit, q: q}
+func NewValidate(txm tx.Manager, audit auditctx.Setter, q tickettx.Queries, checkins repository.CheckinRepository, qr qrtoken.Signer, guard *authz.EventGuard) *ValidateUseCase {
+	return &ValidateUseCase{tx: txm, audit: audit, q: q, checkins: checkins, qr: qr, guard: guard}
 }
 
 type ValidateInput struct {
-	UserID   valueobject.UUID
-	IP       string
-	TicketID valueobject.UUID
+	Actor authz.Actor
+	IP    string
+	// Payload is the signed QR token as scanned from the ticket.
+	Payload string
+	// EventID is the event whose entrance is being scanned. Optional for staff (the event is
+	// then taken from the ticket); devices default to the event they are registered for.
+	EventID valueobject.UUID
+	Gate    string
 }
 
-func (uc *ValidateUseCase) Validate(ctx context.Context, in ValidateInput) error {
-	if in.UserID == valueobject.Nil {
-		return apperror.New(apperror.CodeValidation, "user_id is required", nil)
+type ValidateOutput struct {
+	TicketID     valueobject.UUID
+	TicketTypeID valueobject.UUID
+	EventID      valueobject.UUID
+	// UsedAt is the first admission time; EntryNumber counts admissions including this one.
+	UsedAt           time.Time
+	EntryNumber      int
+	EntriesRemaining int
+}
+
+// Validate admits a ticket. Every attempt made by an authorized scanner is recorded in the
+// check-in log, including rejected ones.
+func (uc *ValidateUseCase) Validate(ctx context.Context, in ValidateInput) (ValidateOutput, error) {
+	eventID := in.EventID
+	if eventID == valueobject.Nil && in.Actor.IsDevice() {
+		eventID = in.Actor.Device.EventID
+	}
+	if eventID != valueobject.Nil {
+		if err := uc.guard.RequireScanner(ctx, in.Actor, eventID); err != nil {
+			return ValidateOutput{}, err
+		}
+	} else if err := authz.RequireRole(in.Actor, entity.UserRoleAdmin, entity.UserRoleOrganizer); err != nil {
+		return ValidateOutput{}, err
+	}
+	if in.Payload == "" {
+		return ValidateOutput{}, apperror.New(apperror.CodeValidation, "payload is required", nil)
+	}
+
+	now := time.Now().UTC()
+	attempt := newAttempt(in.Actor, eventID, in.Gate, false, now)
+	p, err := uc.qr.Verify(in.Payload)
+	if err != nil {
+		return ValidateOutput{}, recordFailure(ctx, uc.checkins, attempt, err)
 	}
-	if in.TicketID == valueobject.Nil {
-		return apperror.New(apperror.CodeValidation, "ticket_id is required", nil)
+	attempt.TicketID = &p.TicketID
+	if eventID == valueobject.Nil {
+		// Without an explicit event the caller must manage the ticket's event; attempts
+		// outside the caller's events are not attributable and are not recorded.
+		if _, err := uc.guard.RequireEventManager(ctx, in.Actor, p.EventID); err != nil {
+			return ValidateOutput{}, err
+		}
+		attempt.EventID = &p.EventID
+	} else if p.EventID != eventID {
+		return ValidateOutput{}, recordFailure(ctx, uc.checkins, attempt, wrongEvent(p.EventID))
 	}
 
-	return uc.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
-		if err := uc.audit.Set(ctx, txx, in.UserID, in.IP); err != nil {
+	var out ValidateOutput
+	err = uc.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
+		if err := uc.audit.Set(ctx, txx, in.Actor.UserID, in.IP); err != nil {
+			return err
+		}
+		t, err := uc.q.LockTicketForUpdate(ctx, txx, p.TicketID)
+		if err != nil {
+			return err
+		}
+		// A valid signature is not enough: the ticket may have been re-issued a new code.
+		if t.QRCode != in.Payload || t.EventID != p.EventID {
+			return apperror.New(apperror.CodeInvalidQRCode, "qr code has been superseded", nil)
+		}
+		if err := checkAdmissible(t); err != nil {
 			return err
 		}
-		ok, err := uc.q.MarkTicketUsed(ctx, txx, in.TicketID, time.Now().UTC())
+		ok, err := uc.q.MarkTicketUsed(ctx, txx, t.ID, now, attempt.StaffID, attempt.DeviceID, t.MaxEntries())
 		if err != nil {
 			return err
 		}
 		if !ok {
-			return apperror.New(apperror.CodeNotFound, "ticket not found or invalid state", nil)
+			return apperror.New(apperror.CodeInvalidState, "ticket is "+string(t.Status), nil)
+		}
+		attempt.Result = entity.CheckinResultAdmitted
+		if err := uc.q.InsertCheckin(ctx, txx, attempt); err != nil {
+			return err
+		}
+		out = ValidateOutput{
+			TicketID:         t.ID,
+			TicketTypeID:     t.TicketTypeID,
+			EventID:          t.EventID,
+			UsedAt:           now,
+			EntryNumber:      t.EntryCount + 1,
+			EntriesRemaining: t.MaxEntries() - t.EntryCount - 1,
+		}
+		if t.UsedAt != nil {
+			out.UsedAt = *t.UsedAt
 		}
 		return nil
 	})
+	if err != nil {
+		return ValidateOutput{}, recordFailure(ctx, uc.checkins, attempt, err)
+	}
+	return out, nil
 }
 
+// checkAdmissible rejects tickets that cannot be admitted again. Already-used tickets get a
+// distinct code with the original check-in so door staff can tell a re-scan from fraud.
+func checkAdmissible(t tickettx.LockedTicket) error {
+	switch t.Status {
+	case valueobject.TicketStatusPaid:
+		if t.RefundPending {
+			return errRefundPending()
+		}
+		return nil
+	case valueobject.TicketStatusUsed:
+		if t.EntryCount < t.MaxEntries() {
+			return nil
+		}
+		details := map[string]any{"entry_count": t.EntryCount}
+		if t.UsedAt != nil {
+			details["used_at"] = t.UsedAt.UTC().Format(time.RFC3339)
+		}
+		if t.UsedBy != nil {
+			details["used_by"] = t.UsedBy.String()
+		}
+		if t.UsedByDeviceID != nil {
+			details["used_by_device_id"] = t.UsedByDeviceID.String()
+		}
+		return apperror.New(apperror.CodeAlreadyUsed, "ticket has already been used", nil).WithDetails(details)
+	default:
+		return apperror.New(apperror.CodeInvalidState, "ticket is "+string(t.Status), nil)
+	}
+}
 
+// errRefundPending refuses to admit or hand over a ticket whose money is on its way back.
+func errRefundPending() error {
+	return apperror.New(apperror.CodeInvalidState, "ticket has a pending refund", nil)
+}
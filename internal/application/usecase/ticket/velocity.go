@@ -0,0 +1,59 @@
+package ticket
+
+import (
+	"context"
+	"time"
+
+	"time2meet/internal/application/port/tickettx"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/valueobject"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// VelocityLimits configures the per-IP checks run on every checkout. A zero limit disables
+// its check.
+type VelocityLimits struct {
+	Window time.Duration
+	// MaxOrders is how many orders one IP may place within Window.
+	MaxOrders int
+	// MaxBuyers is how many different buyers may order from one IP within Window.
+	MaxBuyers int
+}
+
+// screen flags the order just placed when its IP went over a limit. Flagged orders still go
+// through: many buyers legitimately share an IP behind NAT, so admins review them in the
+// flagged purchases report instead.
+func (v VelocityLimits) screen(ctx context.Context, txx *sqlx.Tx, q tickettx.Queries, o entity.Order) error {
+	if o.ClientIP == "" || v.Window <= 0 || (v.MaxOrders <= 0 && v.MaxBuyers <= 0) {
+		return nil
+	}
+	// The count includes the order itself.
+	orders, buyers, err := q.CountIPOrders(ctx, txx, o.ClientIP, o.CreatedAt.Add(-v.Window))
+	if err != nil {
+		return err
+	}
+	flag := func(reason valueobject.PurchaseFlagReason, observed, threshold int) error {
+		return q.InsertPurchaseFlag(ctx, txx, entity.PurchaseFlag{
+			ID:        valueobject.NewUUID(),
+			OrderID:   o.ID,
+			BuyerID:   o.BuyerID,
+			ClientIP:  o.ClientIP,
+			Reason:    reason,
+			Observed:  observed,
+			Threshold: threshold,
+			CreatedAt: o.CreatedAt,
+		})
+	}
+	if v.MaxOrders > 0 && orders > v.MaxOrders {
+		if err := flag(valueobject.PurchaseFlagIPOrderVelocity, orders, v.MaxOrders); err != nil {
+			return err
+		}
+	}
+	if v.MaxBuyers > 0 && buyers > v.MaxBuyers {
+		if err := flag(valueobject.PurchaseFlagIPBuyerVelocity, buyers, v.MaxBuyers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package ticket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"time2meet/internal/application/authz"
+	"time2meet/internal/application/port/auditctx"
+	"time2meet/internal/application/port/notify"
+	"time2meet/internal/application/port/tickettx"
+	"time2meet/internal/application/tx"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// WaitlistUseCase queues users for sold-out ticket types. Freed tickets are offered in join
+// order to the entries they cover: an entry asking for more than is available is passed over
+// until enough tickets free up, rather than blocking everyone behind it. An offered entry gets
+// a hold for its quantity, which it confirms through HoldUseCase.Confirm before the offer
+// expires. While a waiting entry fits the available tickets, new purchases and holds of the
+// type are refused (see checkWaitlist).
+type WaitlistUseCase struct {
+	tx          tx.Manager
+	audit       auditctx.Setter
+	q           tickettx.Queries
+	waitlist    repository.WaitlistRepository
+	ticketTypes repository.TicketTypeRepository
+	users       repository.UserRepository
+	guard       *authz.EventGuard
+	notifier    notify.Notifier
+	offerTTL    time.Duration
+}
+
+func NewWaitlist(txm tx.Manager, audit auditctx.Setter, q tickettx.Queries, waitlist repository.WaitlistRepository, ticketTypes repository.TicketTypeRepository, users repository.UserRepository, guard *authz.EventGuard, notifier notify.Notifier, offerTTL time.Duration) *WaitlistUseCase {
+	return &WaitlistUseCase{tx: txm, audit: audit, q: q, waitlist: waitlist, ticketTypes: ticketTypes, users: users, guard: guard, notifier: notifier, offerTTL: offerTTL}
+}
+
+type JoinWaitlistInput struct {
+	Actor        authz.Actor
+	IP           string
+	TicketTypeID valueobject.UUID
+	Quantity     int
+}
+
+// Join queues the caller for a ticket type that is sold out, or whose remaining tickets are
+// owed to the queue. Purchase limits are checked on joining and again when the offer is
+// confirmed.
+func (uc *WaitlistUseCase) Join(ctx context.Context, in JoinWaitlistInput) (entity.WaitlistEntry, error) {
+	if in.Actor.IsAnonymous() {
+		return entity.WaitlistEntry{}, apperror.New(apperror.CodeUnauthorized, "authentication required", nil)
+	}
+	if in.TicketTypeID == valueobject.Nil {
+		return entity.WaitlistEntry{}, apperror.New(apperror.CodeValidation, "ticket_type_id is required", nil)
+	}
+	if in.Quantity <= 0 {
+		return entity.WaitlistEntry{}, apperror.New(apperror.CodeValidation, "quantity must be positive", nil)
+	}
+	if in.Quantity > maxTicketsPerOrder {
+		return entity.WaitlistEntry{}, apperror.New(apperror.CodeValidation, fmt.Sprintf("at most %d tickets per order", maxTicketsPerOrder), nil)
+	}
+
+	var out entity.WaitlistEntry
+	err := uc.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
+		if err := uc.audit.Set(ctx, txx, in.Actor.UserID, in.IP); err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		tt, err := uc.q.LockTicketTypeForUpdate(ctx, txx, in.TicketTypeID)
+		if err != nil {
+			return err
+		}
+		if err := checkSaleOpen(tt, now); err != nil {
+			return err
+		}
+		// Tickets still on sale are bought, not queued for. With nobody waiting that is any
+		// available ticket; with a queue that cannot use them, any quantity they cover.
+		if available := tt.Available(); available > 0 && (tt.WaitlistWaiting == 0 || (!waitlistClaims(tt) && in.Quantity <= available)) {
+			return apperror.New(apperror.CodeInvalidState, fmt.Sprintf("%d tickets are still on sale", available), nil).
+				WithDetails(map[string]any{"ticket_type_id": tt.ID.String(), "available": available})
+		}
+		// An entry that can never be served would wait forever.
+		if in.Quantity > tt.QuantityTotal {
+			return apperror.New(apperror.CodeValidation, fmt.Sprintf("ticket type has only %d tickets", tt.QuantityTotal), nil)
+		}
+		if err := checkPurchaseLimits(ctx, txx, uc.q, tt, in.Actor.UserID, in.Quantity); err != nil {
+			return err
+		}
+		e := entity.WaitlistEntry{
+			ID:           valueobject.NewUUID(),
+			TicketTypeID: tt.ID,
+			UserID:       in.Actor.UserID,
+			Quantity:     in.Quantity,
+			Status:       valueobject.WaitlistStatusWaiting,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+		if err := uc.q.InsertWaitlistEntry(ctx, txx, e); err != nil {
+			return err
+		}
+		e.Position = tt.WaitlistWaiting + 1
+		out = e
+		return nil
+	})
+	if err != nil {
+		return entity.WaitlistEntry{}, err
+	}
+	return out, nil
+}
+
+func (uc *WaitlistUseCase) Get(ctx context.Context, actor authz.Actor, id valueobject.UUID) (entity.WaitlistEntry, error) {
+	e, err := uc.waitlist.GetByID(ctx, id)
+	if err != nil {
+		return entity.WaitlistEntry{}, err
+	}
+	if err := authz.RequireSelfOrAdmin(actor, e.UserID); err != nil {
+		return entity.WaitlistEntry{}, err
+	}
+	return e, nil
+}
+
+// ListMine lists the caller's entries, newest first.
+func (uc *WaitlistUseCase) ListMine(ctx context.Context, actor authz.Actor, limit, offset int) ([]entity.WaitlistEntry, error) {
+	if actor.IsAnonymous() {
+		return nil, apperror.New(apperror.CodeUnauthorized, "authentication required", nil)
+	}
+	return uc.waitlist.ListByUserID(ctx, actor.UserID, limit, offset)
+}
+
+// ListForTicketType shows the event's organizer who is waiting for a ticket type and who holds
+// an offer.
+func (uc *WaitlistUseCase) ListForTicketType(ctx context.Context, actor authz.Actor, eventID, ticketTypeID valueobject.UUID, limit, offset int) ([]entity.WaitlistEntry, error) {
+	if _, err := uc.guard.RequireEventManager(ctx, actor, eventID); err != nil {
+		return nil, err
+	}
+	tt, err := uc.ticketTypes.GetByID(ctx, ticketTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if tt.EventID != eventID {
+		return nil, apperror.New(apperror.CodeNotFound, "ticket type not found", nil)
+	}
+	return uc.waitlist.ListByTicketTypeID(ctx, ticketTypeID, limit, offset)
+}
+
+// Leave takes the entry off the waitlist. An open offer is declined and its tickets go to the
+// next entry.
+func (uc *WaitlistUseCase) Leave(ctx context.Context, actor authz.Actor, ip string, id valueobject.UUID) error {
+	return uc.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
+		if err := uc.audit.Set(ctx, txx, actor.UserID, ip); err != nil {
+			return err
+		}
+		e, err := uc.q.LockWaitlistEntryForUpdate(ctx, txx, id)
+		if err != nil {
+			return err
+		}
+		if err := authz.RequireSelfOrAdmin(actor, e.UserID); err != nil {
+			return err
+		}
+		if !e.Status.Open() {
+			return apperror.New(apperror.CodeInvalidState, "waitlist entry is "+string(e.Status), nil)
+		}
+		if _, err := uc.q.SetWaitlistEntryStatus(ctx, txx, e.ID, e.Status, valueobject.WaitlistStatusCancelled); err != nil {
+			return err
+		}
+		if e.Status == valueobject.WaitlistStatusOffered && e.HoldID != nil {
+			if _, err := uc.q.FinishHold(ctx, txx, *e.HoldID, valueobject.HoldStatusReleased, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type WaitlistOfferResult struct {
+	// Settled counts offers closed because their hold was confirmed, released or expired.
+	Settled int
+	Offered int
+}
+
+// OfferFreed closes finished offers, then offers freed tickets of up to limit ticket types to
+// their queues. Users are notified after each ticket type's offers are committed; delivery
+// failures are returned joined but do not undo the offers, which users also see on
+// GET /waitlist.
+func (uc *WaitlistUseCase) OfferFreed(ctx context.Context, limit int) (WaitlistOfferResult, error) {
+	var res WaitlistOfferResult
+	settled, err := uc.waitlist.SettleOffers(ctx, limit)
+	res.Settled = settled
+	if err != nil {
+		return res, err
+	}
+	ids, err := uc.waitlist.ListOfferable(ctx, time.Now().UTC(), limit)
+	if err != nil {
+		return res, err
+	}
+	var errs []error
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			break
+		}
+		offers, err := uc.offer(ctx, id)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		res.Offered += len(offers)
+		for _, o := range offers {
+			if err := uc.notifyOffer(ctx, o); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return res, errors.Join(errs...)
+}
+
+// offer hands the ticket type's available tickets to its queue in order, passing over entries
+// that ask for more than is left. Types that are not on sale keep their queue.
+func (uc *WaitlistUseCase) offer(ctx context.Context, ticketTypeID valueobject.UUID) ([]entity.WaitlistEntry, error) {
+	var out []entity.WaitlistEntry
+	err := uc.tx.WithTx(ctx, func(ctx context.Context, txx *sqlx.Tx) error {
+		out = nil
+		if err := uc.audit.Set(ctx, txx, valueobject.Nil, ""); err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		tt, err := uc.q.LockTicketTypeForUpdate(ctx, txx, ticketTypeID)
+		if err != nil {
+			return err
+		}
+		if checkSaleOpen(tt, now) != nil {
+			return nil
+		}
+		for {
+			available := tt.Available()
+			if available <= 0 {
+				return nil
+			}
+			e, ok, err := uc.q.LockNextWaitlistEntry(ctx, txx, tt.ID, available)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return nil
+			}
+			expiresAt := now.Add(uc.offerTTL)
+			h := entity.TicketHold{
+				ID:           valueobject.NewUUID(),
+				TicketTypeID: tt.ID,
+				UserID:       e.UserID,
+				Quantity:     e.Quantity,
+				Status:       valueobject.HoldStatusActive,
+				ExpiresAt:    expiresAt,
+				CreatedAt:    now,
+				UpdatedAt:    now,
+			}
+			if err := uc.q.InsertHold(ctx, txx, h); err != nil {
+				return err
+			}
+			if _, err := uc.q.OfferWaitlistEntry(ctx, txx, e.ID, h.ID, now, expiresAt); err != nil {
+				return err
+			}
+			tt.QuantityHeld += e.Quantity
+			e.Status = valueobject.WaitlistStatusOffered
+			e.HoldID = &h.ID
+			e.OfferedAt = &now
+			e.OfferExpiresAt = &expiresAt
+			e.Position = 0
+			out = append(out, e)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (uc *WaitlistUseCase) notifyOffer(ctx context.Context, e entity.WaitlistEntry) error {
+	u, err := uc.users.GetByID(ctx, e.UserID)
+	if err != nil {
+		return err
+	}
+	deadline := e.OfferExpiresAt.UTC().Format(time.RFC3339)
+	return uc.notifier.Notify(ctx, notify.Message{
+		Kind:    notify.KindWaitlistOffer,
+		UserID:  u.ID,
+		Email:   u.Email,
+		Subject: "Tickets are waiting for you",
+		Body:    fmt.Sprintf("%d ticket(s) are held for you until %s. Confirm hold %s to buy them.", e.Quantity, deadline, e.HoldID.String()),
+		Data: map[string]string{
+			"waitlist_entry_id": e.ID.String(),
+			"ticket_type_id":    e.TicketTypeID.String(),
+			"hold_id":           e.HoldID.String(),
+			"quantity":          strconv.Itoa(e.Quantity),
+			"expires_at":        deadline,
+		},
+	})
+}
@@ -0,0 +1,205 @@
+package ticket
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"time2meet/internal/application/authz"
+	"time2meet/internal/application/port/tickettx"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type fakeTx struct{}
+
+func (fakeTx) WithTx(ctx context.Context, fn func(ctx context.Context, tx *sqlx.Tx) error) error {
+	return fn(ctx, nil)
+}
+
+type fakeAudit struct{}
+
+func (fakeAudit) Set(context.Context, *sqlx.Tx, valueobject.UUID, string) error { return nil }
+
+// fakeWaitlistQueries keeps one ticket type and its waitlist in memory. Queries the waitlist
+// does not use are left to the nil embedded interface and panic if called.
+type fakeWaitlistQueries struct {
+	tickettx.Queries
+	tt      tickettx.LockedTicketType
+	entries []entity.WaitlistEntry
+}
+
+func newFakeWaitlistQueries(total, sold int) *fakeWaitlistQueries {
+	return &fakeWaitlistQueries{tt: tickettx.LockedTicketType{
+		ID:            valueobject.NewUUID(),
+		EventID:       valueobject.NewUUID(),
+		QuantityTotal: total,
+		QuantitySold:  sold,
+		IsActive:      true,
+		EventStatus:   valueobject.EventStatusPublished,
+	}}
+}
+
+func (f *fakeWaitlistQueries) wait(qty int) valueobject.UUID {
+	e := entity.WaitlistEntry{
+		ID:           valueobject.NewUUID(),
+		TicketTypeID: f.tt.ID,
+		UserID:       valueobject.NewUUID(),
+		Quantity:     qty,
+		Status:       valueobject.WaitlistStatusWaiting,
+	}
+	f.entries = append(f.entries, e)
+	return e.ID
+}
+
+func (f *fakeWaitlistQueries) status(id valueobject.UUID) valueobject.WaitlistStatus {
+	for _, e := range f.entries {
+		if e.ID == id {
+			return e.Status
+		}
+	}
+	return ""
+}
+
+func (f *fakeWaitlistQueries) LockTicketTypeForUpdate(context.Context, *sqlx.Tx, valueobject.UUID) (tickettx.LockedTicketType, error) {
+	tt := f.tt
+	tt.WaitlistWaiting, tt.WaitlistMinQuantity = 0, 0
+	for _, e := range f.entries {
+		if e.Status != valueobject.WaitlistStatusWaiting {
+			continue
+		}
+		tt.WaitlistWaiting++
+		if tt.WaitlistMinQuantity == 0 || e.Quantity < tt.WaitlistMinQuantity {
+			tt.WaitlistMinQuantity = e.Quantity
+		}
+	}
+	return tt, nil
+}
+
+func (f *fakeWaitlistQueries) InsertWaitlistEntry(_ context.Context, _ *sqlx.Tx, e entity.WaitlistEntry) error {
+	f.entries = append(f.entries, e)
+	return nil
+}
+
+func (f *fakeWaitlistQueries) LockNextWaitlistEntry(_ context.Context, _ *sqlx.Tx, _ valueobject.UUID, maxQuantity int) (entity.WaitlistEntry, bool, error) {
+	for _, e := range f.entries {
+		if e.Status == valueobject.WaitlistStatusWaiting && e.Quantity <= maxQuantity {
+			return e, true, nil
+		}
+	}
+	return entity.WaitlistEntry{}, false, nil
+}
+
+func (f *fakeWaitlistQueries) InsertHold(_ context.Context, _ *sqlx.Tx, h entity.TicketHold) error {
+	f.tt.QuantityHeld += h.Quantity
+	return nil
+}
+
+func (f *fakeWaitlistQueries) OfferWaitlistEntry(_ context.Context, _ *sqlx.Tx, entryID, holdID valueobject.UUID, _, _ time.Time) (bool, error) {
+	for i, e := range f.entries {
+		if e.ID == entryID && e.Status == valueobject.WaitlistStatusWaiting {
+			f.entries[i].Status = valueobject.WaitlistStatusOffered
+			f.entries[i].HoldID = &holdID
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func newTestWaitlist(q tickettx.Queries) *WaitlistUseCase {
+	return NewWaitlist(fakeTx{}, fakeAudit{}, q, nil, nil, nil, nil, nil, 30*time.Minute)
+}
+
+func TestWaitlistJoin(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int
+		sold    int
+		waiting []int // quantities already in the queue, in join order
+		qty     int
+		wantErr apperror.Code
+	}{
+		{name: "sold out", total: 10, sold: 10, qty: 2},
+		{name: "tickets left and nobody waiting", total: 20, sold: 10, qty: 2, wantErr: apperror.CodeInvalidState},
+		{name: "more than is left and nobody waiting", total: 20, sold: 10, qty: 11, wantErr: apperror.CodeInvalidState},
+		{name: "queue cannot use what is left, quantity fits", total: 10, sold: 7, waiting: []int{4}, qty: 2, wantErr: apperror.CodeInvalidState},
+		{name: "queue cannot use what is left, quantity does not fit", total: 10, sold: 7, waiting: []int{4}, qty: 5},
+		{name: "what is left is owed to the queue", total: 10, sold: 7, waiting: []int{4, 2}, qty: 1},
+		{name: "more than the type has", total: 10, sold: 10, qty: 11, wantErr: apperror.CodeValidation},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			q := newFakeWaitlistQueries(tc.total, tc.sold)
+			for _, n := range tc.waiting {
+				q.wait(n)
+			}
+			uc := newTestWaitlist(q)
+			e, err := uc.Join(context.Background(), JoinWaitlistInput{
+				Actor:        authz.Actor{UserID: valueobject.NewUUID(), Role: entity.UserRoleAttendee},
+				TicketTypeID: q.tt.ID,
+				Quantity:     tc.qty,
+			})
+			if tc.wantErr != "" {
+				var ae *apperror.AppError
+				if !errors.As(err, &ae) || ae.Code != tc.wantErr {
+					t.Fatalf("err = %v, want %s", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Join: %v", err)
+			}
+			if want := len(tc.waiting) + 1; e.Position != want {
+				t.Errorf("position = %d, want %d", e.Position, want)
+			}
+		})
+	}
+}
+
+func TestWaitlistOfferSkipsEntriesThatDoNotFit(t *testing.T) {
+	q := newFakeWaitlistQueries(10, 7)
+	large := q.wait(4)
+	pair := q.wait(2)
+	single := q.wait(1)
+	another := q.wait(1)
+	uc := newTestWaitlist(q)
+
+	offers, err := uc.offer(context.Background(), q.tt.ID)
+	if err != nil {
+		t.Fatalf("offer: %v", err)
+	}
+	if len(offers) != 2 || offers[0].ID != pair || offers[1].ID != single {
+		t.Fatalf("offered %v, want the pair and then the first single", offers)
+	}
+	for id, want := range map[valueobject.UUID]valueobject.WaitlistStatus{
+		large:   valueobject.WaitlistStatusWaiting,
+		pair:    valueobject.WaitlistStatusOffered,
+		single:  valueobject.WaitlistStatusOffered,
+		another: valueobject.WaitlistStatusWaiting,
+	} {
+		if got := q.status(id); got != want {
+			t.Errorf("entry %s is %s, want %s", id, got, want)
+		}
+	}
+	if q.tt.Available() != 0 {
+		t.Errorf("available = %d, want 0", q.tt.Available())
+	}
+}
+
+func TestCheckWaitlistLeavesTicketsNoEntryFits(t *testing.T) {
+	q := newFakeWaitlistQueries(10, 7)
+	q.wait(4)
+	tt, _ := q.LockTicketTypeForUpdate(context.Background(), nil, q.tt.ID)
+	if err := checkWaitlist(tt); err != nil {
+		t.Fatalf("3 tickets left for a queue asking for 4: %v", err)
+	}
+	q.wait(3)
+	tt, _ = q.LockTicketTypeForUpdate(context.Background(), nil, q.tt.ID)
+	if err := checkWaitlist(tt); err == nil {
+		t.Fatal("3 tickets left for a queue entry asking for 3 are still on sale")
+	}
+}
@@ -3,6 +3,8 @@ package user
 import (
 	"context"
 
+	"time2meet/internal/application/authz"
+	"time2meet/internal/application/port/passwordhash"
 	"time2meet/internal/domain/entity"
 	"time2meet/internal/domain/repository"
 	"time2meet/internal/domain/valueobject"
@@ -12,27 +14,53 @@ import (
 type UseCase struct {
 	users    repository.UserRepository
 	profiles repository.UserProfileRepository
+	hasher   passwordhash.Hasher
 }
 
-func New(users repository.UserRepository, profiles repository.UserProfileRepository) *UseCase {
-	return &UseCase{users: users, profiles: profiles}
+func New(users repository.UserRepository, profiles repository.UserProfileRepository, hasher passwordhash.Hasher) *UseCase {
+	return &UseCase{users: users, profiles: profiles, hasher: hasher}
 }
 
 type CreateUserInput struct {
-	Email        string
-	PasswordHash string
-	FullName     string
-	Phone        string
-	Role         string
+	Email    string
+	Password string
+	FullName string
+	Phone    string
+	Role     string
 }
 
-func (uc *UseCase) Create(ctx context.Context, in CreateUserInput) (valueobject.UUID, error) {
+func (uc *UseCase) Create(ctx context.Context, actor authz.Actor, in CreateUserInput) (valueobject.UUID, error) {
+	if err := authz.RequireRole(actor, entity.UserRoleAdmin); err != nil {
+		return valueobject.Nil, err
+	}
+	return uc.create(ctx, in)
+}
+
+type RegisterInput struct {
+	Email    string
+	Password string
+	FullName string
+	Phone    string
+}
+
+// Register signs up an attendee. Organizers and admins are only created by an admin.
+func (uc *UseCase) Register(ctx context.Context, in RegisterInput) (valueobject.UUID, error) {
+	return uc.create(ctx, CreateUserInput{
+		Email:    in.Email,
+		Password: in.Password,
+		FullName: in.FullName,
+		Phone:    in.Phone,
+		Role:     string(entity.UserRoleAttendee),
+	})
+}
+
+func (uc *UseCase) create(ctx context.Context, in CreateUserInput) (valueobject.UUID, error) {
 	email, err := valueobject.ParseEmail(in.Email)
 	if err != nil {
 		return valueobject.Nil, apperror.New(apperror.CodeValidation, "invalid email", err)
 	}
-	if in.PasswordHash == "" {
-		return valueobject.Nil, apperror.New(apperror.CodeValidation, "password_hash is required", nil)
+	if err := valueobject.ValidatePassword(in.Password); err != nil {
+		return valueobject.Nil, apperror.New(apperror.CodeValidation, err.Error(), err)
 	}
 	if in.FullName == "" {
 		return valueobject.Nil, apperror.New(apperror.CodeValidation, "full_name is required", nil)
@@ -43,9 +71,13 @@ func (uc *UseCase) Create(ctx context.Context, in CreateUserInput) (valueobject.
 	default:
 		return valueobject.Nil, apperror.New(apperror.CodeValidation, "invalid role", nil)
 	}
+	hash, err := uc.hasher.Hash(in.Password)
+	if err != nil {
+		return valueobject.Nil, err
+	}
 	u := entity.User{
 		Email:        email,
-		PasswordHash: in.PasswordHash,
+		PasswordHash: hash,
 		FullName:     in.FullName,
 		Phone:        in.Phone,
 		Role:         role,
@@ -54,25 +86,36 @@ func (uc *UseCase) Create(ctx context.Context, in CreateUserInput) (valueobject.
 	return uc.users.Create(ctx, u)
 }
 
-func (uc *UseCase) Get(ctx context.Context, id valueobject.UUID) (entity.User, error) {
+func (uc *UseCase) Get(ctx context.Context, actor authz.Actor, id valueobject.UUID) (entity.User, error) {
+	if err := authz.RequireSelfOrAdmin(actor, id); err != nil {
+		return entity.User{}, err
+	}
 	return uc.users.GetByID(ctx, id)
 }
 
-func (uc *UseCase) List(ctx context.Context, limit, offset int) ([]entity.User, error) {
+func (uc *UseCase) List(ctx context.Context, actor authz.Actor, limit, offset int) ([]entity.User, error) {
+	if err := authz.RequireRole(actor, entity.UserRoleAdmin); err != nil {
+		return nil, err
+	}
 	return uc.users.List(ctx, limit, offset)
 }
 
 type UpdateUserInput struct {
-	ID           valueobject.UUID
-	Email        string
-	PasswordHash string
-	FullName     string
-	Phone        string
-	Role         string
-	IsActive     bool
+	ID    valueobject.UUID
+	Email string
+	// Password is optional; an empty value keeps the current password.
+	Password string
+	FullName string
+	Phone    string
+	Role     string
+	IsActive bool
 }
 
-func (uc *UseCase) Update(ctx context.Context, in UpdateUserInput) error {
+// Update lets users edit their own profile; only admins may edit others or change role/is_active.
+func (uc *UseCase) Update(ctx context.Context, actor authz.Actor, in UpdateUserInput) error {
+	if err := authz.RequireSelfOrAdmin(actor, in.ID); err != nil {
+		return err
+	}
 	email, err := valueobject.ParseEmail(in.Email)
 	if err != nil {
 		return apperror.New(apperror.CodeValidation, "invalid email", err)
@@ -83,10 +126,28 @@ func (uc *UseCase) Update(ctx context.Context, in UpdateUserInput) error {
 	default:
 		return apperror.New(apperror.CodeValidation, "invalid role", nil)
 	}
+	if !actor.IsAdmin() {
+		cur, err := uc.users.GetByID(ctx, in.ID)
+		if err != nil {
+			return err
+		}
+		if role != cur.Role || in.IsActive != cur.IsActive {
+			return apperror.New(apperror.CodeForbidden, "only admins can change role or is_active", nil)
+		}
+	}
+	var hash string
+	if in.Password != "" {
+		if err := valueobject.ValidatePassword(in.Password); err != nil {
+			return apperror.New(apperror.CodeValidation, err.Error(), err)
+		}
+		if hash, err = uc.hasher.Hash(in.Password); err != nil {
+			return err
+		}
+	}
 	u := entity.User{
 		ID:           in.ID,
 		Email:        email,
-		PasswordHash: in.PasswordHash,
+		PasswordHash: hash,
 		FullName:     in.FullName,
 		Phone:        in.Phone,
 		Role:         role,
@@ -95,7 +156,10 @@ func (uc *UseCase) Update(ctx context.Context, in UpdateUserInput) error {
 	return uc.users.Update(ctx, u)
 }
 
-func (uc *UseCase) Delete(ctx context.Context, id valueobject.UUID) error {
+func (uc *UseCase) Delete(ctx context.Context, actor authz.Actor, id valueobject.UUID) error {
+	if err := authz.RequireRole(actor, entity.UserRoleAdmin); err != nil {
+		return err
+	}
 	return uc.users.Delete(ctx, id)
 }
 
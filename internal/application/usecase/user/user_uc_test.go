@@ -0,0 +1,41 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"time2meet/internal/application/authz"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	authinfra "time2meet/internal/infrastructure/auth"
+)
+
+type fakeUsers struct {
+	repository.UserRepository
+	created []entity.User
+}
+
+func (f *fakeUsers) Create(_ context.Context, u entity.User) (valueobject.UUID, error) {
+	f.created = append(f.created, u)
+	return valueobject.NewUUID(), nil
+}
+
+func TestRegisterCreatesAttendees(t *testing.T) {
+	ctx := context.Background()
+	users := &fakeUsers{}
+	hasher := authinfra.NewArgon2Hasher(authinfra.Argon2Params{Memory: 1024, Time: 1, Threads: 1, SaltLen: 16, KeyLen: 32})
+	uc := New(users, nil, hasher)
+
+	if _, err := uc.Register(ctx, RegisterInput{Email: "fan@example.com", Password: "correct horse 42", FullName: "Fan"}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if len(users.created) != 1 || users.created[0].Role != entity.UserRoleAttendee {
+		t.Fatalf("created %v, want one attendee", users.created)
+	}
+
+	attendee := authz.Actor{UserID: valueobject.NewUUID(), Role: entity.UserRoleAttendee}
+	if _, err := uc.Create(ctx, attendee, CreateUserInput{Email: "boss@example.com", Password: "correct horse 42", FullName: "Boss", Role: string(entity.UserRoleAdmin)}); err == nil {
+		t.Fatal("an attendee created an admin")
+	}
+}
@@ -3,6 +3,7 @@ package venue
 import (
 	"context"
 
+	"time2meet/internal/application/authz"
 	"time2meet/internal/domain/entity"
 	"time2meet/internal/domain/repository"
 	"time2meet/internal/domain/valueobject"
@@ -29,7 +30,10 @@ type CreateVenueInput struct {
 	Website      string
 }
 
-func (uc *UseCase) CreateVenue(ctx context.Context, in CreateVenueInput) (valueobject.UUID, error) {
+func (uc *UseCase) CreateVenue(ctx context.Context, actor authz.Actor, in CreateVenueInput) (valueobject.UUID, error) {
+	if err := authz.RequireRole(actor, entity.UserRoleAdmin); err != nil {
+		return valueobject.Nil, err
+	}
 	if in.Name == "" || in.Address == "" || in.City == "" {
 		return valueobject.Nil, apperror.New(apperror.CodeValidation, "name/address/city are required", nil)
 	}
@@ -71,7 +75,10 @@ type UpdateVenueInput struct {
 	IsActive     bool
 }
 
-func (uc *UseCase) UpdateVenue(ctx context.Context, in UpdateVenueInput) error {
+func (uc *UseCase) UpdateVenue(ctx context.Context, actor authz.Actor, in UpdateVenueInput) error {
+	if err := authz.RequireRole(actor, entity.UserRoleAdmin); err != nil {
+		return err
+	}
 	if in.ID == valueobject.Nil {
 		return apperror.New(apperror.CodeValidation, "id is required", nil)
 	}
@@ -96,7 +103,10 @@ func (uc *UseCase) UpdateVenue(ctx context.Context, in UpdateVenueInput) error {
 	return uc.venues.Update(ctx, v)
 }
 
-func (uc *UseCase) DeleteVenue(ctx context.Context, id valueobject.UUID) error {
+func (uc *UseCase) DeleteVenue(ctx context.Context, actor authz.Actor, id valueobject.UUID) error {
+	if err := authz.RequireRole(actor, entity.UserRoleAdmin); err != nil {
+		return err
+	}
 	return uc.venues.Delete(ctx, id)
 }
 
@@ -110,7 +120,10 @@ type CreateRoomInput struct {
 	IsAvailable bool
 }
 
-func (uc *UseCase) CreateRoom(ctx context.Context, in CreateRoomInput) (valueobject.UUID, error) {
+func (uc *UseCase) CreateRoom(ctx context.Context, actor authz.Actor, in CreateRoomInput) (valueobject.UUID, error) {
+	if err := authz.RequireRole(actor, entity.UserRoleAdmin); err != nil {
+		return valueobject.Nil, err
+	}
 	if in.VenueID == valueobject.Nil {
 		return valueobject.Nil, apperror.New(apperror.CodeValidation, "venue_id is required", nil)
 	}
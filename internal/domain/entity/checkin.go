@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"time"
+
+	"time2meet/internal/domain/valueobject"
+)
+
+type CheckinDevice struct {
+	ID         valueobject.UUID
+	EventID    valueobject.UUID
+	Name       string
+	Gate       string
+	SecretHash string
+	CreatedBy  *valueobject.UUID
+	LastSeenAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+type CheckinResult string
+
+const (
+	CheckinResultAdmitted   CheckinResult = "admitted"
+	CheckinResultDuplicate  CheckinResult = "duplicate"
+	CheckinResultInvalid    CheckinResult = "invalid"
+	CheckinResultWrongEvent CheckinResult = "wrong_event"
+	CheckinResultRejected   CheckinResult = "rejected"
+)
+
+// Checkin is one scan attempt. EventID is the event being scanned for (nil when unknown),
+// TicketID is nil when the code could not be decoded.
+type Checkin struct {
+	ID         valueobject.UUID
+	EventID    *valueobject.UUID
+	TicketID   *valueobject.UUID
+	DeviceID   *valueobject.UUID
+	StaffID    *valueobject.UUID
+	Gate       string
+	Result     CheckinResult
+	Message    string
+	Offline    bool
+	ScannedAt  time.Time
+	RecordedAt time.Time
+}
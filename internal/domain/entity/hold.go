@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+
+	"time2meet/internal/domain/valueobject"
+)
+
+// TicketHold reserves Quantity tickets of a type for a user during checkout. Active holds
+// count against the ticket type's capacity until they are converted, released or expire.
+type TicketHold struct {
+	ID           valueobject.UUID
+	TicketTypeID valueobject.UUID
+	UserID       valueobject.UUID
+	Quantity     int
+	Status       valueobject.HoldStatus
+	ExpiresAt    time.Time
+	OrderID      *valueobject.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
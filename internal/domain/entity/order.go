@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"time"
+
+	"time2meet/internal/domain/valueobject"
+)
+
+// Order is one checkout: the line items it was placed with and their totals. The tickets
+// issued for it reference the order by id.
+type Order struct {
+	ID          valueobject.UUID
+	BuyerID     valueobject.UUID
+	Status      valueobject.OrderStatus
+	Currency    valueobject.Currency
+	TotalAmount valueobject.Money
+	TicketCount int
+	// PromoCodeID is the code redeemed by the order; DiscountAmount is the total it took off.
+	PromoCodeID    *valueobject.UUID
+	DiscountAmount valueobject.Money
+	// ClientIP is where the order was placed from, empty when unknown.
+	ClientIP  string
+	Items     []OrderItem
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type OrderItem struct {
+	ID           valueobject.UUID
+	OrderID      valueobject.UUID
+	TicketTypeID valueobject.UUID
+	Quantity     int
+	// UnitPrice is charged per ticket after the discount; DiscountAmount is the whole line's.
+	UnitPrice      valueobject.Money
+	LineTotal      valueobject.Money
+	DiscountAmount valueobject.Money
+}
+
+// PurchaseFlag marks an order that tripped a velocity check, for admins to review. Observed is
+// what was counted within the check's window and Threshold the limit it went over.
+type PurchaseFlag struct {
+	ID        valueobject.UUID
+	OrderID   valueobject.UUID
+	BuyerID   valueobject.UUID
+	ClientIP  string
+	Reason    valueobject.PurchaseFlagReason
+	Observed  int
+	Threshold int
+	CreatedAt time.Time
+}
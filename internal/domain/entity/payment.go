@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+
+	"time2meet/internal/domain/valueobject"
+)
+
+// Payment is one charge attempt for an order at a payment provider.
+type Payment struct {
+	ID       valueobject.UUID
+	OrderID  valueobject.UUID
+	Provider string
+	// IntentID is the provider's reference; empty until the intent has been created.
+	IntentID      string
+	Status        valueobject.PaymentStatus
+	Amount        valueobject.Money
+	Currency      valueobject.Currency
+	FailureReason string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
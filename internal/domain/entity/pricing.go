@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"time"
+
+	"time2meet/internal/domain/valueobject"
+)
+
+// PriceTier sells tickets at Price while fewer than UpToQuantity tickets of the type are sold
+// or reserved and before EndsAt. At least one of the limits is set on stored tiers.
+type PriceTier struct {
+	Price        valueobject.Money
+	UpToQuantity *int
+	EndsAt       *time.Time
+}
+
+// appliesAt reports whether the tier prices the next ticket when taken tickets are gone.
+func (t PriceTier) appliesAt(taken int, now time.Time) bool {
+	return (t.UpToQuantity == nil || taken < *t.UpToQuantity) && (t.EndsAt == nil || now.Before(*t.EndsAt))
+}
+
+// PriceTiers are a ticket type's tiers in the order they apply. The ticket type's own price
+// is the base price, charged once every tier has run out.
+type PriceTiers []PriceTier
+
+// Resolve returns the tier that prices the next ticket and the tier that will follow it. The
+// base price is returned as a tier without limits; next is nil when current is the base price.
+func (ts PriceTiers) Resolve(base valueobject.Money, taken int, now time.Time) (current PriceTier, next *PriceTier) {
+	i := 0
+	for i < len(ts) && !ts[i].appliesAt(taken, now) {
+		i++
+	}
+	if i == len(ts) {
+		return PriceTier{Price: base}, nil
+	}
+	for _, t := range ts[i+1:] {
+		if t.appliesAt(taken, now) {
+			return ts[i], &t
+		}
+	}
+	return ts[i], &PriceTier{Price: base}
+}
+
+// PricedQuantity is part of a purchase sold at one price.
+type PricedQuantity struct {
+	Price    valueobject.Money
+	Quantity int
+}
+
+// Split prices qty tickets bought together when taken tickets are already gone. A purchase
+// that crosses a tier's quantity cap is charged at more than one price.
+func (ts PriceTiers) Split(base valueobject.Money, taken, qty int, now time.Time) []PricedQuantity {
+	var out []PricedQuantity
+	for qty > 0 {
+		tier, _ := ts.Resolve(base, taken, now)
+		n := qty
+		if tier.UpToQuantity != nil && *tier.UpToQuantity-taken < n {
+			n = *tier.UpToQuantity - taken
+		}
+		if k := len(out); k > 0 && out[k-1].Price.Amount.Equal(tier.Price.Amount) {
+			out[k-1].Quantity += n
+		} else {
+			out = append(out, PricedQuantity{Price: tier.Price, Quantity: n})
+		}
+		taken += n
+		qty -= n
+	}
+	return out
+}
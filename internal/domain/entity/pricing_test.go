@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"time2meet/internal/domain/valueobject"
+
+	"github.com/shopspring/decimal"
+)
+
+func price(n int64) valueobject.Money {
+	return valueobject.Money{Amount: decimal.NewFromInt(n)}
+}
+
+func upTo(n int) *int { return &n }
+
+func ptr(n int64) *int64 { return &n }
+
+var pricingNow = time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func at(d time.Duration) *time.Time {
+	t := pricingNow.Add(d)
+	return &t
+}
+
+// tiers: early bird (10 tickets or until tomorrow), then regular (up to 50), then the base price.
+var testTiers = PriceTiers{
+	{Price: price(50), UpToQuantity: upTo(10), EndsAt: at(24 * time.Hour)},
+	{Price: price(80), UpToQuantity: upTo(50)},
+}
+
+func TestPriceTiersResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		tiers    PriceTiers
+		taken    int
+		now      time.Time
+		want     int64
+		wantNext *int64 // nil when the current price is the base price
+	}{
+		{name: "first tier", tiers: testTiers, taken: 0, now: pricingNow, want: 50, wantNext: ptr(80)},
+		{name: "last ticket of the first tier", tiers: testTiers, taken: 9, now: pricingNow, want: 50, wantNext: ptr(80)},
+		{name: "first tier sold out", tiers: testTiers, taken: 10, now: pricingNow, want: 80, wantNext: ptr(100)},
+		{name: "first tier expired", tiers: testTiers, taken: 3, now: pricingNow.Add(24 * time.Hour), want: 80, wantNext: ptr(100)},
+		{name: "first tier expires in a second", tiers: testTiers, taken: 3, now: pricingNow.Add(24*time.Hour - time.Second), want: 50, wantNext: ptr(80)},
+		{name: "every tier sold out", tiers: testTiers, taken: 50, now: pricingNow, want: 100},
+		{name: "no tiers", tiers: nil, taken: 0, now: pricingNow, want: 100},
+		{
+			name: "next skips a tier that has already expired",
+			tiers: PriceTiers{
+				{Price: price(40), UpToQuantity: upTo(5)},
+				{Price: price(60), EndsAt: at(-time.Hour)},
+				{Price: price(70), UpToQuantity: upTo(20)},
+			},
+			taken: 0, now: pricingNow, want: 40, wantNext: ptr(70),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cur, next := tc.tiers.Resolve(price(100), tc.taken, tc.now)
+			if !cur.Price.Amount.Equal(decimal.NewFromInt(tc.want)) {
+				t.Errorf("current price = %s, want %d", cur.Price.Amount, tc.want)
+			}
+			switch {
+			case tc.wantNext == nil && next != nil:
+				t.Errorf("next price = %s, want none", next.Price.Amount)
+			case tc.wantNext != nil && next == nil:
+				t.Errorf("no next price, want %d", *tc.wantNext)
+			case tc.wantNext != nil && !next.Price.Amount.Equal(decimal.NewFromInt(*tc.wantNext)):
+				t.Errorf("next price = %s, want %d", next.Price.Amount, *tc.wantNext)
+			}
+			if tc.wantNext == nil && (cur.UpToQuantity != nil || cur.EndsAt != nil) {
+				t.Errorf("base price returned with limits %+v", cur)
+			}
+		})
+	}
+}
+
+func TestPriceTiersSplit(t *testing.T) {
+	tests := []struct {
+		name  string
+		taken int
+		qty   int
+		now   time.Time
+		want  []PricedQuantity
+	}{
+		{name: "inside one tier", taken: 2, qty: 3, now: pricingNow, want: []PricedQuantity{{price(50), 3}}},
+		{name: "fills a tier exactly", taken: 7, qty: 3, now: pricingNow, want: []PricedQuantity{{price(50), 3}}},
+		{name: "crosses one cap", taken: 8, qty: 5, now: pricingNow, want: []PricedQuantity{{price(50), 2}, {price(80), 3}}},
+		{name: "crosses every cap", taken: 9, qty: 43, now: pricingNow, want: []PricedQuantity{{price(50), 1}, {price(80), 40}, {price(100), 2}}},
+		{name: "after the first tier expired", taken: 0, qty: 4, now: pricingNow.Add(48 * time.Hour), want: []PricedQuantity{{price(80), 4}}},
+		{name: "base price only", taken: 60, qty: 2, now: pricingNow, want: []PricedQuantity{{price(100), 2}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := testTiers.Split(price(100), tc.taken, tc.qty, tc.now)
+			if len(got) != len(tc.want) {
+				t.Fatalf("split = %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if !got[i].Price.Amount.Equal(tc.want[i].Price.Amount) || got[i].Quantity != tc.want[i].Quantity {
+					t.Fatalf("split = %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+
+	t.Run("tiers at one price are merged", func(t *testing.T) {
+		tiers := PriceTiers{{Price: price(50), UpToQuantity: upTo(2)}, {Price: price(50), UpToQuantity: upTo(4)}}
+		got := tiers.Split(price(50), 0, 6, pricingNow)
+		if len(got) != 1 || got[0].Quantity != 6 {
+			t.Fatalf("split = %v, want 6 at 50", got)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"time"
+
+	"time2meet/internal/domain/valueobject"
+
+	"github.com/shopspring/decimal"
+)
+
+// PromoCode takes a discount off each ticket of its event, or only of TicketTypeIDs when set.
+// Usage caps count orders: one order redeems a code once however many tickets it discounts.
+type PromoCode struct {
+	ID           valueobject.UUID
+	EventID      valueobject.UUID
+	Code         string
+	DiscountType valueobject.DiscountType
+	// DiscountValue is a percent (1-100) or, for fixed discounts, an amount in Currency.
+	DiscountValue decimal.Decimal
+	Currency      valueobject.Currency
+	TicketTypeIDs []valueobject.UUID
+	// MaxUses and MaxUsesPerUser are nil when unlimited.
+	MaxUses        *int
+	MaxUsesPerUser *int
+	UsedCount      int
+	ValidFrom      *time.Time
+	ValidUntil     *time.Time
+	IsActive       bool
+	CreatedBy      valueobject.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// AppliesTo reports whether the code discounts tickets of the given type.
+func (p PromoCode) AppliesTo(eventID, ticketTypeID valueobject.UUID, currency valueobject.Currency) bool {
+	if eventID != p.EventID {
+		return false
+	}
+	if p.DiscountType == valueobject.DiscountTypeFixed && currency != p.Currency {
+		return false
+	}
+	if len(p.TicketTypeIDs) == 0 {
+		return true
+	}
+	for _, id := range p.TicketTypeIDs {
+		if id == ticketTypeID {
+			return true
+		}
+	}
+	return false
+}
+
+// DiscountOn is the amount taken off one ticket of the given price; it never exceeds the price.
+func (p PromoCode) DiscountOn(price valueobject.Money) valueobject.Money {
+	off := p.DiscountValue
+	if p.DiscountType == valueobject.DiscountTypePercent {
+		off = price.Amount.Mul(p.DiscountValue).Div(decimal.NewFromInt(100)).Round(2)
+	}
+	if off.GreaterThan(price.Amount) {
+		off = price.Amount
+	}
+	return valueobject.Money{Amount: off}
+}
+
+// PromoRedemption records a code used by an order.
+type PromoRedemption struct {
+	ID             valueobject.UUID
+	PromoCodeID    valueobject.UUID
+	OrderID        valueobject.UUID
+	UserID         valueobject.UUID
+	DiscountAmount valueobject.Money
+	Currency       valueobject.Currency
+	Status         valueobject.RedemptionStatus
+	CreatedAt      time.Time
+}
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+
+	"time2meet/internal/domain/valueobject"
+)
+
+// Refund is a ledger record of money returned for a single ticket.
+type Refund struct {
+	ID       valueobject.UUID
+	TicketID valueobject.UUID
+	OrderID  *valueobject.UUID
+	// PaymentID is nil when nothing was charged through a provider (free or legacy tickets).
+	PaymentID *valueobject.UUID
+	Amount    valueobject.Money
+	Currency  valueobject.Currency
+	Status    valueobject.RefundStatus
+	// ProviderRefundID is the provider's reference; empty until the provider has answered.
+	ProviderRefundID string
+	Reason           string
+	FailureReason    string
+	RequestedBy      valueobject.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"time"
+
+	"time2meet/internal/domain/valueobject"
+)
+
+type AuthSession struct {
+	ID        valueobject.UUID
+	UserID    valueobject.UUID
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+	IP        string
+	CreatedAt time.Time
+}
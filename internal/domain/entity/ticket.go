@@ -7,31 +7,61 @@ import (
 )
 
 type TicketType struct {
-	ID            valueobject.UUID
-	EventID        valueobject.UUID
-	Name           string
-	Price          valueobject.Money
-	QuantityTotal  int
-	QuantitySold   int
-	SaleStart      *time.Time
-	SaleEnd        *time.Time
-	Description    string
-	IsActive       bool
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
+	ID      valueobject.UUID
+	EventID valueobject.UUID
+	Name    string
+	// Price is the base price, charged once every tier in PriceTiers has run out.
+	Price         valueobject.Money
+	PriceTiers    PriceTiers
+	Currency      valueobject.Currency
+	QuantityTotal int
+	QuantitySold  int
+	QuantityHeld  int
+	SaleStart     *time.Time
+	SaleEnd       *time.Time
+	Description   string
+	IsActive      bool
+	ReentryLimit  int
+	// MaxPerOrder and MaxPerUser limit how many tickets of the type one order and one buyer
+	// may take; nil means no limit.
+	MaxPerOrder *int
+	MaxPerUser  *int
+	// RefundPolicy applies until RefundDeadlineDays before the event starts.
+	RefundPolicy       valueobject.RefundPolicy
+	RefundDeadlineDays int
+	// RefundPercent is the share of the amount paid returned under RefundPolicyPartial.
+	RefundPercent int
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// PriceTierAt returns the tier that prices the next ticket at now and the one that follows
+// it, counting held tickets as taken like the purchase transaction does.
+func (t TicketType) PriceTierAt(now time.Time) (current PriceTier, next *PriceTier) {
+	return t.PriceTiers.Resolve(t.Price, t.QuantitySold+t.QuantityHeld, now)
 }
 
 type Ticket struct {
 	ID           valueobject.UUID
 	TicketTypeID valueobject.UUID
+	// BuyerID is the original purchaser; HolderID is who holds the ticket after transfers.
 	BuyerID      valueobject.UUID
+	HolderID     valueobject.UUID
+	OrderID      *valueobject.UUID
 	PurchaseDate time.Time
 	Status       valueobject.TicketStatus
 	QRCode       string
 	AmountPaid   valueobject.Money
-	UsedAt       *time.Time
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	// DiscountAmount is what a promo code took off the price; AmountPaid is already net of it.
+	DiscountAmount valueobject.Money
+	// Comp tickets were issued free by an organizer; CompKind is set for them only.
+	Comp       bool
+	CompKind   valueobject.CompKind
+	UsedAt     *time.Time
+	UsedBy     *valueobject.UUID
+	EntryCount int
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
 }
 
 type Registration struct {
@@ -39,10 +69,20 @@ type Registration struct {
 	UserID              valueobject.UUID
 	EventID             valueobject.UUID
 	Status              valueobject.RegistrationStatus
-	RegisteredAt         time.Time
+	RegisteredAt        time.Time
 	AttendanceConfirmed bool
 	Notes               string
 	CreatedAt           time.Time
 	UpdatedAt           time.Time
 }
 
+// TicketStatusChange records a manual ticket status change and who made it.
+type TicketStatusChange struct {
+	ID        valueobject.UUID
+	TicketID  valueobject.UUID
+	From      valueobject.TicketStatus
+	To        valueobject.TicketStatus
+	Reason    string
+	ChangedBy valueobject.UUID
+	CreatedAt time.Time
+}
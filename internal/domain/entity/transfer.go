@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+
+	"time2meet/internal/domain/valueobject"
+)
+
+// TicketTransfer is an offer of a ticket from its current holder to another user. Accepted
+// transfers form the ticket's chain of holders.
+type TicketTransfer struct {
+	ID         valueobject.UUID
+	TicketID   valueobject.UUID
+	FromUserID valueobject.UUID
+	ToEmail    valueobject.Email
+	// ToUserID is set once the recipient has accepted.
+	ToUserID   *valueobject.UUID
+	Status     valueobject.TransferStatus
+	ExpiresAt  time.Time
+	AcceptedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
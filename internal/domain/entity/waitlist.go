@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+
+	"time2meet/internal/domain/valueobject"
+)
+
+// WaitlistEntry is a user's place in the queue for a sold-out ticket type. When tickets are
+// freed the entry is offered a hold for its quantity, which it confirms like any other hold.
+type WaitlistEntry struct {
+	ID           valueobject.UUID
+	TicketTypeID valueobject.UUID
+	UserID       valueobject.UUID
+	Quantity     int
+	Status       valueobject.WaitlistStatus
+	// HoldID, OfferedAt and OfferExpiresAt are set once the entry has been offered tickets.
+	HoldID         *valueobject.UUID
+	OfferedAt      *time.Time
+	OfferExpiresAt *time.Time
+	// Position is the 1-based place among waiting entries of the ticket type; 0 when the entry
+	// is no longer waiting.
+	Position  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
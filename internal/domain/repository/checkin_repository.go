@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/valueobject"
+)
+
+type CheckinDeviceRepository interface {
+	Create(ctx context.Context, d entity.CheckinDevice) (valueobject.UUID, error)
+	GetByID(ctx context.Context, id valueobject.UUID) (entity.CheckinDevice, error)
+	ListByEventID(ctx context.Context, eventID valueobject.UUID) ([]entity.CheckinDevice, error)
+	Revoke(ctx context.Context, id valueobject.UUID) (bool, error)
+	TouchLastSeen(ctx context.Context, id valueobject.UUID, at time.Time) error
+}
+
+type CheckinRepository interface {
+	Create(ctx context.Context, c entity.Checkin) (valueobject.UUID, error)
+	ListByTicketID(ctx context.Context, ticketID valueobject.UUID, limit, offset int) ([]entity.Checkin, error)
+	ListByEventID(ctx context.Context, eventID valueobject.UUID, limit, offset int) ([]entity.Checkin, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/valueobject"
+)
+
+// TicketHoldRepository covers hold reads and expiry. Holds are created and converted inside
+// the purchase transaction (see port/tickettx).
+type TicketHoldRepository interface {
+	GetByID(ctx context.Context, id valueobject.UUID) (entity.TicketHold, error)
+	// ExpireDue marks up to limit active holds whose expires_at has passed as expired and
+	// returns how many were expired.
+	ExpireDue(ctx context.Context, now time.Time, limit int) (int, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/valueobject"
+)
+
+// OrderRepository reads orders. Orders are only written inside the purchase transaction.
+type OrderRepository interface {
+	// GetByID returns the order with its items.
+	GetByID(ctx context.Context, id valueobject.UUID) (entity.Order, error)
+	// ListByBuyerID returns orders without their items, newest first.
+	ListByBuyerID(ctx context.Context, buyerID valueobject.UUID, limit, offset int) ([]entity.Order, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/valueobject"
+)
+
+// PaymentRepository covers payment writes made outside the checkout transaction. Payments
+// are created and settled inside it (see port/tickettx).
+type PaymentRepository interface {
+	// SetIntentID records the provider's intent id once the gateway has accepted the intent.
+	SetIntentID(ctx context.Context, id valueobject.UUID, intentID string) error
+	GetByID(ctx context.Context, id valueobject.UUID) (entity.Payment, error)
+	// GetByOrderID returns the order's most recent payment.
+	GetByOrderID(ctx context.Context, orderID valueobject.UUID) (entity.Payment, error)
+	GetByIntentID(ctx context.Context, provider, intentID string) (entity.Payment, error)
+	// ListPending returns up to limit pending payments created before the given time, oldest first.
+	ListPending(ctx context.Context, createdBefore time.Time, limit int) ([]entity.Payment, error)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/valueobject"
+)
+
+// PromoCodeRepository manages promo codes. Codes are redeemed inside the purchase
+// transaction (see port/tickettx), never through this repository.
+type PromoCodeRepository interface {
+	Create(ctx context.Context, p entity.PromoCode) (valueobject.UUID, error)
+	GetByID(ctx context.Context, id valueobject.UUID) (entity.PromoCode, error)
+	ListByEventID(ctx context.Context, eventID valueobject.UUID) ([]entity.PromoCode, error)
+	// Update never writes used_count; it is maintained by redemptions.
+	Update(ctx context.Context, p entity.PromoCode) error
+	// Delete fails with CodeConflict once the code has been redeemed.
+	Delete(ctx context.Context, id valueobject.UUID) error
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/valueobject"
+)
+
+// RefundRepository reads the refund ledger. Refunds are written inside the refund
+// transaction (see port/tickettx).
+type RefundRepository interface {
+	ListByTicketID(ctx context.Context, ticketID valueobject.UUID) ([]entity.Refund, error)
+	// ListPending returns up to limit pending refunds created before the given time, oldest first.
+	ListPending(ctx context.Context, createdBefore time.Time, limit int) ([]entity.Refund, error)
+}
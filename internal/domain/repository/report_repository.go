@@ -8,11 +8,15 @@ import (
 )
 
 type SalesReportRow struct {
-	EventID      valueobject.UUID
-	EventTitle   string
-	TicketsSold  int64
-	Revenue      string
+	EventID     valueobject.UUID
+	EventTitle  string
+	TicketsSold int64
+	Revenue     string
+	// Discounts is what promo codes took off the tickets counted in Revenue.
+	Discounts    string
 	UniqueBuyers int64
+	// CompTickets counts complimentary tickets, which are left out of the other columns.
+	CompTickets int64
 }
 
 type AttendanceRow struct {
@@ -29,8 +33,45 @@ type PopularEventRow struct {
 	TicketsSold   int64
 }
 
+// FlaggedPurchaseRow is a velocity flag together with the order it was raised on.
+type FlaggedPurchaseRow struct {
+	FlagID      valueobject.UUID
+	OrderID     valueobject.UUID
+	BuyerID     valueobject.UUID
+	BuyerEmail  string
+	ClientIP    string
+	Reason      valueobject.PurchaseFlagReason
+	Observed    int
+	Threshold   int
+	OrderStatus valueobject.OrderStatus
+	TotalAmount string
+	Currency    string
+	TicketCount int
+	FlaggedAt   time.Time
+}
+
+// WaitlistReportRow summarises the waitlist of one ticket type. Offered counts open offers;
+// Purchased and Expired count offers that were confirmed or ran out.
+type WaitlistReportRow struct {
+	TicketTypeID   valueobject.UUID
+	TicketTypeName string
+	Waiting        int64
+	Offered        int64
+	Purchased      int64
+	Expired        int64
+	Cancelled      int64
+	// WaitingTickets is how many tickets the waiting entries ask for.
+	WaitingTickets int64
+}
+
 type ReportRepository interface {
 	SalesReport(ctx context.Context, start, end time.Time) ([]SalesReportRow, error)
 	AttendanceStats(ctx context.Context, eventID valueobject.UUID) ([]AttendanceRow, error)
-	PopularEvents(ctx context.Context, limit int, days int) ([]PopularEventRow, error)
+	// PopularEvents ranks events by registrations and tickets sold in the last days; a non-nil
+	// organizerID ranks only that organizer's events.
+	PopularEvents(ctx context.Context, limit int, days int, organizerID *valueobject.UUID) ([]PopularEventRow, error)
+	// FlaggedPurchases lists flags raised between start and end (inclusive dates), newest first.
+	FlaggedPurchases(ctx context.Context, start, end time.Time) ([]FlaggedPurchaseRow, error)
+	// WaitlistOffers returns one row per ticket type of the event that has a waitlist.
+	WaitlistOffers(ctx context.Context, eventID valueobject.UUID) ([]WaitlistReportRow, error)
 }
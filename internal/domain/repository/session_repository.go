@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/valueobject"
+)
+
+type AuthSessionRepository interface {
+	Create(ctx context.Context, s entity.AuthSession) (valueobject.UUID, error)
+	GetByID(ctx context.Context, id valueobject.UUID) (entity.AuthSession, error)
+	Revoke(ctx context.Context, id valueobject.UUID) (bool, error)
+}
@@ -15,11 +15,24 @@ type TicketTypeRepository interface {
 	Delete(ctx context.Context, id valueobject.UUID) error
 }
 
+// CheckinEntry is one admissible ticket in an offline check-in manifest.
+type CheckinEntry struct {
+	TicketID     valueobject.UUID
+	QRCode       string
+	Status       valueobject.TicketStatus
+	EntryCount   int
+	ReentryLimit int
+}
+
 type TicketRepository interface {
 	Create(ctx context.Context, t entity.Ticket) (valueobject.UUID, error)
 	GetByID(ctx context.Context, id valueobject.UUID) (entity.Ticket, error)
-	ListByBuyerID(ctx context.Context, buyerID valueobject.UUID, limit, offset int) ([]entity.Ticket, error)
-	UpdateStatus(ctx context.Context, id valueobject.UUID, status string) error
+	// ListByHolderID returns the tickets a user holds now, bought or received by transfer.
+	ListByHolderID(ctx context.Context, holderID valueobject.UUID, limit, offset int) ([]entity.Ticket, error)
+	ListByOrderID(ctx context.Context, orderID valueobject.UUID) ([]entity.Ticket, error)
+	// ListCheckinEntries returns paid and used tickets of an event, ordered by ticket id.
+	// Tickets with a pending refund are left out, as they may not be admitted.
+	ListCheckinEntries(ctx context.Context, eventID valueobject.UUID) ([]CheckinEntry, error)
 	Delete(ctx context.Context, id valueobject.UUID) error
 }
 
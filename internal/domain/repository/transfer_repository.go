@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/valueobject"
+)
+
+// TicketTransferRepository covers transfer reads and expiry. Transfers are created and
+// accepted inside the ticket transaction (see port/tickettx).
+type TicketTransferRepository interface {
+	GetByID(ctx context.Context, id valueobject.UUID) (entity.TicketTransfer, error)
+	// ListByTicketID returns every transfer of the ticket, oldest first.
+	ListByTicketID(ctx context.Context, ticketID valueobject.UUID) ([]entity.TicketTransfer, error)
+	// ListPendingByEmail returns the unexpired pending transfers offered to the email.
+	ListPendingByEmail(ctx context.Context, email valueobject.Email, now time.Time) ([]entity.TicketTransfer, error)
+	// ExpireDue marks up to limit pending transfers whose expires_at has passed as expired and
+	// returns how many were expired.
+	ExpireDue(ctx context.Context, now time.Time, limit int) (int, error)
+}
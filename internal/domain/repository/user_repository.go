@@ -13,6 +13,10 @@ type UserRepository interface {
 	GetByEmail(ctx context.Context, email string) (entity.User, error)
 	List(ctx context.Context, limit, offset int) ([]entity.User, error)
 	Update(ctx context.Context, u entity.User) error
+	UpdatePasswordHash(ctx context.Context, id valueobject.UUID, hash string) error
+	// ClaimPasswordHash sets the password of an active account that has none yet and reports
+	// whether it did.
+	ClaimPasswordHash(ctx context.Context, id valueobject.UUID, hash string) (bool, error)
 	Delete(ctx context.Context, id valueobject.UUID) error
 }
 
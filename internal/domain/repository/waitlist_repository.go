@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/valueobject"
+)
+
+// WaitlistRepository covers waitlist reads and offer bookkeeping. Entries are joined, offered
+// and left inside the ticket transaction (see port/tickettx).
+type WaitlistRepository interface {
+	GetByID(ctx context.Context, id valueobject.UUID) (entity.WaitlistEntry, error)
+	// ListByUserID returns the user's entries, newest first.
+	ListByUserID(ctx context.Context, userID valueobject.UUID, limit, offset int) ([]entity.WaitlistEntry, error)
+	// ListByTicketTypeID returns the open entries of the ticket type: offers first, then the
+	// queue in order.
+	ListByTicketTypeID(ctx context.Context, ticketTypeID valueobject.UUID, limit, offset int) ([]entity.WaitlistEntry, error)
+	// SettleOffers closes up to limit offered entries whose hold has been finished: converted
+	// holds become purchased, expired holds expired and released holds cancelled. It returns
+	// how many entries were closed.
+	SettleOffers(ctx context.Context, limit int) (int, error)
+	// ListOfferable returns up to limit ticket types on sale at now that have enough tickets
+	// available for at least one waiting entry.
+	ListOfferable(ctx context.Context, now time.Time, limit int) ([]valueobject.UUID, error)
+}
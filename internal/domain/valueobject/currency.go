@@ -0,0 +1,27 @@
+package valueobject
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultCurrency matches the ticket_types.currency column default.
+const DefaultCurrency Currency = "RUB"
+
+// Currency is an ISO 4217 alphabetic code.
+type Currency string
+
+func ParseCurrency(s string) (Currency, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if len(s) != 3 {
+		return "", fmt.Errorf("currency must be a 3-letter ISO 4217 code")
+	}
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return "", fmt.Errorf("currency must be a 3-letter ISO 4217 code")
+		}
+	}
+	return Currency(s), nil
+}
+
+func (c Currency) String() string { return string(c) }
@@ -0,0 +1,39 @@
+package valueobject
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	PasswordMinLen = 8
+	PasswordMaxLen = 128
+)
+
+// ValidatePassword enforces the password policy: 8..128 characters with at least one letter and one digit.
+func ValidatePassword(s string) error {
+	n := len([]rune(s))
+	if n < PasswordMinLen {
+		return fmt.Errorf("password must be at least %d characters", PasswordMinLen)
+	}
+	if n > PasswordMaxLen {
+		return fmt.Errorf("password must be at most %d characters", PasswordMaxLen)
+	}
+	if strings.TrimSpace(s) != s {
+		return fmt.Errorf("password must not start or end with whitespace")
+	}
+	var hasLetter, hasDigit bool
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	if !hasLetter || !hasDigit {
+		return fmt.Errorf("password must contain letters and digits")
+	}
+	return nil
+}
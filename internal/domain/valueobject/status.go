@@ -23,21 +23,40 @@ func (s EventStatus) Validate() error {
 type TicketStatus string
 
 const (
-	TicketStatusPaid     TicketStatus = "paid"
-	TicketStatusRefunded TicketStatus = "refunded"
-	TicketStatusVoid     TicketStatus = "void"
-	TicketStatusUsed     TicketStatus = "used"
+	// TicketStatusPendingPayment reserves capacity until the order's payment settles.
+	TicketStatusPendingPayment TicketStatus = "pending_payment"
+	TicketStatusPaid           TicketStatus = "paid"
+	TicketStatusRefunded       TicketStatus = "refunded"
+	TicketStatusVoid           TicketStatus = "void"
+	TicketStatusUsed           TicketStatus = "used"
 )
 
 func (s TicketStatus) Validate() error {
 	switch s {
-	case TicketStatusPaid, TicketStatusRefunded, TicketStatusVoid, TicketStatusUsed:
+	case TicketStatusPendingPayment, TicketStatusPaid, TicketStatusRefunded, TicketStatusVoid, TicketStatusUsed:
 		return nil
 	default:
 		return fmt.Errorf("invalid ticket status: %q", s)
 	}
 }
 
+// ticketTransitions lists where a ticket may go from each status. Used, refunded and void
+// are final.
+var ticketTransitions = map[TicketStatus][]TicketStatus{
+	TicketStatusPendingPayment: {TicketStatusPaid, TicketStatusVoid},
+	TicketStatusPaid:           {TicketStatusUsed, TicketStatusRefunded, TicketStatusVoid},
+}
+
+// CanTransitionTo reports whether a ticket in status s may be moved to status to.
+func (s TicketStatus) CanTransitionTo(to TicketStatus) bool {
+	for _, next := range ticketTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
 type RegistrationStatus string
 
 const (
@@ -55,3 +74,190 @@ func (s RegistrationStatus) Validate() error {
 		return fmt.Errorf("invalid registration status: %q", s)
 	}
 }
+
+type OrderStatus string
+
+const (
+	OrderStatusPendingPayment OrderStatus = "pending_payment"
+	OrderStatusPaid           OrderStatus = "paid"
+	OrderStatusPaymentFailed  OrderStatus = "payment_failed"
+	OrderStatusRefunded       OrderStatus = "refunded"
+)
+
+func (s OrderStatus) Validate() error {
+	switch s {
+	case OrderStatusPendingPayment, OrderStatusPaid, OrderStatusPaymentFailed, OrderStatusRefunded:
+		return nil
+	default:
+		return fmt.Errorf("invalid order status: %q", s)
+	}
+}
+
+type HoldStatus string
+
+const (
+	HoldStatusActive    HoldStatus = "active"
+	HoldStatusConverted HoldStatus = "converted"
+	HoldStatusExpired   HoldStatus = "expired"
+	HoldStatusReleased  HoldStatus = "released"
+)
+
+func (s HoldStatus) Validate() error {
+	switch s {
+	case HoldStatusActive, HoldStatusConverted, HoldStatusExpired, HoldStatusReleased:
+		return nil
+	default:
+		return fmt.Errorf("invalid hold status: %q", s)
+	}
+}
+
+type PaymentStatus string
+
+const (
+	PaymentStatusPending   PaymentStatus = "pending"
+	PaymentStatusSucceeded PaymentStatus = "succeeded"
+	PaymentStatusFailed    PaymentStatus = "failed"
+	PaymentStatusRefunded  PaymentStatus = "refunded"
+)
+
+func (s PaymentStatus) Validate() error {
+	switch s {
+	case PaymentStatusPending, PaymentStatusSucceeded, PaymentStatusFailed, PaymentStatusRefunded:
+		return nil
+	default:
+		return fmt.Errorf("invalid payment status: %q", s)
+	}
+}
+
+type RefundStatus string
+
+const (
+	RefundStatusPending   RefundStatus = "pending"
+	RefundStatusSucceeded RefundStatus = "succeeded"
+	RefundStatusFailed    RefundStatus = "failed"
+)
+
+func (s RefundStatus) Validate() error {
+	switch s {
+	case RefundStatusPending, RefundStatusSucceeded, RefundStatusFailed:
+		return nil
+	default:
+		return fmt.Errorf("invalid refund status: %q", s)
+	}
+}
+
+// RefundPolicy decides how much of a ticket's price a buyer gets back.
+type RefundPolicy string
+
+const (
+	// RefundPolicyFull refunds the whole amount paid until the deadline.
+	RefundPolicyFull RefundPolicy = "full"
+	// RefundPolicyPartial refunds a fixed percentage of the amount paid until the deadline.
+	RefundPolicyPartial RefundPolicy = "partial"
+	RefundPolicyNone    RefundPolicy = "none"
+)
+
+func (p RefundPolicy) Validate() error {
+	switch p {
+	case RefundPolicyFull, RefundPolicyPartial, RefundPolicyNone:
+		return nil
+	default:
+		return fmt.Errorf("invalid refund policy: %q", p)
+	}
+}
+
+type TransferStatus string
+
+const (
+	TransferStatusPending   TransferStatus = "pending"
+	TransferStatusAccepted  TransferStatus = "accepted"
+	TransferStatusCancelled TransferStatus = "cancelled"
+	TransferStatusExpired   TransferStatus = "expired"
+)
+
+func (s TransferStatus) Validate() error {
+	switch s {
+	case TransferStatusPending, TransferStatusAccepted, TransferStatusCancelled, TransferStatusExpired:
+		return nil
+	default:
+		return fmt.Errorf("invalid transfer status: %q", s)
+	}
+}
+
+type DiscountType string
+
+const (
+	DiscountTypePercent DiscountType = "percent"
+	DiscountTypeFixed   DiscountType = "fixed"
+)
+
+func (t DiscountType) Validate() error {
+	switch t {
+	case DiscountTypePercent, DiscountTypeFixed:
+		return nil
+	default:
+		return fmt.Errorf("invalid discount type: %q", t)
+	}
+}
+
+type RedemptionStatus string
+
+const (
+	RedemptionStatusApplied RedemptionStatus = "applied"
+	// RedemptionStatusReleased gives the use back to the code, e.g. after a failed payment.
+	RedemptionStatusReleased RedemptionStatus = "released"
+)
+
+// PurchaseFlagReason names the velocity check an order tripped.
+type PurchaseFlagReason string
+
+const (
+	// PurchaseFlagIPOrderVelocity: too many orders from one IP within the window.
+	PurchaseFlagIPOrderVelocity PurchaseFlagReason = "ip_order_velocity"
+	// PurchaseFlagIPBuyerVelocity: too many different buyers ordering from one IP within the window.
+	PurchaseFlagIPBuyerVelocity PurchaseFlagReason = "ip_buyer_velocity"
+)
+
+type WaitlistStatus string
+
+const (
+	WaitlistStatusWaiting WaitlistStatus = "waiting"
+	// WaitlistStatusOffered: tickets are held for the entry until its offer expires.
+	WaitlistStatusOffered   WaitlistStatus = "offered"
+	WaitlistStatusPurchased WaitlistStatus = "purchased"
+	// WaitlistStatusExpired: the offer was not confirmed in time.
+	WaitlistStatusExpired   WaitlistStatus = "expired"
+	WaitlistStatusCancelled WaitlistStatus = "cancelled"
+)
+
+func (s WaitlistStatus) Validate() error {
+	switch s {
+	case WaitlistStatusWaiting, WaitlistStatusOffered, WaitlistStatusPurchased, WaitlistStatusExpired, WaitlistStatusCancelled:
+		return nil
+	default:
+		return fmt.Errorf("invalid waitlist status: %q", s)
+	}
+}
+
+// Open reports whether the entry still waits for or holds an offer.
+func (s WaitlistStatus) Open() bool {
+	return s == WaitlistStatusWaiting || s == WaitlistStatusOffered
+}
+
+// CompKind says who a complimentary ticket was issued to.
+type CompKind string
+
+const (
+	CompKindGuest CompKind = "guest"
+	CompKindPress CompKind = "press"
+	CompKindStaff CompKind = "staff"
+)
+
+func (k CompKind) Validate() error {
+	switch k {
+	case CompKindGuest, CompKindPress, CompKindStaff:
+		return nil
+	default:
+		return fmt.Errorf("invalid comp kind: %q", k)
+	}
+}
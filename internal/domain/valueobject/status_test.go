@@ -0,0 +1,37 @@
+package valueobject
+
+import "testing"
+
+func TestTicketStatusCanTransitionTo(t *testing.T) {
+	all := []TicketStatus{TicketStatusPendingPayment, TicketStatusPaid, TicketStatusRefunded, TicketStatusVoid, TicketStatusUsed}
+	allowed := map[[2]TicketStatus]bool{
+		{TicketStatusPendingPayment, TicketStatusPaid}: true,
+		{TicketStatusPendingPayment, TicketStatusVoid}: true,
+		{TicketStatusPaid, TicketStatusUsed}:           true,
+		{TicketStatusPaid, TicketStatusRefunded}:       true,
+		{TicketStatusPaid, TicketStatusVoid}:           true,
+	}
+	for _, from := range all {
+		for _, to := range all {
+			want := allowed[[2]TicketStatus{from, to}]
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s -> %s: CanTransitionTo = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+
+	// Final statuses must never come back, whoever changes the status.
+	for _, tc := range [][2]TicketStatus{
+		{TicketStatusUsed, TicketStatusPaid},
+		{TicketStatusRefunded, TicketStatusUsed},
+		{TicketStatusRefunded, TicketStatusPaid},
+		{TicketStatusVoid, TicketStatusPaid},
+	} {
+		if tc[0].CanTransitionTo(tc[1]) {
+			t.Errorf("%s -> %s is allowed", tc[0], tc[1])
+		}
+	}
+	if TicketStatus("unknown").CanTransitionTo(TicketStatusPaid) {
+		t.Error("unknown status may become paid")
+	}
+}
@@ -22,3 +22,5 @@ func ParseUUID(s string) (UUID, error) {
 	}
 	return u, nil
 }
+
+func NewUUID() UUID { return uuid.New() }
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"time2meet/internal/application/port/passwordhash"
+	"time2meet/pkg/apperror"
+
+	"golang.org/x/crypto/argon2"
+)
+
+type Argon2Params struct {
+	Memory  uint32 // KiB
+	Time    uint32
+	Threads uint8
+	SaltLen uint32
+	KeyLen  uint32
+}
+
+// DefaultArgon2Params follows the OWASP baseline for argon2id.
+var DefaultArgon2Params = Argon2Params{
+	Memory:  64 * 1024,
+	Time:    3,
+	Threads: 2,
+	SaltLen: 16,
+	KeyLen:  32,
+}
+
+// Upper bounds for hashes being verified; anything above is treated as an unknown format.
+const (
+	argon2MaxMemory  = 256 * 1024 // KiB
+	argon2MaxTime    = 16
+	argon2MaxThreads = 16
+	argon2MaxKeyLen  = 128
+)
+
+// Argon2Hasher produces PHC-encoded argon2id hashes and still verifies legacy bcrypt hashes,
+// which are reported by NeedsRehash so they get upgraded on the next successful login.
+type Argon2Hasher struct {
+	params Argon2Params
+	legacy *BcryptHasher
+}
+
+func NewArgon2Hasher(params Argon2Params) *Argon2Hasher {
+	return &Argon2Hasher{params: params, legacy: NewBcryptHasher()}
+}
+
+var _ passwordhash.Hasher = (*Argon2Hasher)(nil)
+
+func (h *Argon2Hasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", apperror.New(apperror.CodeInternal, "generate salt failed", err)
+	}
+	key := argon2.IDKey([]byte(password), salt, h.params.Time, h.params.Memory, h.params.Threads, h.params.KeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.params.Memory, h.params.Time, h.params.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *Argon2Hasher) Verify(hash, password string) (bool, error) {
+	if isBcryptHash(hash) {
+		return h.legacy.Verify(hash, password)
+	}
+	p, salt, key, err := decodeArgon2(hash)
+	if err != nil {
+		// Unknown hash formats never match.
+		return false, nil
+	}
+	other := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1, nil
+}
+
+func (h *Argon2Hasher) NeedsRehash(hash string) bool {
+	p, salt, key, err := decodeArgon2(hash)
+	if err != nil {
+		return true
+	}
+	return p.Memory != h.params.Memory ||
+		p.Time != h.params.Time ||
+		p.Threads != h.params.Threads ||
+		uint32(len(salt)) != h.params.SaltLen ||
+		uint32(len(key)) != h.params.KeyLen
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func decodeArgon2(hash string) (Argon2Params, []byte, []byte, error) {
+	// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return Argon2Params{}, nil, nil, fmt.Errorf("not an argon2id hash")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return Argon2Params{}, nil, nil, fmt.Errorf("unsupported argon2 version")
+	}
+	var p Argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Threads); err != nil {
+		return Argon2Params{}, nil, nil, fmt.Errorf("invalid argon2 params: %w", err)
+	}
+	// Hashes come from the database, but a tampered row must not make a login burn
+	// gigabytes or minutes, nor panic on zero cost.
+	if p.Memory == 0 || p.Memory > argon2MaxMemory || p.Time == 0 || p.Time > argon2MaxTime ||
+		p.Threads == 0 || p.Threads > argon2MaxThreads {
+		return Argon2Params{}, nil, nil, fmt.Errorf("argon2 params out of range")
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2Params{}, nil, nil, fmt.Errorf("invalid argon2 salt: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 || len(key) > argon2MaxKeyLen {
+		return Argon2Params{}, nil, nil, fmt.Errorf("invalid argon2 key")
+	}
+	return p, salt, key, nil
+}
@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArgon2RejectsOutOfRangeParams(t *testing.T) {
+	h := NewArgon2Hasher(Argon2Params{Memory: 1024, Time: 1, Threads: 1, SaltLen: 16, KeyLen: 32})
+	hash, err := h.Hash("correct horse 42")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if ok, err := h.Verify(hash, "correct horse 42"); err != nil || !ok {
+		t.Fatalf("Verify = %v, %v; want a match", ok, err)
+	}
+
+	for _, params := range []string{"m=4294967295,t=1,p=1", "m=1024,t=4294967295,p=1", "m=1024,t=1,p=255", "m=0,t=1,p=1", "m=1024,t=0,p=1", "m=1024,t=1,p=0"} {
+		tampered := strings.Replace(hash, "m=1024,t=1,p=1", params, 1)
+		if ok, err := h.Verify(tampered, "correct horse 42"); err != nil || ok {
+			t.Errorf("%s: Verify = %v, %v; want no match", params, ok, err)
+		}
+		if !h.NeedsRehash(tampered) {
+			t.Errorf("%s: NeedsRehash = false", params)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package auth
+
+import (
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BcryptHasher only verifies hashes created before the switch to argon2id.
+type BcryptHasher struct{}
+
+func NewBcryptHasher() *BcryptHasher { return &BcryptHasher{} }
+
+func (h *BcryptHasher) Verify(hash, password string) (bool, error) {
+	// Mismatches and unknown hash formats (e.g. legacy seed values) are both treated as "no match".
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
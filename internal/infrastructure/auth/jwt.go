@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"time2meet/internal/application/port/authtoken"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const jwtIssuer = "time2meet"
+
+type JWTIssuer struct {
+	secret []byte
+}
+
+func NewJWTIssuer(secret string) *JWTIssuer { return &JWTIssuer{secret: []byte(secret)} }
+
+var _ authtoken.Issuer = (*JWTIssuer)(nil)
+
+type jwtClaims struct {
+	Kind      authtoken.Kind `json:"typ"`
+	Role      string         `json:"role,omitempty"`
+	SessionID string         `json:"sid,omitempty"`
+	jwt.RegisteredClaims
+}
+
+func (i *JWTIssuer) Issue(kind authtoken.Kind, c authtoken.Claims) (string, error) {
+	now := time.Now().UTC()
+	claims := jwtClaims{
+		Kind: kind,
+		Role: string(c.Role),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    jwtIssuer,
+			Subject:   c.UserID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(c.ExpiresAt),
+		},
+	}
+	if c.SessionID != valueobject.Nil {
+		claims.SessionID = c.SessionID.String()
+	}
+	s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(i.secret)
+	if err != nil {
+		return "", apperror.New(apperror.CodeInternal, "sign token failed", err)
+	}
+	return s, nil
+}
+
+func (i *JWTIssuer) Parse(kind authtoken.Kind, token string) (authtoken.Claims, error) {
+	var claims jwtClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return i.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(jwtIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return authtoken.Claims{}, apperror.New(apperror.CodeUnauthorized, "token expired", err)
+		}
+		return authtoken.Claims{}, apperror.New(apperror.CodeUnauthorized, "invalid token", err)
+	}
+	if claims.Kind != kind {
+		return authtoken.Claims{}, apperror.New(apperror.CodeUnauthorized, "invalid token type", nil)
+	}
+	uid, err := valueobject.ParseUUID(claims.Subject)
+	if err != nil {
+		return authtoken.Claims{}, apperror.New(apperror.CodeUnauthorized, "invalid token subject", err)
+	}
+	out := authtoken.Claims{
+		UserID:    uid,
+		Role:      entity.UserRole(claims.Role),
+		ExpiresAt: claims.ExpiresAt.Time,
+	}
+	if claims.SessionID != "" {
+		sid, err := valueobject.ParseUUID(claims.SessionID)
+		if err != nil {
+			return authtoken.Claims{}, apperror.New(apperror.CodeUnauthorized, "invalid token session", err)
+		}
+		out.SessionID = sid
+	}
+	return out, nil
+}
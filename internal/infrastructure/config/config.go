@@ -1,9 +1,14 @@
 package config
 
 import (
+	"crypto/ed25519"
+	"encoding/base64"
 	"fmt"
+	"net"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type DatabaseConfig struct {
@@ -17,11 +22,90 @@ type DatabaseConfig struct {
 
 type HTTPConfig struct {
 	Addr string
+	// TrustedProxies are the IPs or CIDRs whose X-Forwarded-For is believed. Empty trusts no
+	// proxy: the client IP is the peer address.
+	TrustedProxies []string
+}
+
+type AuthConfig struct {
+	JWTSecret  string
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+	// ClaimTTL is how long a mailed account claim token can be used.
+	ClaimTTL time.Duration
+	// DevHeaders enables the legacy X-User-Id/X-User-Role headers. Never enable outside local development.
+	DevHeaders bool
+}
+
+type QRConfig struct {
+	// SigningKeys maps key id to HMAC secret. Retired keys stay here until every ticket
+	// signed with them has been re-issued or the event is over.
+	SigningKeys map[string]string
+	ActiveKeyID string
+	// ManifestKeyID and ManifestKey sign offline check-in manifests. The key is asymmetric so
+	// that scanner devices, which hold its public half, cannot forge QR codes or manifests.
+	ManifestKeyID string
+	ManifestKey   ed25519.PrivateKey
+}
+
+type HoldConfig struct {
+	// TTL is how long a checkout hold reserves tickets before it expires.
+	TTL time.Duration
+	// SweepInterval is how often expired holds are returned to the pool.
+	SweepInterval time.Duration
+}
+
+type TransferConfig struct {
+	// TTL is how long the recipient has to accept a ticket transfer.
+	TTL time.Duration
+	// SweepInterval is how often unaccepted transfers are marked expired.
+	SweepInterval time.Duration
+}
+
+type WaitlistConfig struct {
+	// OfferTTL is how long tickets offered to a waitlisted user stay held for them.
+	OfferTTL time.Duration
+	// OfferInterval is how often freed tickets are offered to waitlists.
+	OfferInterval time.Duration
+}
+
+type IdempotencyConfig struct {
+	// TTL is how long a stored response is replayed for retries with the same key.
+	TTL time.Duration
+}
+
+type PaymentConfig struct {
+	// Provider selects the payment gateway. Only the in-process "fake" provider exists so far.
+	Provider string
+	// FakeMode makes the fake provider succeed, fail or time out.
+	FakeMode string
+	// WebhookSecret authenticates provider webhooks; without it every webhook is rejected.
+	WebhookSecret string
+	// ReconcileInterval is how often payments and refunds stuck in pending are re-queried from
+	// the provider; ReconcileAfter is how long they must have been pending first.
+	ReconcileInterval time.Duration
+	ReconcileAfter    time.Duration
+}
+
+type PurchaseConfig struct {
+	// VelocityWindow is how far back orders from one IP are counted. Orders over either limit
+	// are flagged for review, not rejected; a zero limit disables its check.
+	VelocityWindow    time.Duration
+	VelocityMaxOrders int
+	VelocityMaxBuyers int
 }
 
 type Config struct {
-	Database DatabaseConfig
-	HTTP     HTTPConfig
+	Database    DatabaseConfig
+	HTTP        HTTPConfig
+	Auth        AuthConfig
+	QR          QRConfig
+	Hold        HoldConfig
+	Transfer    TransferConfig
+	Waitlist    WaitlistConfig
+	Idempotency IdempotencyConfig
+	Payment     PaymentConfig
+	Purchase    PurchaseConfig
 }
 
 func LoadFromEnv() (Config, error) {
@@ -41,6 +125,73 @@ func LoadFromEnv() (Config, error) {
 	cfg.Database.Port = port
 
 	cfg.HTTP.Addr = getEnv("HTTP_ADDR", ":8080")
+	if cfg.HTTP.TrustedProxies, err = getNetList("HTTP_TRUSTED_PROXIES"); err != nil {
+		return Config{}, err
+	}
+
+	cfg.Auth.JWTSecret = os.Getenv("AUTH_JWT_SECRET")
+	if cfg.Auth.AccessTTL, err = getDuration("AUTH_ACCESS_TTL", 15*time.Minute); err != nil {
+		return Config{}, err
+	}
+	if cfg.Auth.RefreshTTL, err = getDuration("AUTH_REFRESH_TTL", 30*24*time.Hour); err != nil {
+		return Config{}, err
+	}
+	if cfg.Auth.ClaimTTL, err = getDuration("AUTH_CLAIM_TTL", 24*time.Hour); err != nil {
+		return Config{}, err
+	}
+	if cfg.Auth.DevHeaders, err = getBool("AUTH_DEV_HEADERS", false); err != nil {
+		return Config{}, err
+	}
+
+	if cfg.QR.SigningKeys, err = getKeyMap("QR_SIGNING_KEYS"); err != nil {
+		return Config{}, err
+	}
+	cfg.QR.ActiveKeyID = os.Getenv("QR_ACTIVE_KEY_ID")
+	if cfg.QR.ManifestKeyID, cfg.QR.ManifestKey, err = getEd25519Key("MANIFEST_SIGNING_KEY"); err != nil {
+		return Config{}, err
+	}
+
+	if cfg.Hold.TTL, err = getDuration("HOLD_TTL", 10*time.Minute); err != nil {
+		return Config{}, err
+	}
+	if cfg.Hold.SweepInterval, err = getDuration("HOLD_SWEEP_INTERVAL", 30*time.Second); err != nil {
+		return Config{}, err
+	}
+	if cfg.Transfer.TTL, err = getDuration("TRANSFER_TTL", 72*time.Hour); err != nil {
+		return Config{}, err
+	}
+	if cfg.Transfer.SweepInterval, err = getDuration("TRANSFER_SWEEP_INTERVAL", 5*time.Minute); err != nil {
+		return Config{}, err
+	}
+	if cfg.Waitlist.OfferTTL, err = getDuration("WAITLIST_OFFER_TTL", 30*time.Minute); err != nil {
+		return Config{}, err
+	}
+	if cfg.Waitlist.OfferInterval, err = getDuration("WAITLIST_OFFER_INTERVAL", 30*time.Second); err != nil {
+		return Config{}, err
+	}
+	if cfg.Idempotency.TTL, err = getDuration("IDEMPOTENCY_TTL", 24*time.Hour); err != nil {
+		return Config{}, err
+	}
+
+	cfg.Payment.Provider = getEnv("PAYMENT_PROVIDER", "fake")
+	cfg.Payment.FakeMode = getEnv("PAYMENT_FAKE_MODE", "succeed")
+	cfg.Payment.WebhookSecret = os.Getenv("PAYMENT_WEBHOOK_SECRET")
+	if cfg.Payment.ReconcileInterval, err = getDuration("PAYMENT_RECONCILE_INTERVAL", time.Minute); err != nil {
+		return Config{}, err
+	}
+	if cfg.Payment.ReconcileAfter, err = getDuration("PAYMENT_RECONCILE_AFTER", 5*time.Minute); err != nil {
+		return Config{}, err
+	}
+
+	if cfg.Purchase.VelocityWindow, err = getDuration("PURCHASE_VELOCITY_WINDOW", 10*time.Minute); err != nil {
+		return Config{}, err
+	}
+	if cfg.Purchase.VelocityMaxOrders, err = getInt("PURCHASE_VELOCITY_MAX_ORDERS", 10); err != nil {
+		return Config{}, err
+	}
+	if cfg.Purchase.VelocityMaxBuyers, err = getInt("PURCHASE_VELOCITY_MAX_BUYERS", 3); err != nil {
+		return Config{}, err
+	}
 
 	if cfg.Database.Name == "" {
 		return Config{}, fmt.Errorf("DB_NAME is required")
@@ -51,6 +202,27 @@ func LoadFromEnv() (Config, error) {
 	if cfg.Database.Pass == "" {
 		return Config{}, fmt.Errorf("DB_PASSWORD is required")
 	}
+	if len(cfg.Auth.JWTSecret) < 32 {
+		return Config{}, fmt.Errorf("AUTH_JWT_SECRET is required (at least 32 bytes)")
+	}
+	if len(cfg.QR.SigningKeys) == 0 {
+		return Config{}, fmt.Errorf("QR_SIGNING_KEYS is required")
+	}
+	if _, ok := cfg.QR.SigningKeys[cfg.QR.ActiveKeyID]; !ok {
+		return Config{}, fmt.Errorf("QR_ACTIVE_KEY_ID must name one of QR_SIGNING_KEYS")
+	}
+	if cfg.QR.ManifestKey == nil {
+		return Config{}, fmt.Errorf("MANIFEST_SIGNING_KEY is required")
+	}
+
+	if cfg.Payment.Provider != "fake" {
+		return Config{}, fmt.Errorf("invalid PAYMENT_PROVIDER: %q", cfg.Payment.Provider)
+	}
+	switch cfg.Payment.FakeMode {
+	case "succeed", "fail", "timeout":
+	default:
+		return Config{}, fmt.Errorf("invalid PAYMENT_FAKE_MODE: %q", cfg.Payment.FakeMode)
+	}
 
 	return cfg, nil
 }
@@ -61,3 +233,102 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getDuration(key string, fallback time.Duration) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid %s: %q", key, v)
+	}
+	return d, nil
+}
+
+// getInt parses a non-negative integer; zero is allowed so a limit can be switched off.
+func getInt(key string, fallback int) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s: %q", key, v)
+	}
+	return n, nil
+}
+
+func getBool(key string, fallback bool) (bool, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %q", key, v)
+	}
+	return b, nil
+}
+
+// getNetList parses a comma-separated list of IPs and CIDRs.
+func getNetList(key string) ([]string, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil, nil
+	}
+	var out []string
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+		if _, _, err := net.ParseCIDR(part); err != nil && net.ParseIP(part) == nil {
+			return nil, fmt.Errorf("invalid %s: %q is not an IP or CIDR", key, part)
+		}
+		out = append(out, part)
+	}
+	return out, nil
+}
+
+// getKeyMap parses "id1:secret1,id2:secret2". Secrets must be at least 32 bytes.
+func getKeyMap(key string) (map[string]string, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil, nil
+	}
+	out := make(map[string]string)
+	for _, part := range strings.Split(v, ",") {
+		id, secret, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok || id == "" || strings.ContainsAny(id, ".") {
+			return nil, fmt.Errorf("invalid %s: entries must be id:secret", key)
+		}
+		if len(secret) < 32 {
+			return nil, fmt.Errorf("invalid %s: secret for %q must be at least 32 bytes", key, id)
+		}
+		if _, dup := out[id]; dup {
+			return nil, fmt.Errorf("invalid %s: duplicate key id %q", key, id)
+		}
+		out[id] = secret
+	}
+	return out, nil
+}
+
+// getEd25519Key reads "id:seed", where seed is the base64 (standard or url, padding optional)
+// 32-byte Ed25519 seed.
+func getEd25519Key(key string) (string, ed25519.PrivateKey, error) {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return "", nil, nil
+	}
+	id, enc, ok := strings.Cut(v, ":")
+	if !ok || id == "" || strings.ContainsAny(id, ".") {
+		return "", nil, fmt.Errorf("invalid %s: must be id:base64-seed", key)
+	}
+	enc = strings.TrimRight(enc, "=")
+	seed, err := base64.RawStdEncoding.DecodeString(enc)
+	if err != nil {
+		seed, err = base64.RawURLEncoding.DecodeString(enc)
+	}
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return "", nil, fmt.Errorf("invalid %s: seed must be %d bytes of base64", key, ed25519.SeedSize)
+	}
+	return id, ed25519.NewKeyFromSeed(seed), nil
+}
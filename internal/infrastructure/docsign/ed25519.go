@@ -0,0 +1,39 @@
+package docsign
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+
+	"time2meet/internal/application/port/docsign"
+	"time2meet/pkg/apperror"
+)
+
+var b64 = base64.RawURLEncoding
+
+// Ed25519Signer signs documents with an Ed25519 key. Signatures and public keys are
+// unpadded base64url.
+type Ed25519Signer struct {
+	keyID string
+	key   ed25519.PrivateKey
+}
+
+// NewEd25519Signer expects a key already validated by config; without one Sign fails.
+func NewEd25519Signer(keyID string, key ed25519.PrivateKey) *Ed25519Signer {
+	return &Ed25519Signer{keyID: keyID, key: key}
+}
+
+var _ docsign.Signer = (*Ed25519Signer)(nil)
+
+func (s *Ed25519Signer) Sign(doc []byte) (string, string, error) {
+	if len(s.key) != ed25519.PrivateKeySize {
+		return "", "", apperror.New(apperror.CodeInternal, "document signing key is not configured", nil)
+	}
+	return s.keyID, b64.EncodeToString(ed25519.Sign(s.key, doc)), nil
+}
+
+func (s *Ed25519Signer) PublicKey() (string, string) {
+	if len(s.key) != ed25519.PrivateKeySize {
+		return s.keyID, ""
+	}
+	return s.keyID, b64.EncodeToString(s.key.Public().(ed25519.PublicKey))
+}
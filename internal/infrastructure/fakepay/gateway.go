@@ -0,0 +1,238 @@
+package fakepay
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"time2meet/internal/application/port/payment"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mode decides how the fake answers captures and refunds.
+type Mode string
+
+const (
+	ModeSucceed Mode = "succeed"
+	ModeFail    Mode = "fail"
+	// ModeTimeout applies the operation but reports a timeout, as when the provider's
+	// response is lost on the way back. Only a webhook or reconciliation reveals the outcome.
+	ModeTimeout Mode = "timeout"
+)
+
+// Gateway is an in-process payment provider for development and tests. It keeps intents
+// in memory and records the webhook events a real provider would send.
+type Gateway struct {
+	secret []byte
+
+	mu          sync.Mutex
+	mode        Mode
+	intents     map[string]payment.Intent
+	intentByKey map[string]string
+	refunds     map[string]payment.Refund
+	refundByKey map[string]string
+	events      []payment.Event
+}
+
+func New(mode Mode, webhookSecret string) *Gateway {
+	return &Gateway{
+		secret:      []byte(webhookSecret),
+		mode:        mode,
+		intents:     make(map[string]payment.Intent),
+		intentByKey: make(map[string]string),
+		refunds:     make(map[string]payment.Refund),
+		refundByKey: make(map[string]string),
+	}
+}
+
+var _ payment.Gateway = (*Gateway)(nil)
+
+// SetMode changes how subsequent captures and refunds behave.
+func (g *Gateway) SetMode(m Mode) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.mode = m
+}
+
+func (g *Gateway) Name() string { return "fake" }
+
+func (g *Gateway) CreateIntent(ctx context.Context, in payment.CreateIntentInput) (payment.Intent, error) {
+	if err := ctx.Err(); err != nil {
+		return payment.Intent{}, timeout(err)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if id, ok := g.intentByKey[in.IdempotencyKey]; ok && in.IdempotencyKey != "" {
+		return g.intents[id], nil
+	}
+	it := payment.Intent{
+		ID:       "pi_" + uuid.NewString(),
+		Status:   payment.IntentRequiresCapture,
+		Amount:   in.Amount,
+		Currency: in.Currency,
+	}
+	g.intents[it.ID] = it
+	if in.IdempotencyKey != "" {
+		g.intentByKey[in.IdempotencyKey] = it.ID
+	}
+	return it, nil
+}
+
+func (g *Gateway) GetIntent(ctx context.Context, intentID string) (payment.Intent, error) {
+	if err := ctx.Err(); err != nil {
+		return payment.Intent{}, timeout(err)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	it, ok := g.intents[intentID]
+	if !ok {
+		return payment.Intent{}, apperror.New(apperror.CodeNotFound, "payment intent not found", nil)
+	}
+	return it, nil
+}
+
+func (g *Gateway) Capture(ctx context.Context, intentID string) (payment.Intent, error) {
+	if err := ctx.Err(); err != nil {
+		return payment.Intent{}, timeout(err)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	it, ok := g.intents[intentID]
+	if !ok {
+		return payment.Intent{}, apperror.New(apperror.CodeNotFound, "payment intent not found", nil)
+	}
+	if it.Status != payment.IntentRequiresCapture {
+		return it, nil
+	}
+	if g.mode == ModeFail {
+		it.Status, it.FailureReason = payment.IntentFailed, "card_declined"
+		g.emit(payment.Event{Type: payment.EventIntentFailed, IntentID: it.ID, Reason: it.FailureReason})
+	} else {
+		it.Status = payment.IntentSucceeded
+		g.emit(payment.Event{Type: payment.EventIntentSucceeded, IntentID: it.ID})
+	}
+	g.intents[it.ID] = it
+	if g.mode == ModeTimeout {
+		return payment.Intent{}, timeout(context.DeadlineExceeded)
+	}
+	return it, nil
+}
+
+func (g *Gateway) Refund(ctx context.Context, intentID string, amount valueobject.Money, idempotencyKey string) (payment.Refund, error) {
+	if err := ctx.Err(); err != nil {
+		return payment.Refund{}, timeout(err)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if id, ok := g.refundByKey[idempotencyKey]; ok && idempotencyKey != "" {
+		return g.refunds[id], nil
+	}
+	it, ok := g.intents[intentID]
+	if !ok {
+		return payment.Refund{}, apperror.New(apperror.CodeNotFound, "payment intent not found", nil)
+	}
+	if it.Status != payment.IntentSucceeded {
+		return payment.Refund{}, apperror.New(apperror.CodeInvalidState, "payment intent is "+string(it.Status), nil)
+	}
+	refunded := decimal.Zero
+	for _, r := range g.refunds {
+		if r.IntentID == intentID && r.Status == payment.RefundSucceeded {
+			refunded = refunded.Add(r.Amount.Amount)
+		}
+	}
+	r := payment.Refund{ID: "re_" + uuid.NewString(), IntentID: intentID, Amount: amount, Status: payment.RefundSucceeded}
+	if g.mode == ModeFail || refunded.Add(amount.Amount).GreaterThan(it.Amount.Amount) {
+		r.Status = payment.RefundFailed
+	} else {
+		g.emit(payment.Event{Type: payment.EventRefundSucceeded, IntentID: intentID, RefundID: r.ID})
+	}
+	g.refunds[r.ID] = r
+	if idempotencyKey != "" {
+		g.refundByKey[idempotencyKey] = r.ID
+	}
+	if g.mode == ModeTimeout {
+		return payment.Refund{}, timeout(context.DeadlineExceeded)
+	}
+	return r, nil
+}
+
+// webhookBody is the JSON the fake sends (and expects) as a webhook payload.
+type webhookBody struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	IntentID   string    `json:"intent_id"`
+	RefundID   string    `json:"refund_id,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// VerifyWebhook expects signature to be the hex HMAC-SHA256 of the payload under the
+// webhook secret. Without a configured secret every webhook is rejected.
+func (g *Gateway) VerifyWebhook(payload []byte, signature string) (payment.Event, error) {
+	sig, err := hex.DecodeString(signature)
+	if len(g.secret) == 0 || err != nil || !hmac.Equal(sig, g.mac(payload)) {
+		return payment.Event{}, apperror.New(apperror.CodeUnauthorized, "invalid webhook signature", err)
+	}
+	var b webhookBody
+	if err := json.Unmarshal(payload, &b); err != nil {
+		return payment.Event{}, apperror.New(apperror.CodeValidation, "invalid webhook payload", err)
+	}
+	if b.ID == "" || b.IntentID == "" {
+		return payment.Event{}, apperror.New(apperror.CodeValidation, "webhook id and intent_id are required", nil)
+	}
+	return payment.Event{
+		ID:         b.ID,
+		Type:       payment.EventType(b.Type),
+		IntentID:   b.IntentID,
+		RefundID:   b.RefundID,
+		Reason:     b.Reason,
+		OccurredAt: b.OccurredAt,
+	}, nil
+}
+
+// Events returns the notifications emitted so far, oldest first.
+func (g *Gateway) Events() []payment.Event {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]payment.Event(nil), g.events...)
+}
+
+// EncodeWebhook renders an event as the signed payload the fake provider would deliver.
+func (g *Gateway) EncodeWebhook(e payment.Event) (payload []byte, signature string, err error) {
+	payload, err = json.Marshal(webhookBody{
+		ID:         e.ID,
+		Type:       string(e.Type),
+		IntentID:   e.IntentID,
+		RefundID:   e.RefundID,
+		Reason:     e.Reason,
+		OccurredAt: e.OccurredAt,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, hex.EncodeToString(g.mac(payload)), nil
+}
+
+func (g *Gateway) emit(e payment.Event) {
+	e.ID = "evt_" + uuid.NewString()
+	e.OccurredAt = time.Now().UTC()
+	g.events = append(g.events, e)
+}
+
+func (g *Gateway) mac(payload []byte) []byte {
+	m := hmac.New(sha256.New, g.secret)
+	m.Write(payload)
+	return m.Sum(nil)
+}
+
+func timeout(cause error) error {
+	return apperror.New(apperror.CodeUnavailable, "payment gateway timed out", cause)
+}
@@ -0,0 +1,258 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/internal/infrastructure/persistence/postgres/dto"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type CheckinDeviceRepo struct{ db *sqlx.DB }
+
+func NewCheckinDeviceRepo(db *sqlx.DB) *CheckinDeviceRepo { return &CheckinDeviceRepo{db: db} }
+
+var _ repository.CheckinDeviceRepository = (*CheckinDeviceRepo)(nil)
+
+func (r *CheckinDeviceRepo) Create(ctx context.Context, d entity.CheckinDevice) (valueobject.UUID, error) {
+	q := `
+		INSERT INTO checkin_devices (id, event_id, name, gate, secret_hash, created_by)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)
+	`
+	if _, err := r.db.ExecContext(ctx, q, d.ID.String(), d.EventID.String(), d.Name, d.Gate, d.SecretHash, uuidArg(d.CreatedBy)); err != nil {
+		if isUniqueViolation(err) {
+			return valueobject.Nil, apperror.New(apperror.CodeConflict, "device with this name is already registered for the event", err)
+		}
+		if isForeignKeyViolation(err) {
+			return valueobject.Nil, apperror.New(apperror.CodeNotFound, "event not found", err)
+		}
+		return valueobject.Nil, apperror.New(apperror.CodeInternal, "create checkin device failed", err)
+	}
+	return d.ID, nil
+}
+
+func (r *CheckinDeviceRepo) GetByID(ctx context.Context, id valueobject.UUID) (entity.CheckinDevice, error) {
+	q := `
+		SELECT id, event_id, name, gate, secret_hash, created_by, last_seen_at, revoked_at, created_at
+		FROM checkin_devices
+		WHERE id = $1
+	`
+	var row dto.CheckinDeviceRow
+	if err := r.db.GetContext(ctx, &row, q, id.String()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.CheckinDevice{}, apperror.New(apperror.CodeNotFound, "checkin device not found", err)
+		}
+		return entity.CheckinDevice{}, apperror.New(apperror.CodeInternal, "get checkin device failed", err)
+	}
+	return mapCheckinDeviceRow(row)
+}
+
+func (r *CheckinDeviceRepo) ListByEventID(ctx context.Context, eventID valueobject.UUID) ([]entity.CheckinDevice, error) {
+	q := `
+		SELECT id, event_id, name, gate, secret_hash, created_by, last_seen_at, revoked_at, created_at
+		FROM checkin_devices
+		WHERE event_id = $1
+		ORDER BY created_at, name
+	`
+	var rows []dto.CheckinDeviceRow
+	if err := r.db.SelectContext(ctx, &rows, q, eventID.String()); err != nil {
+		return nil, apperror.New(apperror.CodeInternal, "list checkin devices failed", err)
+	}
+	out := make([]entity.CheckinDevice, 0, len(rows))
+	for _, row := range rows {
+		d, err := mapCheckinDeviceRow(row)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+// Revoke marks the device as revoked and reports whether it was still active.
+func (r *CheckinDeviceRepo) Revoke(ctx context.Context, id valueobject.UUID) (bool, error) {
+	q := `UPDATE checkin_devices SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+	res, err := r.db.ExecContext(ctx, q, id.String())
+	if err != nil {
+		return false, apperror.New(apperror.CodeInternal, "revoke checkin device failed", err)
+	}
+	aff, _ := res.RowsAffected()
+	return aff > 0, nil
+}
+
+// TouchLastSeen records device activity. Writes are throttled to one per minute per device.
+func (r *CheckinDeviceRepo) TouchLastSeen(ctx context.Context, id valueobject.UUID, at time.Time) error {
+	q := `
+		UPDATE checkin_devices
+		SET last_seen_at = $2
+		WHERE id = $1
+		  AND (last_seen_at IS NULL OR last_seen_at < $2 - INTERVAL '1 minute')
+	`
+	if _, err := r.db.ExecContext(ctx, q, id.String(), at); err != nil {
+		return apperror.New(apperror.CodeInternal, "update checkin device failed", err)
+	}
+	return nil
+}
+
+type CheckinRepo struct{ db *sqlx.DB }
+
+func NewCheckinRepo(db *sqlx.DB) *CheckinRepo { return &CheckinRepo{db: db} }
+
+var _ repository.CheckinRepository = (*CheckinRepo)(nil)
+
+func (r *CheckinRepo) Create(ctx context.Context, c entity.Checkin) (valueobject.UUID, error) {
+	return insertCheckin(ctx, r.db, c)
+}
+
+func (r *CheckinRepo) ListByTicketID(ctx context.Context, ticketID valueobject.UUID, limit, offset int) ([]entity.Checkin, error) {
+	return r.list(ctx, "ticket_id", ticketID, limit, offset)
+}
+
+func (r *CheckinRepo) ListByEventID(ctx context.Context, eventID valueobject.UUID, limit, offset int) ([]entity.Checkin, error) {
+	return r.list(ctx, "event_id", eventID, limit, offset)
+}
+
+func (r *CheckinRepo) list(ctx context.Context, column string, id valueobject.UUID, limit, offset int) ([]entity.Checkin, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	// column is one of the fixed names above, never user input.
+	q := `
+		SELECT id, event_id, ticket_id, device_id, staff_id, gate, result, message, offline, scanned_at, recorded_at
+		FROM checkins
+		WHERE ` + column + ` = $1
+		ORDER BY scanned_at DESC, recorded_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	var rows []dto.CheckinRow
+	if err := r.db.SelectContext(ctx, &rows, q, id.String(), limit, offset); err != nil {
+		return nil, apperror.New(apperror.CodeInternal, "list checkins failed", err)
+	}
+	out := make([]entity.Checkin, 0, len(rows))
+	for _, row := range rows {
+		c, err := mapCheckinRow(row)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// insertCheckin is shared by CheckinRepo and the in-transaction ticket queries, so admissions
+// are recorded atomically with the ticket update.
+func insertCheckin(ctx context.Context, db sqlx.ExtContext, c entity.Checkin) (valueobject.UUID, error) {
+	id := c.ID
+	if id == valueobject.Nil {
+		id = valueobject.NewUUID()
+	}
+	q := `
+		INSERT INTO checkins (id, event_id, ticket_id, device_id, staff_id, gate, result, message, offline, scanned_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, NULLIF($8, ''), $9, $10)
+	`
+	if _, err := db.ExecContext(ctx, q,
+		id.String(), uuidArg(c.EventID), uuidArg(c.TicketID), uuidArg(c.DeviceID), uuidArg(c.StaffID),
+		c.Gate, string(c.Result), c.Message, c.Offline, c.ScannedAt,
+	); err != nil {
+		return valueobject.Nil, apperror.New(apperror.CodeInternal, "record checkin failed", err)
+	}
+	return id, nil
+}
+
+func mapCheckinDeviceRow(row dto.CheckinDeviceRow) (entity.CheckinDevice, error) {
+	id, err := valueobject.ParseUUID(row.ID)
+	if err != nil {
+		return entity.CheckinDevice{}, apperror.New(apperror.CodeInternal, "invalid checkin device id in db", err)
+	}
+	eventID, err := valueobject.ParseUUID(row.EventID)
+	if err != nil {
+		return entity.CheckinDevice{}, apperror.New(apperror.CodeInternal, "invalid checkin device event_id in db", err)
+	}
+	createdBy, err := parseNullUUID(row.CreatedBy)
+	if err != nil {
+		return entity.CheckinDevice{}, apperror.New(apperror.CodeInternal, "invalid checkin device created_by in db", err)
+	}
+	d := entity.CheckinDevice{
+		ID:         id,
+		EventID:    eventID,
+		Name:       row.Name,
+		Gate:       row.Gate.String,
+		SecretHash: row.SecretHash,
+		CreatedBy:  createdBy,
+	}
+	if row.LastSeenAt.Valid {
+		t := row.LastSeenAt.Time
+		d.LastSeenAt = &t
+	}
+	if row.RevokedAt.Valid {
+		t := row.RevokedAt.Time
+		d.RevokedAt = &t
+	}
+	if row.CreatedAt.Valid {
+		d.CreatedAt = row.CreatedAt.Time
+	}
+	return d, nil
+}
+
+func mapCheckinRow(row dto.CheckinRow) (entity.Checkin, error) {
+	id, err := valueobject.ParseUUID(row.ID)
+	if err != nil {
+		return entity.Checkin{}, apperror.New(apperror.CodeInternal, "invalid checkin id in db", err)
+	}
+	c := entity.Checkin{
+		ID:      id,
+		Gate:    row.Gate.String,
+		Result:  entity.CheckinResult(row.Result),
+		Message: row.Message.String,
+		Offline: row.Offline,
+	}
+	for _, f := range []struct {
+		src sql.NullString
+		dst **valueobject.UUID
+	}{
+		{row.EventID, &c.EventID},
+		{row.TicketID, &c.TicketID},
+		{row.DeviceID, &c.DeviceID},
+		{row.StaffID, &c.StaffID},
+	} {
+		if *f.dst, err = parseNullUUID(f.src); err != nil {
+			return entity.Checkin{}, apperror.New(apperror.CodeInternal, "invalid checkin reference in db", err)
+		}
+	}
+	if row.ScannedAt.Valid {
+		c.ScannedAt = row.ScannedAt.Time
+	}
+	if row.RecordedAt.Valid {
+		c.RecordedAt = row.RecordedAt.Time
+	}
+	return c, nil
+}
+
+// uuidArg converts an optional id to a query argument, NULL when absent.
+func uuidArg(id *valueobject.UUID) any {
+	if id == nil || *id == valueobject.Nil {
+		return nil
+	}
+	return id.String()
+}
+
+func parseNullUUID(s sql.NullString) (*valueobject.UUID, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+	id, err := valueobject.ParseUUID(s.String)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
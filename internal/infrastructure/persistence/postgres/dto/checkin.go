@@ -0,0 +1,29 @@
+package dto
+
+import "database/sql"
+
+type CheckinDeviceRow struct {
+	ID         string         `db:"id"`
+	EventID    string         `db:"event_id"`
+	Name       string         `db:"name"`
+	Gate       sql.NullString `db:"gate"`
+	SecretHash string         `db:"secret_hash"`
+	CreatedBy  sql.NullString `db:"created_by"`
+	LastSeenAt sql.NullTime   `db:"last_seen_at"`
+	RevokedAt  sql.NullTime   `db:"revoked_at"`
+	CreatedAt  sql.NullTime   `db:"created_at"`
+}
+
+type CheckinRow struct {
+	ID         string         `db:"id"`
+	EventID    sql.NullString `db:"event_id"`
+	TicketID   sql.NullString `db:"ticket_id"`
+	DeviceID   sql.NullString `db:"device_id"`
+	StaffID    sql.NullString `db:"staff_id"`
+	Gate       sql.NullString `db:"gate"`
+	Result     string         `db:"result"`
+	Message    sql.NullString `db:"message"`
+	Offline    bool           `db:"offline"`
+	ScannedAt  sql.NullTime   `db:"scanned_at"`
+	RecordedAt sql.NullTime   `db:"recorded_at"`
+}
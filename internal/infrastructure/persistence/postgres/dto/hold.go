@@ -0,0 +1,15 @@
+package dto
+
+import "database/sql"
+
+type TicketHoldRow struct {
+	ID           string         `db:"id"`
+	TicketTypeID string         `db:"ticket_type_id"`
+	UserID       string         `db:"user_id"`
+	Quantity     int            `db:"quantity"`
+	Status       string         `db:"status"`
+	ExpiresAt    sql.NullTime   `db:"expires_at"`
+	OrderID      sql.NullString `db:"order_id"`
+	CreatedAt    sql.NullTime   `db:"created_at"`
+	UpdatedAt    sql.NullTime   `db:"updated_at"`
+}
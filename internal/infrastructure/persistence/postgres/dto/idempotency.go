@@ -0,0 +1,11 @@
+package dto
+
+import "database/sql"
+
+type IdempotencyKeyRow struct {
+	Fingerprint string         `db:"fingerprint"`
+	Completed   bool           `db:"completed"`
+	StatusCode  sql.NullInt64  `db:"status_code"`
+	ContentType sql.NullString `db:"content_type"`
+	Body        []byte         `db:"body"`
+}
@@ -0,0 +1,32 @@
+package dto
+
+import "database/sql"
+
+type OrderRow struct {
+	ID             string         `db:"id"`
+	BuyerID        string         `db:"buyer_id"`
+	Status         string         `db:"status"`
+	Currency       string         `db:"currency"`
+	TotalAmount    string         `db:"total_amount"`
+	TicketCount    int            `db:"ticket_count"`
+	PromoCodeID    sql.NullString `db:"promo_code_id"`
+	DiscountAmount string         `db:"discount_amount"`
+	ClientIP       sql.NullString `db:"client_ip"`
+	CreatedAt      sql.NullTime   `db:"created_at"`
+	UpdatedAt      sql.NullTime   `db:"updated_at"`
+}
+
+type OrderItemRow struct {
+	ID             string `db:"id"`
+	OrderID        string `db:"order_id"`
+	TicketTypeID   string `db:"ticket_type_id"`
+	Quantity       int    `db:"quantity"`
+	UnitPrice      string `db:"unit_price"`
+	LineTotal      string `db:"line_total"`
+	DiscountAmount string `db:"discount_amount"`
+}
+
+type IPOrderCountRow struct {
+	Orders int `db:"orders"`
+	Buyers int `db:"buyers"`
+}
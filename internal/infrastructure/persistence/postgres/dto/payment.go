@@ -0,0 +1,16 @@
+package dto
+
+import "database/sql"
+
+type PaymentRow struct {
+	ID            string         `db:"id"`
+	OrderID       string         `db:"order_id"`
+	Provider      string         `db:"provider"`
+	IntentID      sql.NullString `db:"intent_id"`
+	Status        string         `db:"status"`
+	Amount        string         `db:"amount"`
+	Currency      string         `db:"currency"`
+	FailureReason sql.NullString `db:"failure_reason"`
+	CreatedAt     sql.NullTime   `db:"created_at"`
+	UpdatedAt     sql.NullTime   `db:"updated_at"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+type PromoCodeRow struct {
+	ID             string         `db:"id"`
+	EventID        string         `db:"event_id"`
+	Code           string         `db:"code"`
+	DiscountType   string         `db:"discount_type"`
+	DiscountValue  string         `db:"discount_value"`
+	Currency       sql.NullString `db:"currency"`
+	TicketTypeIDs  pq.StringArray `db:"ticket_type_ids"`
+	MaxUses        sql.NullInt64  `db:"max_uses"`
+	MaxUsesPerUser sql.NullInt64  `db:"max_uses_per_user"`
+	UsedCount      int            `db:"used_count"`
+	ValidFrom      sql.NullTime   `db:"valid_from"`
+	ValidUntil     sql.NullTime   `db:"valid_until"`
+	IsActive       bool           `db:"is_active"`
+	CreatedBy      sql.NullString `db:"created_by"`
+	CreatedAt      sql.NullTime   `db:"created_at"`
+	UpdatedAt      sql.NullTime   `db:"updated_at"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "database/sql"
+
+type RefundRow struct {
+	ID               string         `db:"id"`
+	TicketID         string         `db:"ticket_id"`
+	OrderID          sql.NullString `db:"order_id"`
+	PaymentID        sql.NullString `db:"payment_id"`
+	Amount           string         `db:"amount"`
+	Currency         string         `db:"currency"`
+	Status           string         `db:"status"`
+	ProviderRefundID sql.NullString `db:"provider_refund_id"`
+	Reason           sql.NullString `db:"reason"`
+	FailureReason    sql.NullString `db:"failure_reason"`
+	RequestedBy      sql.NullString `db:"requested_by"`
+	CreatedAt        sql.NullTime   `db:"created_at"`
+	UpdatedAt        sql.NullTime   `db:"updated_at"`
+}
@@ -1,11 +1,15 @@
 package dto
 
+import "time"
+
 type SalesReportRow struct {
 	EventID      string `db:"event_id"`
 	EventTitle   string `db:"event_title"`
 	TicketsSold  int64  `db:"tickets_sold"`
 	Revenue      string `db:"revenue"`
+	Discounts    string `db:"discounts"`
 	UniqueBuyers int64  `db:"unique_buyers"`
+	CompTickets  int64  `db:"comp_tickets"`
 }
 
 type AttendanceRow struct {
@@ -22,4 +26,29 @@ type PopularEventRow struct {
 	TicketsSold   int64  `db:"tickets_sold"`
 }
 
+type FlaggedPurchaseRow struct {
+	FlagID      string    `db:"flag_id"`
+	OrderID     string    `db:"order_id"`
+	BuyerID     string    `db:"buyer_id"`
+	BuyerEmail  string    `db:"buyer_email"`
+	ClientIP    string    `db:"client_ip"`
+	Reason      string    `db:"reason"`
+	Observed    int       `db:"observed"`
+	Threshold   int       `db:"threshold"`
+	OrderStatus string    `db:"order_status"`
+	TotalAmount string    `db:"total_amount"`
+	Currency    string    `db:"currency"`
+	TicketCount int       `db:"ticket_count"`
+	FlaggedAt   time.Time `db:"flagged_at"`
+}
 
+type WaitlistReportRow struct {
+	TicketTypeID   string `db:"ticket_type_id"`
+	TicketTypeName string `db:"ticket_type_name"`
+	Waiting        int64  `db:"waiting"`
+	Offered        int64  `db:"offered"`
+	Purchased      int64  `db:"purchased"`
+	Expired        int64  `db:"expired"`
+	Cancelled      int64  `db:"cancelled"`
+	WaitingTickets int64  `db:"waiting_tickets"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+import "database/sql"
+
+type AuthSessionRow struct {
+	ID        string         `db:"id"`
+	UserID    string         `db:"user_id"`
+	ExpiresAt sql.NullTime   `db:"expires_at"`
+	RevokedAt sql.NullTime   `db:"revoked_at"`
+	IPAddress sql.NullString `db:"ip_address"`
+	CreatedAt sql.NullTime   `db:"created_at"`
+}
@@ -1,34 +1,92 @@
 package dto
 
-import "database/sql"
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
 
 type TicketTypeRow struct {
+	ID                 string         `db:"id"`
+	EventID            string         `db:"event_id"`
+	Name               string         `db:"name"`
+	Price              string         `db:"price"`
+	Currency           string         `db:"currency"`
+	QuantityTotal      int            `db:"quantity_total"`
+	QuantitySold       int            `db:"quantity_sold"`
+	QuantityHeld       int            `db:"quantity_held"`
+	SaleStart          sql.NullTime   `db:"sale_start"`
+	SaleEnd            sql.NullTime   `db:"sale_end"`
+	Description        sql.NullString `db:"description"`
+	IsActive           bool           `db:"is_active"`
+	ReentryLimit       int            `db:"reentry_limit"`
+	RefundPolicy       string         `db:"refund_policy"`
+	RefundDeadlineDays int            `db:"refund_deadline_days"`
+	RefundPercent      int            `db:"refund_percent"`
+	MaxPerOrder        sql.NullInt64  `db:"max_per_order"`
+	MaxPerUser         sql.NullInt64  `db:"max_per_user"`
+	// PriceTiers is a JSON array of PriceTierJSON in the order the tiers apply.
+	PriceTiers json.RawMessage `db:"price_tiers"`
+	CreatedAt  sql.NullTime    `db:"created_at"`
+	UpdatedAt  sql.NullTime    `db:"updated_at"`
+}
+
+type PriceTierJSON struct {
+	Price        json.Number `json:"price"`
+	UpToQuantity *int        `json:"up_to_quantity"`
+	EndsAt       *time.Time  `json:"ends_at"`
+}
+
+type LockedTicketTypeRow struct {
+	TicketTypeRow
+	EventStatus   string       `db:"event_status"`
+	EventStartsAt sql.NullTime `db:"event_starts_at"`
+}
+
+type LockedTicketRow struct {
 	ID            string         `db:"id"`
+	TicketTypeID  string         `db:"ticket_type_id"`
 	EventID       string         `db:"event_id"`
-	Name          string         `db:"name"`
-	Price         string         `db:"price"`
-	Currency      string         `db:"currency"`
-	QuantityTotal int            `db:"quantity_total"`
-	QuantitySold  int            `db:"quantity_sold"`
-	SaleStart     sql.NullTime   `db:"sale_start"`
-	SaleEnd       sql.NullTime   `db:"sale_end"`
-	Description   sql.NullString `db:"description"`
-	IsActive      bool           `db:"is_active"`
-	CreatedAt     sql.NullTime   `db:"created_at"`
-	UpdatedAt     sql.NullTime   `db:"updated_at"`
+	BuyerID       string         `db:"buyer_id"`
+	HolderID      string         `db:"holder_id"`
+	OrderID       sql.NullString `db:"order_id"`
+	Status        string         `db:"status"`
+	QRCode        string         `db:"qr_code"`
+	AmountPaid    string         `db:"amount_paid"`
+	UsedAt        sql.NullTime   `db:"used_at"`
+	UsedBy        sql.NullString `db:"used_by"`
+	UsedByDevice  sql.NullString `db:"used_by_device_id"`
+	EntryCount    int            `db:"entry_count"`
+	ReentryLimit  int            `db:"reentry_limit"`
+	RefundPending bool           `db:"refund_pending"`
+}
+
+type CheckinEntryRow struct {
+	ID           string `db:"id"`
+	QRCode       string `db:"qr_code"`
+	Status       string `db:"status"`
+	EntryCount   int    `db:"entry_count"`
+	ReentryLimit int    `db:"reentry_limit"`
 }
 
 type TicketRow struct {
-	ID           string       `db:"id"`
-	TicketTypeID string       `db:"ticket_type_id"`
-	BuyerID      string       `db:"buyer_id"`
-	PurchaseDate sql.NullTime `db:"purchase_date"`
-	Status       string       `db:"status"`
-	QRCode       string       `db:"qr_code"`
-	AmountPaid   string       `db:"amount_paid"`
-	UsedAt       sql.NullTime `db:"used_at"`
-	CreatedAt    sql.NullTime `db:"created_at"`
-	UpdatedAt    sql.NullTime `db:"updated_at"`
+	ID             string         `db:"id"`
+	TicketTypeID   string         `db:"ticket_type_id"`
+	BuyerID        string         `db:"buyer_id"`
+	HolderID       string         `db:"holder_id"`
+	OrderID        sql.NullString `db:"order_id"`
+	PurchaseDate   sql.NullTime   `db:"purchase_date"`
+	Status         string         `db:"status"`
+	QRCode         string         `db:"qr_code"`
+	AmountPaid     string         `db:"amount_paid"`
+	DiscountAmount string         `db:"discount_amount"`
+	IsComp         bool           `db:"is_comp"`
+	CompKind       sql.NullString `db:"comp_kind"`
+	UsedAt         sql.NullTime   `db:"used_at"`
+	UsedBy         sql.NullString `db:"used_by"`
+	EntryCount     int            `db:"entry_count"`
+	CreatedAt      sql.NullTime   `db:"created_at"`
+	UpdatedAt      sql.NullTime   `db:"updated_at"`
 }
 
 type RegistrationRow struct {
@@ -42,5 +100,3 @@ type RegistrationRow struct {
 	CreatedAt           sql.NullTime   `db:"created_at"`
 	UpdatedAt           sql.NullTime   `db:"updated_at"`
 }
-
-
@@ -0,0 +1,16 @@
+package dto
+
+import "database/sql"
+
+type TicketTransferRow struct {
+	ID         string         `db:"id"`
+	TicketID   string         `db:"ticket_id"`
+	FromUserID string         `db:"from_user_id"`
+	ToEmail    string         `db:"to_email"`
+	ToUserID   sql.NullString `db:"to_user_id"`
+	Status     string         `db:"status"`
+	ExpiresAt  sql.NullTime   `db:"expires_at"`
+	AcceptedAt sql.NullTime   `db:"accepted_at"`
+	CreatedAt  sql.NullTime   `db:"created_at"`
+	UpdatedAt  sql.NullTime   `db:"updated_at"`
+}
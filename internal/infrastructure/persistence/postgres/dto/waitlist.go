@@ -0,0 +1,17 @@
+package dto
+
+import "database/sql"
+
+type WaitlistEntryRow struct {
+	ID             string         `db:"id"`
+	TicketTypeID   string         `db:"ticket_type_id"`
+	UserID         string         `db:"user_id"`
+	Quantity       int            `db:"quantity"`
+	Status         string         `db:"status"`
+	HoldID         sql.NullString `db:"hold_id"`
+	OfferedAt      sql.NullTime   `db:"offered_at"`
+	OfferExpiresAt sql.NullTime   `db:"offer_expires_at"`
+	Position       int            `db:"position"`
+	CreatedAt      sql.NullTime   `db:"created_at"`
+	UpdatedAt      sql.NullTime   `db:"updated_at"`
+}
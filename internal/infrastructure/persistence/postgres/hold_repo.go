@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/internal/infrastructure/persistence/postgres/dto"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const selectTicketHold = `
+	SELECT id, ticket_type_id, user_id, quantity, status, expires_at, order_id, created_at, updated_at
+	FROM ticket_holds
+`
+
+type TicketHoldRepo struct{ db *sqlx.DB }
+
+func NewTicketHoldRepo(db *sqlx.DB) *TicketHoldRepo { return &TicketHoldRepo{db: db} }
+
+var _ repository.TicketHoldRepository = (*TicketHoldRepo)(nil)
+
+func (r *TicketHoldRepo) GetByID(ctx context.Context, id valueobject.UUID) (entity.TicketHold, error) {
+	return getTicketHold(ctx, r.db, selectTicketHold+`WHERE id = $1`, id)
+}
+
+// ExpireDue expires holds one statement at a time, so the sweeper never holds more than one
+// ticket type lock (taken by the holds trigger) and cannot deadlock with multi-type orders.
+func (r *TicketHoldRepo) ExpireDue(ctx context.Context, now time.Time, limit int) (int, error) {
+	var ids []string
+	q := `
+		SELECT id FROM ticket_holds
+		WHERE status = 'active' AND expires_at <= $1
+		ORDER BY expires_at
+		LIMIT $2
+	`
+	if err := r.db.SelectContext(ctx, &ids, q, now, limit); err != nil {
+		return 0, apperror.New(apperror.CodeInternal, "list due holds failed", err)
+	}
+	expired := 0
+	for _, id := range ids {
+		res, err := r.db.ExecContext(ctx,
+			`UPDATE ticket_holds SET status = 'expired' WHERE id = $1 AND status = 'active' AND expires_at <= $2`,
+			id, now)
+		if err != nil {
+			return expired, apperror.New(apperror.CodeInternal, "expire hold failed", err)
+		}
+		aff, _ := res.RowsAffected()
+		expired += int(aff)
+	}
+	return expired, nil
+}
+
+func insertTicketHold(ctx context.Context, db sqlx.ExtContext, h entity.TicketHold) error {
+	q := `
+		INSERT INTO ticket_holds (id, ticket_type_id, user_id, quantity, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	if _, err := db.ExecContext(ctx, q,
+		h.ID.String(), h.TicketTypeID.String(), h.UserID.String(), h.Quantity, string(h.Status), h.ExpiresAt,
+	); err != nil {
+		return apperror.New(apperror.CodeInternal, "insert hold failed", err)
+	}
+	return nil
+}
+
+func getTicketHold(ctx context.Context, db sqlx.QueryerContext, q string, id valueobject.UUID) (entity.TicketHold, error) {
+	var row dto.TicketHoldRow
+	if err := sqlx.GetContext(ctx, db, &row, q, id.String()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.TicketHold{}, apperror.New(apperror.CodeNotFound, "hold not found", err)
+		}
+		return entity.TicketHold{}, apperror.New(apperror.CodeInternal, "get hold failed", err)
+	}
+	return mapTicketHoldRow(row)
+}
+
+func mapTicketHoldRow(row dto.TicketHoldRow) (entity.TicketHold, error) {
+	id, err := valueobject.ParseUUID(row.ID)
+	if err != nil {
+		return entity.TicketHold{}, apperror.New(apperror.CodeInternal, "invalid hold id in db", err)
+	}
+	ttID, err := valueobject.ParseUUID(row.TicketTypeID)
+	if err != nil {
+		return entity.TicketHold{}, apperror.New(apperror.CodeInternal, "invalid hold ticket_type_id in db", err)
+	}
+	userID, err := valueobject.ParseUUID(row.UserID)
+	if err != nil {
+		return entity.TicketHold{}, apperror.New(apperror.CodeInternal, "invalid hold user_id in db", err)
+	}
+	st := valueobject.HoldStatus(row.Status)
+	if err := st.Validate(); err != nil {
+		return entity.TicketHold{}, apperror.New(apperror.CodeInternal, "invalid hold status in db", err)
+	}
+	orderID, err := parseNullUUID(row.OrderID)
+	if err != nil {
+		return entity.TicketHold{}, apperror.New(apperror.CodeInternal, "invalid hold order_id in db", err)
+	}
+	h := entity.TicketHold{
+		ID:           id,
+		TicketTypeID: ttID,
+		UserID:       userID,
+		Quantity:     row.Quantity,
+		Status:       st,
+		OrderID:      orderID,
+	}
+	if row.ExpiresAt.Valid {
+		h.ExpiresAt = row.ExpiresAt.Time
+	}
+	if row.CreatedAt.Valid {
+		h.CreatedAt = row.CreatedAt.Time
+	}
+	if row.UpdatedAt.Valid {
+		h.UpdatedAt = row.UpdatedAt.Time
+	}
+	return h, nil
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"time2meet/internal/application/port/idempotency"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/internal/infrastructure/persistence/postgres/dto"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type IdempotencyStore struct{ db *sqlx.DB }
+
+func NewIdempotencyStore(db *sqlx.DB) *IdempotencyStore { return &IdempotencyStore{db: db} }
+
+var _ idempotency.Store = (*IdempotencyStore)(nil)
+
+func (s *IdempotencyStore) Claim(ctx context.Context, userID valueobject.UUID, key, fingerprint string, lockedUntil, expiresAt time.Time) (idempotency.Record, bool, error) {
+	// The upsert only overwrites a record that expired or whose claim was abandoned.
+	q := `
+		INSERT INTO idempotency_keys (user_id, key, fingerprint, locked_until, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint,
+		    completed = FALSE,
+		    status_code = NULL,
+		    content_type = NULL,
+		    body = NULL,
+		    locked_until = EXCLUDED.locked_until,
+		    expires_at = EXCLUDED.expires_at,
+		    created_at = NOW()
+		WHERE idempotency_keys.expires_at <= NOW()
+		   OR (NOT idempotency_keys.completed AND idempotency_keys.locked_until <= NOW())
+		RETURNING TRUE
+	`
+	var claimed bool
+	err := s.db.GetContext(ctx, &claimed, q, userID.String(), key, fingerprint, lockedUntil, expiresAt)
+	if err == nil {
+		return idempotency.Record{}, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return idempotency.Record{}, false, apperror.New(apperror.CodeInternal, "claim idempotency key failed", err)
+	}
+
+	var row dto.IdempotencyKeyRow
+	err = s.db.GetContext(ctx, &row, `
+		SELECT fingerprint, completed, status_code, content_type, body
+		FROM idempotency_keys
+		WHERE user_id = $1 AND key = $2
+	`, userID.String(), key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Purged between the two statements; the client can simply retry.
+			return idempotency.Record{}, false, apperror.New(apperror.CodeConflict, "idempotency key is being reset, retry the request", err)
+		}
+		return idempotency.Record{}, false, apperror.New(apperror.CodeInternal, "get idempotency key failed", err)
+	}
+	return idempotency.Record{
+		Fingerprint: row.Fingerprint,
+		Completed:   row.Completed,
+		StatusCode:  int(row.StatusCode.Int64),
+		ContentType: row.ContentType.String,
+		Body:        row.Body,
+	}, false, nil
+}
+
+func (s *IdempotencyStore) Complete(ctx context.Context, userID valueobject.UUID, key string, rec idempotency.Record) error {
+	q := `
+		UPDATE idempotency_keys
+		SET completed = TRUE, status_code = $4, content_type = $5, body = $6
+		WHERE user_id = $1 AND key = $2 AND fingerprint = $3 AND NOT completed
+	`
+	if _, err := s.db.ExecContext(ctx, q, userID.String(), key, rec.Fingerprint, rec.StatusCode, rec.ContentType, rec.Body); err != nil {
+		return apperror.New(apperror.CodeInternal, "complete idempotency key failed", err)
+	}
+	return nil
+}
+
+func (s *IdempotencyStore) Release(ctx context.Context, userID valueobject.UUID, key string) error {
+	if _, err := s.db.ExecContext(ctx,
+		`DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2 AND NOT completed`,
+		userID.String(), key); err != nil {
+		return apperror.New(apperror.CodeInternal, "release idempotency key failed", err)
+	}
+	return nil
+}
+
+func (s *IdempotencyStore) PurgeExpired(ctx context.Context, now time.Time) (int, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, apperror.New(apperror.CodeInternal, "purge idempotency keys failed", err)
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
@@ -0,0 +1,210 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/internal/infrastructure/persistence/postgres/dto"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+const selectOrder = `
+	SELECT id, buyer_id, status, currency, total_amount, ticket_count, promo_code_id, discount_amount, client_ip, created_at, updated_at
+	FROM orders
+`
+
+type OrderRepo struct{ db *sqlx.DB }
+
+func NewOrderRepo(db *sqlx.DB) *OrderRepo { return &OrderRepo{db: db} }
+
+var _ repository.OrderRepository = (*OrderRepo)(nil)
+
+func (r *OrderRepo) GetByID(ctx context.Context, id valueobject.UUID) (entity.Order, error) {
+	o, err := getOrder(ctx, r.db, selectOrder+`WHERE id = $1`, id)
+	if err != nil {
+		return entity.Order{}, err
+	}
+	itemsQ := `
+		SELECT id, order_id, ticket_type_id, quantity, unit_price, line_total, discount_amount
+		FROM order_items
+		WHERE order_id = $1
+		ORDER BY ticket_type_id, unit_price, id
+	`
+	var items []dto.OrderItemRow
+	if err := r.db.SelectContext(ctx, &items, itemsQ, id.String()); err != nil {
+		return entity.Order{}, apperror.New(apperror.CodeInternal, "list order items failed", err)
+	}
+	o.Items = make([]entity.OrderItem, 0, len(items))
+	for _, it := range items {
+		item, err := mapOrderItemRow(it)
+		if err != nil {
+			return entity.Order{}, err
+		}
+		o.Items = append(o.Items, item)
+	}
+	return o, nil
+}
+
+func (r *OrderRepo) ListByBuyerID(ctx context.Context, buyerID valueobject.UUID, limit, offset int) ([]entity.Order, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	q := selectOrder + `
+		WHERE buyer_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	var rows []dto.OrderRow
+	if err := r.db.SelectContext(ctx, &rows, q, buyerID.String(), limit, offset); err != nil {
+		return nil, apperror.New(apperror.CodeInternal, "list orders failed", err)
+	}
+	out := make([]entity.Order, 0, len(rows))
+	for _, row := range rows {
+		o, err := mapOrderRow(row)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, o)
+	}
+	return out, nil
+}
+
+// insertOrder writes the order and its items. It runs inside the purchase transaction.
+func insertOrder(ctx context.Context, db sqlx.ExtContext, o entity.Order) error {
+	q := `
+		INSERT INTO orders (id, buyer_id, status, currency, total_amount, ticket_count, promo_code_id, discount_amount, client_ip)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''))
+	`
+	if _, err := db.ExecContext(ctx, q,
+		o.ID.String(), o.BuyerID.String(), string(o.Status), o.Currency.String(),
+		o.TotalAmount.Amount.StringFixed(2), o.TicketCount, uuidArg(o.PromoCodeID), o.DiscountAmount.Amount.StringFixed(2),
+		o.ClientIP,
+	); err != nil {
+		return apperror.New(apperror.CodeInternal, "insert order failed", err)
+	}
+	itemQ := `
+		INSERT INTO order_items (id, order_id, ticket_type_id, quantity, unit_price, line_total, discount_amount)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	for _, it := range o.Items {
+		if _, err := db.ExecContext(ctx, itemQ,
+			it.ID.String(), o.ID.String(), it.TicketTypeID.String(), it.Quantity,
+			it.UnitPrice.Amount.StringFixed(2), it.LineTotal.Amount.StringFixed(2), it.DiscountAmount.Amount.StringFixed(2),
+		); err != nil {
+			return apperror.New(apperror.CodeInternal, "insert order item failed", err)
+		}
+	}
+	return nil
+}
+
+// getOrder loads an order row without its items.
+func getOrder(ctx context.Context, db sqlx.QueryerContext, q string, id valueobject.UUID) (entity.Order, error) {
+	var row dto.OrderRow
+	if err := sqlx.GetContext(ctx, db, &row, q, id.String()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Order{}, apperror.New(apperror.CodeNotFound, "order not found", err)
+		}
+		return entity.Order{}, apperror.New(apperror.CodeInternal, "get order failed", err)
+	}
+	return mapOrderRow(row)
+}
+
+func mapOrderRow(row dto.OrderRow) (entity.Order, error) {
+	id, err := valueobject.ParseUUID(row.ID)
+	if err != nil {
+		return entity.Order{}, apperror.New(apperror.CodeInternal, "invalid order id in db", err)
+	}
+	buyerID, err := valueobject.ParseUUID(row.BuyerID)
+	if err != nil {
+		return entity.Order{}, apperror.New(apperror.CodeInternal, "invalid order buyer_id in db", err)
+	}
+	st := valueobject.OrderStatus(row.Status)
+	if err := st.Validate(); err != nil {
+		return entity.Order{}, apperror.New(apperror.CodeInternal, "invalid order status in db", err)
+	}
+	total, err := parseMoney(row.TotalAmount)
+	if err != nil {
+		return entity.Order{}, apperror.New(apperror.CodeInternal, "invalid order total in db", err)
+	}
+	promoID, err := parseNullUUID(row.PromoCodeID)
+	if err != nil {
+		return entity.Order{}, apperror.New(apperror.CodeInternal, "invalid order promo_code_id in db", err)
+	}
+	discount, err := parseMoney(row.DiscountAmount)
+	if err != nil {
+		return entity.Order{}, apperror.New(apperror.CodeInternal, "invalid order discount in db", err)
+	}
+	o := entity.Order{
+		ID:             id,
+		BuyerID:        buyerID,
+		Status:         st,
+		Currency:       valueobject.Currency(row.Currency),
+		TotalAmount:    total,
+		TicketCount:    row.TicketCount,
+		PromoCodeID:    promoID,
+		DiscountAmount: discount,
+		ClientIP:       row.ClientIP.String,
+	}
+	if row.CreatedAt.Valid {
+		o.CreatedAt = row.CreatedAt.Time
+	}
+	if row.UpdatedAt.Valid {
+		o.UpdatedAt = row.UpdatedAt.Time
+	}
+	return o, nil
+}
+
+func mapOrderItemRow(row dto.OrderItemRow) (entity.OrderItem, error) {
+	id, err := valueobject.ParseUUID(row.ID)
+	if err != nil {
+		return entity.OrderItem{}, apperror.New(apperror.CodeInternal, "invalid order item id in db", err)
+	}
+	orderID, err := valueobject.ParseUUID(row.OrderID)
+	if err != nil {
+		return entity.OrderItem{}, apperror.New(apperror.CodeInternal, "invalid order item order_id in db", err)
+	}
+	ttID, err := valueobject.ParseUUID(row.TicketTypeID)
+	if err != nil {
+		return entity.OrderItem{}, apperror.New(apperror.CodeInternal, "invalid order item ticket_type_id in db", err)
+	}
+	unit, err := parseMoney(row.UnitPrice)
+	if err != nil {
+		return entity.OrderItem{}, apperror.New(apperror.CodeInternal, "invalid order item unit_price in db", err)
+	}
+	line, err := parseMoney(row.LineTotal)
+	if err != nil {
+		return entity.OrderItem{}, apperror.New(apperror.CodeInternal, "invalid order item line_total in db", err)
+	}
+	discount, err := parseMoney(row.DiscountAmount)
+	if err != nil {
+		return entity.OrderItem{}, apperror.New(apperror.CodeInternal, "invalid order item discount in db", err)
+	}
+	return entity.OrderItem{
+		ID:             id,
+		OrderID:        orderID,
+		TicketTypeID:   ttID,
+		Quantity:       row.Quantity,
+		UnitPrice:      unit,
+		LineTotal:      line,
+		DiscountAmount: discount,
+	}, nil
+}
+
+func parseMoney(s string) (valueobject.Money, error) {
+	amt, err := decimal.NewFromString(s)
+	if err != nil {
+		return valueobject.Money{}, fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+	return valueobject.NewMoney(amt)
+}
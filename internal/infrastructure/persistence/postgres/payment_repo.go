@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/internal/infrastructure/persistence/postgres/dto"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const selectPayment = `
+	SELECT id, order_id, provider, intent_id, status, amount, currency, failure_reason, created_at, updated_at
+	FROM payments
+`
+
+type PaymentRepo struct{ db *sqlx.DB }
+
+func NewPaymentRepo(db *sqlx.DB) *PaymentRepo { return &PaymentRepo{db: db} }
+
+var _ repository.PaymentRepository = (*PaymentRepo)(nil)
+
+func (r *PaymentRepo) SetIntentID(ctx context.Context, id valueobject.UUID, intentID string) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE payments SET intent_id = $2 WHERE id = $1 AND (intent_id IS NULL OR intent_id = $2)`,
+		id.String(), intentID)
+	if err != nil {
+		return apperror.New(apperror.CodeInternal, "set payment intent failed", err)
+	}
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		return apperror.New(apperror.CodeConflict, "payment already has another intent", nil)
+	}
+	return nil
+}
+
+func (r *PaymentRepo) GetByID(ctx context.Context, id valueobject.UUID) (entity.Payment, error) {
+	return getPayment(ctx, r.db, selectPayment+`WHERE id = $1`, id.String())
+}
+
+func (r *PaymentRepo) GetByOrderID(ctx context.Context, orderID valueobject.UUID) (entity.Payment, error) {
+	return getPayment(ctx, r.db, selectPayment+`WHERE order_id = $1 ORDER BY created_at DESC LIMIT 1`, orderID.String())
+}
+
+func (r *PaymentRepo) GetByIntentID(ctx context.Context, provider, intentID string) (entity.Payment, error) {
+	return getPayment(ctx, r.db, selectPayment+`WHERE provider = $1 AND intent_id = $2`, provider, intentID)
+}
+
+func (r *PaymentRepo) ListPending(ctx context.Context, createdBefore time.Time, limit int) ([]entity.Payment, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	q := selectPayment + `
+		WHERE status = 'pending' AND created_at < $1
+		ORDER BY created_at
+		LIMIT $2
+	`
+	var rows []dto.PaymentRow
+	if err := r.db.SelectContext(ctx, &rows, q, createdBefore, limit); err != nil {
+		return nil, apperror.New(apperror.CodeInternal, "list pending payments failed", err)
+	}
+	out := make([]entity.Payment, 0, len(rows))
+	for _, row := range rows {
+		p, err := mapPaymentRow(row)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+func insertPayment(ctx context.Context, db sqlx.ExtContext, p entity.Payment) error {
+	q := `
+		INSERT INTO payments (id, order_id, provider, intent_id, status, amount, currency)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7)
+	`
+	if _, err := db.ExecContext(ctx, q,
+		p.ID.String(), p.OrderID.String(), p.Provider, p.IntentID, string(p.Status),
+		p.Amount.Amount.StringFixed(2), p.Currency.String(),
+	); err != nil {
+		return apperror.New(apperror.CodeInternal, "insert payment failed", err)
+	}
+	return nil
+}
+
+func getPayment(ctx context.Context, db sqlx.QueryerContext, q string, args ...any) (entity.Payment, error) {
+	var row dto.PaymentRow
+	if err := sqlx.GetContext(ctx, db, &row, q, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Payment{}, apperror.New(apperror.CodeNotFound, "payment not found", err)
+		}
+		return entity.Payment{}, apperror.New(apperror.CodeInternal, "get payment failed", err)
+	}
+	return mapPaymentRow(row)
+}
+
+func mapPaymentRow(row dto.PaymentRow) (entity.Payment, error) {
+	id, err := valueobject.ParseUUID(row.ID)
+	if err != nil {
+		return entity.Payment{}, apperror.New(apperror.CodeInternal, "invalid payment id in db", err)
+	}
+	orderID, err := valueobject.ParseUUID(row.OrderID)
+	if err != nil {
+		return entity.Payment{}, apperror.New(apperror.CodeInternal, "invalid payment order_id in db", err)
+	}
+	st := valueobject.PaymentStatus(row.Status)
+	if err := st.Validate(); err != nil {
+		return entity.Payment{}, apperror.New(apperror.CodeInternal, "invalid payment status in db", err)
+	}
+	amount, err := parseMoney(row.Amount)
+	if err != nil {
+		return entity.Payment{}, apperror.New(apperror.CodeInternal, "invalid payment amount in db", err)
+	}
+	p := entity.Payment{
+		ID:            id,
+		OrderID:       orderID,
+		Provider:      row.Provider,
+		IntentID:      row.IntentID.String,
+		Status:        st,
+		Amount:        amount,
+		Currency:      valueobject.Currency(row.Currency),
+		FailureReason: row.FailureReason.String,
+	}
+	if row.CreatedAt.Valid {
+		p.CreatedAt = row.CreatedAt.Time
+	}
+	if row.UpdatedAt.Valid {
+		p.UpdatedAt = row.UpdatedAt.Time
+	}
+	return p, nil
+}
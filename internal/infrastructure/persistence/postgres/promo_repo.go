@@ -0,0 +1,253 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/internal/infrastructure/persistence/postgres/dto"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+const selectPromoCode = `
+	SELECT p.id, p.event_id, p.code, p.discount_type, p.discount_value, p.currency,
+	       ARRAY(SELECT ticket_type_id::text FROM promo_code_ticket_types WHERE promo_code_id = p.id ORDER BY ticket_type_id) AS ticket_type_ids,
+	       p.max_uses, p.max_uses_per_user, p.used_count, p.valid_from, p.valid_until, p.is_active, p.created_by,
+	       p.created_at, p.updated_at
+	FROM promo_codes p
+`
+
+type PromoCodeRepo struct{ db *sqlx.DB }
+
+func NewPromoCodeRepo(db *sqlx.DB) *PromoCodeRepo { return &PromoCodeRepo{db: db} }
+
+var _ repository.PromoCodeRepository = (*PromoCodeRepo)(nil)
+
+// Create writes the code and its ticket types in one statement.
+func (r *PromoCodeRepo) Create(ctx context.Context, p entity.PromoCode) (valueobject.UUID, error) {
+	q := `
+		WITH p AS (
+			INSERT INTO promo_codes (event_id, code, discount_type, discount_value, currency, max_uses, max_uses_per_user,
+			                         valid_from, valid_until, is_active, created_by)
+			VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10, $11)
+			RETURNING id
+		), tt AS (
+			INSERT INTO promo_code_ticket_types (promo_code_id, ticket_type_id)
+			SELECT p.id, t.id FROM p, UNNEST($12::uuid[]) AS t(id)
+		)
+		SELECT id FROM p
+	`
+	var id string
+	if err := r.db.QueryRowxContext(ctx, q,
+		p.EventID.String(), p.Code, string(p.DiscountType), p.DiscountValue.StringFixed(2), p.Currency.String(),
+		intPtrArg(p.MaxUses), intPtrArg(p.MaxUsesPerUser), timePtrArg(p.ValidFrom), timePtrArg(p.ValidUntil),
+		p.IsActive, uuidArg(&p.CreatedBy), uuidArray(p.TicketTypeIDs),
+	).Scan(&id); err != nil {
+		if isUniqueViolation(err) {
+			return valueobject.Nil, apperror.New(apperror.CodeConflict, "promo code already exists for this event", err)
+		}
+		return valueobject.Nil, apperror.New(apperror.CodeInternal, "create promo code failed", err)
+	}
+	out, err := valueobject.ParseUUID(id)
+	if err != nil {
+		return valueobject.Nil, apperror.New(apperror.CodeInternal, "invalid uuid returned from db", err)
+	}
+	return out, nil
+}
+
+func (r *PromoCodeRepo) GetByID(ctx context.Context, id valueobject.UUID) (entity.PromoCode, error) {
+	return getPromoCode(ctx, r.db, selectPromoCode+`WHERE p.id = $1`, id.String())
+}
+
+func (r *PromoCodeRepo) ListByEventID(ctx context.Context, eventID valueobject.UUID) ([]entity.PromoCode, error) {
+	var rows []dto.PromoCodeRow
+	if err := r.db.SelectContext(ctx, &rows, selectPromoCode+`WHERE p.event_id = $1 ORDER BY p.created_at, p.id`, eventID.String()); err != nil {
+		return nil, apperror.New(apperror.CodeInternal, "list promo codes failed", err)
+	}
+	out := make([]entity.PromoCode, 0, len(rows))
+	for _, row := range rows {
+		p, err := mapPromoCodeRow(row)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// Update replaces the code's ticket types with p.TicketTypeIDs in the same statement. The
+// sub-statements of a CTE run in no set order, so the delete keeps the rows being inserted
+// and the insert skips the ones already there.
+func (r *PromoCodeRepo) Update(ctx context.Context, p entity.PromoCode) error {
+	q := `
+		WITH p AS (
+			UPDATE promo_codes
+			SET code=$2, discount_type=$3, discount_value=$4, currency=NULLIF($5, ''), max_uses=$6, max_uses_per_user=$7,
+			    valid_from=$8, valid_until=$9, is_active=$10
+			WHERE id=$1
+			RETURNING id
+		), del AS (
+			DELETE FROM promo_code_ticket_types
+			WHERE promo_code_id IN (SELECT id FROM p) AND ticket_type_id <> ALL($11::uuid[])
+		), ins AS (
+			INSERT INTO promo_code_ticket_types (promo_code_id, ticket_type_id)
+			SELECT p.id, t.id FROM p, UNNEST($11::uuid[]) AS t(id)
+			ON CONFLICT DO NOTHING
+		)
+		SELECT COUNT(*) FROM p
+	`
+	var n int
+	if err := r.db.GetContext(ctx, &n, q,
+		p.ID.String(), p.Code, string(p.DiscountType), p.DiscountValue.StringFixed(2), p.Currency.String(),
+		intPtrArg(p.MaxUses), intPtrArg(p.MaxUsesPerUser), timePtrArg(p.ValidFrom), timePtrArg(p.ValidUntil),
+		p.IsActive, uuidArray(p.TicketTypeIDs),
+	); err != nil {
+		if isUniqueViolation(err) {
+			return apperror.New(apperror.CodeConflict, "promo code already exists for this event", err)
+		}
+		return apperror.New(apperror.CodeInternal, "update promo code failed", err)
+	}
+	if n == 0 {
+		return apperror.New(apperror.CodeNotFound, "promo code not found", sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *PromoCodeRepo) Delete(ctx context.Context, id valueobject.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM promo_codes WHERE id=$1`, id.String())
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return apperror.New(apperror.CodeConflict, "promo code has been redeemed; deactivate it instead", err)
+		}
+		return apperror.New(apperror.CodeInternal, "delete promo code failed", err)
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return apperror.New(apperror.CodeNotFound, "promo code not found", sql.ErrNoRows)
+	}
+	return nil
+}
+
+func getPromoCode(ctx context.Context, db sqlx.QueryerContext, q string, args ...any) (entity.PromoCode, error) {
+	var row dto.PromoCodeRow
+	if err := sqlx.GetContext(ctx, db, &row, q, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.PromoCode{}, apperror.New(apperror.CodeNotFound, "promo code not found", err)
+		}
+		return entity.PromoCode{}, apperror.New(apperror.CodeInternal, "get promo code failed", err)
+	}
+	return mapPromoCodeRow(row)
+}
+
+func mapPromoCodeRow(row dto.PromoCodeRow) (entity.PromoCode, error) {
+	id, err := valueobject.ParseUUID(row.ID)
+	if err != nil {
+		return entity.PromoCode{}, apperror.New(apperror.CodeInternal, "invalid promo code id in db", err)
+	}
+	eventID, err := valueobject.ParseUUID(row.EventID)
+	if err != nil {
+		return entity.PromoCode{}, apperror.New(apperror.CodeInternal, "invalid promo code event_id in db", err)
+	}
+	dt := valueobject.DiscountType(row.DiscountType)
+	if err := dt.Validate(); err != nil {
+		return entity.PromoCode{}, apperror.New(apperror.CodeInternal, "invalid promo code discount_type in db", err)
+	}
+	value, err := decimal.NewFromString(row.DiscountValue)
+	if err != nil {
+		return entity.PromoCode{}, apperror.New(apperror.CodeInternal, "invalid promo code discount_value in db", err)
+	}
+	p := entity.PromoCode{
+		ID:            id,
+		EventID:       eventID,
+		Code:          row.Code,
+		DiscountType:  dt,
+		DiscountValue: value,
+		Currency:      valueobject.Currency(row.Currency.String),
+		TicketTypeIDs: make([]valueobject.UUID, 0, len(row.TicketTypeIDs)),
+		UsedCount:     row.UsedCount,
+		IsActive:      row.IsActive,
+	}
+	for _, s := range row.TicketTypeIDs {
+		ttID, err := valueobject.ParseUUID(s)
+		if err != nil {
+			return entity.PromoCode{}, apperror.New(apperror.CodeInternal, "invalid promo code ticket_type_id in db", err)
+		}
+		p.TicketTypeIDs = append(p.TicketTypeIDs, ttID)
+	}
+	if row.MaxUses.Valid {
+		n := int(row.MaxUses.Int64)
+		p.MaxUses = &n
+	}
+	if row.MaxUsesPerUser.Valid {
+		n := int(row.MaxUsesPerUser.Int64)
+		p.MaxUsesPerUser = &n
+	}
+	if row.ValidFrom.Valid {
+		t := row.ValidFrom.Time
+		p.ValidFrom = &t
+	}
+	if row.ValidUntil.Valid {
+		t := row.ValidUntil.Time
+		p.ValidUntil = &t
+	}
+	if row.CreatedBy.Valid {
+		if p.CreatedBy, err = valueobject.ParseUUID(row.CreatedBy.String); err != nil {
+			return entity.PromoCode{}, apperror.New(apperror.CodeInternal, "invalid promo code created_by in db", err)
+		}
+	}
+	if row.CreatedAt.Valid {
+		p.CreatedAt = row.CreatedAt.Time
+	}
+	if row.UpdatedAt.Valid {
+		p.UpdatedAt = row.UpdatedAt.Time
+	}
+	return p, nil
+}
+
+func lockPromoCodeByCode(ctx context.Context, tx *sqlx.Tx, code string, eventIDs []valueobject.UUID) (entity.PromoCode, error) {
+	var rows []dto.PromoCodeRow
+	if err := tx.SelectContext(ctx, &rows, selectPromoCode+`
+		WHERE UPPER(p.code) = UPPER($1) AND p.event_id = ANY($2::uuid[])
+		ORDER BY p.id
+		FOR UPDATE
+	`, code, uuidArray(eventIDs)); err != nil {
+		return entity.PromoCode{}, apperror.New(apperror.CodeInternal, "lock promo code failed", err)
+	}
+	switch len(rows) {
+	case 0:
+		return entity.PromoCode{}, apperror.New(apperror.CodePromoInvalid, "unknown promo code", sql.ErrNoRows)
+	case 1:
+		return mapPromoCodeRow(rows[0])
+	}
+	return entity.PromoCode{}, apperror.New(apperror.CodePromoInvalid, "promo code is ambiguous: several events of the order have it", nil)
+}
+
+func intPtrArg(n *int) any {
+	if n == nil {
+		return nil
+	}
+	return *n
+}
+
+func timePtrArg(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+func uuidArray(ids []valueobject.UUID) any {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, id.String())
+	}
+	return pq.Array(out)
+}
@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/internal/infrastructure/persistence/postgres/dto"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const selectRefund = `
+	SELECT id, ticket_id, order_id, payment_id, amount, currency, status, provider_refund_id,
+	       reason, failure_reason, requested_by, created_at, updated_at
+	FROM refunds
+`
+
+type RefundRepo struct{ db *sqlx.DB }
+
+func NewRefundRepo(db *sqlx.DB) *RefundRepo { return &RefundRepo{db: db} }
+
+var _ repository.RefundRepository = (*RefundRepo)(nil)
+
+func (r *RefundRepo) ListByTicketID(ctx context.Context, ticketID valueobject.UUID) ([]entity.Refund, error) {
+	return listRefunds(ctx, r.db, selectRefund+`WHERE ticket_id = $1 ORDER BY created_at, id`, ticketID.String())
+}
+
+func (r *RefundRepo) ListPending(ctx context.Context, createdBefore time.Time, limit int) ([]entity.Refund, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	return listRefunds(ctx, r.db, selectRefund+`
+		WHERE status = 'pending' AND created_at < $1
+		ORDER BY created_at
+		LIMIT $2
+	`, createdBefore, limit)
+}
+
+func insertRefund(ctx context.Context, db sqlx.ExtContext, r entity.Refund) error {
+	q := `
+		INSERT INTO refunds (id, ticket_id, order_id, payment_id, amount, currency, status, provider_refund_id, reason, requested_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), $10)
+	`
+	if _, err := db.ExecContext(ctx, q,
+		r.ID.String(), r.TicketID.String(), uuidArg(r.OrderID), uuidArg(r.PaymentID),
+		r.Amount.Amount.StringFixed(2), r.Currency.String(), string(r.Status), r.ProviderRefundID, r.Reason,
+		uuidArg(&r.RequestedBy),
+	); err != nil {
+		if isUniqueViolation(err) {
+			return apperror.New(apperror.CodeConflict, "ticket already has a refund", err)
+		}
+		return apperror.New(apperror.CodeInternal, "insert refund failed", err)
+	}
+	return nil
+}
+
+func listRefunds(ctx context.Context, db sqlx.QueryerContext, q string, args ...any) ([]entity.Refund, error) {
+	var rows []dto.RefundRow
+	if err := sqlx.SelectContext(ctx, db, &rows, q, args...); err != nil {
+		return nil, apperror.New(apperror.CodeInternal, "list refunds failed", err)
+	}
+	out := make([]entity.Refund, 0, len(rows))
+	for _, row := range rows {
+		r, err := mapRefundRow(row)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func mapRefundRow(row dto.RefundRow) (entity.Refund, error) {
+	id, err := valueobject.ParseUUID(row.ID)
+	if err != nil {
+		return entity.Refund{}, apperror.New(apperror.CodeInternal, "invalid refund id in db", err)
+	}
+	ticketID, err := valueobject.ParseUUID(row.TicketID)
+	if err != nil {
+		return entity.Refund{}, apperror.New(apperror.CodeInternal, "invalid refund ticket_id in db", err)
+	}
+	orderID, err := parseNullUUID(row.OrderID)
+	if err != nil {
+		return entity.Refund{}, apperror.New(apperror.CodeInternal, "invalid refund order_id in db", err)
+	}
+	paymentID, err := parseNullUUID(row.PaymentID)
+	if err != nil {
+		return entity.Refund{}, apperror.New(apperror.CodeInternal, "invalid refund payment_id in db", err)
+	}
+	requestedBy, err := parseNullUUID(row.RequestedBy)
+	if err != nil {
+		return entity.Refund{}, apperror.New(apperror.CodeInternal, "invalid refund requested_by in db", err)
+	}
+	st := valueobject.RefundStatus(row.Status)
+	if err := st.Validate(); err != nil {
+		return entity.Refund{}, apperror.New(apperror.CodeInternal, "invalid refund status in db", err)
+	}
+	amount, err := parseMoney(row.Amount)
+	if err != nil {
+		return entity.Refund{}, apperror.New(apperror.CodeInternal, "invalid refund amount in db", err)
+	}
+	r := entity.Refund{
+		ID:               id,
+		TicketID:         ticketID,
+		OrderID:          orderID,
+		PaymentID:        paymentID,
+		Amount:           amount,
+		Currency:         valueobject.Currency(row.Currency),
+		Status:           st,
+		ProviderRefundID: row.ProviderRefundID.String,
+		Reason:           row.Reason.String,
+		FailureReason:    row.FailureReason.String,
+	}
+	if requestedBy != nil {
+		r.RequestedBy = *requestedBy
+	}
+	if row.CreatedAt.Valid {
+		r.CreatedAt = row.CreatedAt.Time
+	}
+	if row.UpdatedAt.Valid {
+		r.UpdatedAt = row.UpdatedAt.Time
+	}
+	return r, nil
+}
@@ -19,7 +19,7 @@ func NewReportRepo(db *sqlx.DB) *ReportRepo { return &ReportRepo{db: db} }
 var _ repository.ReportRepository = (*ReportRepo)(nil)
 
 func (r *ReportRepo) SalesReport(ctx context.Context, start, end time.Time) ([]repository.SalesReportRow, error) {
-	q := `SELECT event_id, event_title, tickets_sold, revenue, unique_buyers FROM get_sales_report($1::date, $2::date)`
+	q := `SELECT event_id, event_title, tickets_sold, revenue, discounts, unique_buyers, comp_tickets FROM get_sales_report($1::date, $2::date)`
 	var rows []dto.SalesReportRow
 	if err := r.db.SelectContext(ctx, &rows, q, start, end); err != nil {
 		return nil, apperror.New(apperror.CodeInternal, "sales report failed", err)
@@ -35,7 +35,9 @@ func (r *ReportRepo) SalesReport(ctx context.Context, start, end time.Time) ([]r
 			EventTitle:   row.EventTitle,
 			TicketsSold:  row.TicketsSold,
 			Revenue:      row.Revenue,
+			Discounts:    row.Discounts,
 			UniqueBuyers: row.UniqueBuyers,
+			CompTickets:  row.CompTickets,
 		})
 	}
 	return out, nil
@@ -59,10 +61,19 @@ func (r *ReportRepo) AttendanceStats(ctx context.Context, eventID valueobject.UU
 	return out, nil
 }
 
-func (r *ReportRepo) PopularEvents(ctx context.Context, limit int, days int) ([]repository.PopularEventRow, error) {
-	q := `SELECT event_id, title, registrations, tickets_sold FROM get_popular_events($1, $2)`
+func (r *ReportRepo) PopularEvents(ctx context.Context, limit int, days int, organizerID *valueobject.UUID) ([]repository.PopularEventRow, error) {
+	// The function ranks every event, so it is asked for all of them and the organizer filter
+	// and limit are applied here.
+	q := `
+		SELECT p.event_id, p.title, p.registrations, p.tickets_sold
+		FROM get_popular_events(2147483647, $2) p
+		JOIN events e ON e.id = p.event_id
+		WHERE $3::uuid IS NULL OR e.organizer_id = $3::uuid
+		ORDER BY p.registrations + p.tickets_sold DESC
+		LIMIT GREATEST($1, 1)
+	`
 	var rows []dto.PopularEventRow
-	if err := r.db.SelectContext(ctx, &rows, q, limit, days); err != nil {
+	if err := r.db.SelectContext(ctx, &rows, q, limit, days, uuidArg(organizerID)); err != nil {
 		return nil, apperror.New(apperror.CodeInternal, "popular events failed", err)
 	}
 	out := make([]repository.PopularEventRow, 0, len(rows))
@@ -80,3 +91,89 @@ func (r *ReportRepo) PopularEvents(ctx context.Context, limit int, days int) ([]
 	}
 	return out, nil
 }
+
+func (r *ReportRepo) FlaggedPurchases(ctx context.Context, start, end time.Time) ([]repository.FlaggedPurchaseRow, error) {
+	q := `
+		SELECT f.id AS flag_id, f.order_id, f.buyer_id, u.email AS buyer_email, f.client_ip, f.reason, f.observed, f.threshold,
+		       o.status AS order_status, o.total_amount, o.currency, o.ticket_count, f.created_at AS flagged_at
+		FROM purchase_flags f
+		JOIN orders o ON o.id = f.order_id
+		JOIN users u ON u.id = f.buyer_id
+		WHERE f.created_at >= $1::date AND f.created_at < $2::date + 1
+		ORDER BY f.created_at DESC, f.id
+	`
+	var rows []dto.FlaggedPurchaseRow
+	if err := r.db.SelectContext(ctx, &rows, q, start, end); err != nil {
+		return nil, apperror.New(apperror.CodeInternal, "flagged purchases report failed", err)
+	}
+	out := make([]repository.FlaggedPurchaseRow, 0, len(rows))
+	for _, row := range rows {
+		flagID, err := valueobject.ParseUUID(row.FlagID)
+		if err != nil {
+			return nil, apperror.New(apperror.CodeInternal, "invalid flag id in report row", err)
+		}
+		orderID, err := valueobject.ParseUUID(row.OrderID)
+		if err != nil {
+			return nil, apperror.New(apperror.CodeInternal, "invalid order_id in report row", err)
+		}
+		buyerID, err := valueobject.ParseUUID(row.BuyerID)
+		if err != nil {
+			return nil, apperror.New(apperror.CodeInternal, "invalid buyer_id in report row", err)
+		}
+		out = append(out, repository.FlaggedPurchaseRow{
+			FlagID:      flagID,
+			OrderID:     orderID,
+			BuyerID:     buyerID,
+			BuyerEmail:  row.BuyerEmail,
+			ClientIP:    row.ClientIP,
+			Reason:      valueobject.PurchaseFlagReason(row.Reason),
+			Observed:    row.Observed,
+			Threshold:   row.Threshold,
+			OrderStatus: valueobject.OrderStatus(row.OrderStatus),
+			TotalAmount: row.TotalAmount,
+			Currency:    row.Currency,
+			TicketCount: row.TicketCount,
+			FlaggedAt:   row.FlaggedAt,
+		})
+	}
+	return out, nil
+}
+
+func (r *ReportRepo) WaitlistOffers(ctx context.Context, eventID valueobject.UUID) ([]repository.WaitlistReportRow, error) {
+	q := `
+		SELECT tt.id AS ticket_type_id, tt.name AS ticket_type_name,
+		       COUNT(*) FILTER (WHERE w.status = 'waiting') AS waiting,
+		       COUNT(*) FILTER (WHERE w.status = 'offered') AS offered,
+		       COUNT(*) FILTER (WHERE w.status = 'purchased') AS purchased,
+		       COUNT(*) FILTER (WHERE w.status = 'expired') AS expired,
+		       COUNT(*) FILTER (WHERE w.status = 'cancelled') AS cancelled,
+		       COALESCE(SUM(w.quantity) FILTER (WHERE w.status = 'waiting'), 0) AS waiting_tickets
+		FROM waitlist_entries w
+		JOIN ticket_types tt ON tt.id = w.ticket_type_id
+		WHERE tt.event_id = $1
+		GROUP BY tt.id, tt.name
+		ORDER BY tt.name, tt.id
+	`
+	var rows []dto.WaitlistReportRow
+	if err := r.db.SelectContext(ctx, &rows, q, eventID.String()); err != nil {
+		return nil, apperror.New(apperror.CodeInternal, "waitlist report failed", err)
+	}
+	out := make([]repository.WaitlistReportRow, 0, len(rows))
+	for _, row := range rows {
+		tid, err := valueobject.ParseUUID(row.TicketTypeID)
+		if err != nil {
+			return nil, apperror.New(apperror.CodeInternal, "invalid ticket_type_id in waitlist row", err)
+		}
+		out = append(out, repository.WaitlistReportRow{
+			TicketTypeID:   tid,
+			TicketTypeName: row.TicketTypeName,
+			Waiting:        row.Waiting,
+			Offered:        row.Offered,
+			Purchased:      row.Purchased,
+			Expired:        row.Expired,
+			Cancelled:      row.Cancelled,
+			WaitingTickets: row.WaitingTickets,
+		})
+	}
+	return out, nil
+}
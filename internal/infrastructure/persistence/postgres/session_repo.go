@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/internal/infrastructure/persistence/postgres/dto"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type AuthSessionRepo struct{ db *sqlx.DB }
+
+func NewAuthSessionRepo(db *sqlx.DB) *AuthSessionRepo { return &AuthSessionRepo{db: db} }
+
+var _ repository.AuthSessionRepository = (*AuthSessionRepo)(nil)
+
+func (r *AuthSessionRepo) Create(ctx context.Context, s entity.AuthSession) (valueobject.UUID, error) {
+	q := `
+		INSERT INTO auth_sessions (user_id, expires_at, ip_address)
+		VALUES ($1, $2, NULLIF($3, ''))
+		RETURNING id
+	`
+	var id string
+	if err := r.db.QueryRowxContext(ctx, q, s.UserID.String(), s.ExpiresAt, s.IP).Scan(&id); err != nil {
+		return valueobject.Nil, apperror.New(apperror.CodeInternal, "create auth session failed", err)
+	}
+	out, err := valueobject.ParseUUID(id)
+	if err != nil {
+		return valueobject.Nil, apperror.New(apperror.CodeInternal, "invalid uuid returned from db", err)
+	}
+	return out, nil
+}
+
+func (r *AuthSessionRepo) GetByID(ctx context.Context, id valueobject.UUID) (entity.AuthSession, error) {
+	q := `SELECT id, user_id, expires_at, revoked_at, ip_address, created_at FROM auth_sessions WHERE id = $1`
+	var row dto.AuthSessionRow
+	if err := r.db.GetContext(ctx, &row, q, id.String()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.AuthSession{}, apperror.New(apperror.CodeNotFound, "auth session not found", err)
+		}
+		return entity.AuthSession{}, apperror.New(apperror.CodeInternal, "get auth session failed", err)
+	}
+	return mapAuthSessionRow(row)
+}
+
+// Revoke marks the session as revoked and reports whether it was still active.
+func (r *AuthSessionRepo) Revoke(ctx context.Context, id valueobject.UUID) (bool, error) {
+	q := `UPDATE auth_sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+	res, err := r.db.ExecContext(ctx, q, id.String())
+	if err != nil {
+		return false, apperror.New(apperror.CodeInternal, "revoke auth session failed", err)
+	}
+	aff, _ := res.RowsAffected()
+	return aff > 0, nil
+}
+
+func mapAuthSessionRow(row dto.AuthSessionRow) (entity.AuthSession, error) {
+	sid, err := valueobject.ParseUUID(row.ID)
+	if err != nil {
+		return entity.AuthSession{}, apperror.New(apperror.CodeInternal, "invalid auth session id in db", err)
+	}
+	uid, err := valueobject.ParseUUID(row.UserID)
+	if err != nil {
+		return entity.AuthSession{}, apperror.New(apperror.CodeInternal, "invalid auth session user_id in db", err)
+	}
+	s := entity.AuthSession{
+		ID:     sid,
+		UserID: uid,
+	}
+	if row.ExpiresAt.Valid {
+		s.ExpiresAt = row.ExpiresAt.Time
+	}
+	if row.RevokedAt.Valid {
+		t := row.RevokedAt.Time
+		s.RevokedAt = &t
+	}
+	if row.IPAddress.Valid {
+		s.IP = row.IPAddress.String
+	}
+	if row.CreatedAt.Valid {
+		s.CreatedAt = row.CreatedAt.Time
+	}
+	return s, nil
+}
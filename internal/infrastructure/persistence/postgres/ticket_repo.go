@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -16,6 +17,22 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+// priceTiersColumn aggregates a ticket type's price tiers, in order, for TicketTypeRow. The
+// ticket type must be aliased tt.
+const priceTiersColumn = `
+	COALESCE((SELECT json_agg(json_build_object('price', p.price, 'up_to_quantity', p.up_to_quantity, 'ends_at', p.ends_at) ORDER BY p.position)
+	          FROM ticket_price_tiers p WHERE p.ticket_type_id = tt.id), '[]') AS price_tiers`
+
+// insertPriceTiersFrom inserts the tiers passed as a JSON array, numbered in array order, for
+// every ticket type id returned by the named CTE.
+func insertPriceTiersFrom(cte, param string) string {
+	return `
+		INSERT INTO ticket_price_tiers (ticket_type_id, position, price, up_to_quantity, ends_at)
+		SELECT ` + cte + `.id, x.position, x.price, x.up_to_quantity, x.ends_at
+		FROM ` + cte + `, ROWS FROM (jsonb_to_recordset(` + param + `::jsonb) AS (price NUMERIC, up_to_quantity INT, ends_at TIMESTAMPTZ))
+		     WITH ORDINALITY AS x(price, up_to_quantity, ends_at, position)`
+}
+
 type TicketTypeRepo struct{ db *sqlx.DB }
 
 func NewTicketTypeRepo(db *sqlx.DB) *TicketTypeRepo { return &TicketTypeRepo{db: db} }
@@ -24,10 +41,19 @@ var _ repository.TicketTypeRepository = (*TicketTypeRepo)(nil)
 
 func (r *TicketTypeRepo) Create(ctx context.Context, tt entity.TicketType) (valueobject.UUID, error) {
 	q := `
-		INSERT INTO ticket_types (event_id, name, price, quantity_total, quantity_sold, sale_start, sale_end, description, is_active)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9)
-		RETURNING id
+		WITH tt AS (
+			INSERT INTO ticket_types (event_id, name, price, quantity_total, quantity_sold, sale_start, sale_end, description, is_active, currency, reentry_limit,
+			                          refund_policy, refund_deadline_days, refund_percent, max_per_order, max_per_user)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, $10, $11, $12, $13, $14, $16, $17)
+			RETURNING id
+		), tiers AS (` + insertPriceTiersFrom("tt", "$15") + `
+		)
+		SELECT id FROM tt
 	`
+	tiers, err := priceTiersArg(tt.PriceTiers)
+	if err != nil {
+		return valueobject.Nil, err
+	}
 	var id string
 	var saleStart any
 	var saleEnd any
@@ -47,7 +73,18 @@ func (r *TicketTypeRepo) Create(ctx context.Context, tt entity.TicketType) (valu
 		saleEnd,
 		tt.Description,
 		tt.IsActive,
+		tt.Currency.String(),
+		tt.ReentryLimit,
+		string(tt.RefundPolicy),
+		tt.RefundDeadlineDays,
+		tt.RefundPercent,
+		tiers,
+		intPtrArg(tt.MaxPerOrder),
+		intPtrArg(tt.MaxPerUser),
 	).Scan(&id); err != nil {
+		if isUniqueViolation(err) {
+			return valueobject.Nil, apperror.New(apperror.CodeConflict, "ticket type with this name already exists for the event", err)
+		}
 		return valueobject.Nil, apperror.New(apperror.CodeInternal, "create ticket type failed", err)
 	}
 	out, err := valueobject.ParseUUID(id)
@@ -59,8 +96,9 @@ func (r *TicketTypeRepo) Create(ctx context.Context, tt entity.TicketType) (valu
 
 func (r *TicketTypeRepo) GetByID(ctx context.Context, id valueobject.UUID) (entity.TicketType, error) {
 	q := `
-		SELECT id, event_id, name, price, currency, quantity_total, quantity_sold, sale_start, sale_end, description, is_active, created_at, updated_at
-		FROM ticket_types
+		SELECT id, event_id, name, price, currency, quantity_total, quantity_sold, quantity_held, sale_start, sale_end, description, is_active, reentry_limit,
+		       refund_policy, refund_deadline_days, refund_percent, max_per_order, max_per_user, created_at, updated_at,` + priceTiersColumn + `
+		FROM ticket_types tt
 		WHERE id = $1
 	`
 	var row dto.TicketTypeRow
@@ -75,8 +113,9 @@ func (r *TicketTypeRepo) GetByID(ctx context.Context, id valueobject.UUID) (enti
 
 func (r *TicketTypeRepo) ListByEventID(ctx context.Context, eventID valueobject.UUID) ([]entity.TicketType, error) {
 	q := `
-		SELECT id, event_id, name, price, currency, quantity_total, quantity_sold, sale_start, sale_end, description, is_active, created_at, updated_at
-		FROM ticket_types
+		SELECT id, event_id, name, price, currency, quantity_total, quantity_sold, quantity_held, sale_start, sale_end, description, is_active, reentry_limit,
+		       refund_policy, refund_deadline_days, refund_percent, max_per_order, max_per_user, created_at, updated_at,` + priceTiersColumn + `
+		FROM ticket_types tt
 		WHERE event_id = $1
 		ORDER BY id ASC
 	`
@@ -95,13 +134,29 @@ func (r *TicketTypeRepo) ListByEventID(ctx context.Context, eventID valueobject.
 	return out, nil
 }
 
+// Update never writes quantity_sold or quantity_held: they are maintained by triggers. The
+// quantity_total guard is evaluated atomically against the current sold and held counts.
+// Price tiers are replaced in the same statement, under the ticket type's row lock, so a
+// purchase never prices against half-written tiers.
 func (r *TicketTypeRepo) Update(ctx context.Context, tt entity.TicketType) error {
 	q := `
-		UPDATE ticket_types
-		SET name=$1, price=$2, quantity_total=$3, quantity_sold=$4,
-		    sale_start=$5, sale_end=$6, description=NULLIF($7,''), is_active=$8
-		WHERE id=$9
+		WITH tt AS (
+			UPDATE ticket_types
+			SET name=$1, price=$2, quantity_total=$3, currency=$4,
+			    sale_start=$5, sale_end=$6, description=NULLIF($7,''), is_active=$8, reentry_limit=$10,
+			    refund_policy=$11, refund_deadline_days=$12, refund_percent=$13, max_per_order=$15, max_per_user=$16
+			WHERE id=$9 AND quantity_sold + quantity_held <= $3
+			RETURNING id
+		), old AS (
+			DELETE FROM ticket_price_tiers WHERE ticket_type_id IN (SELECT id FROM tt)
+		), tiers AS (` + insertPriceTiersFrom("tt", "$14") + `
+		)
+		SELECT COUNT(*) FROM tt
 	`
+	tiers, err := priceTiersArg(tt.PriceTiers)
+	if err != nil {
+		return err
+	}
 	var saleStart any
 	var saleEnd any
 	if tt.SaleStart != nil {
@@ -110,23 +165,39 @@ func (r *TicketTypeRepo) Update(ctx context.Context, tt entity.TicketType) error
 	if tt.SaleEnd != nil {
 		saleEnd = *tt.SaleEnd
 	}
-	res, err := r.db.ExecContext(ctx, q,
+	var aff int
+	if err := r.db.GetContext(ctx, &aff, q,
 		tt.Name,
 		tt.Price.Amount.StringFixed(2),
 		tt.QuantityTotal,
-		tt.QuantitySold,
+		tt.Currency.String(),
 		saleStart,
 		saleEnd,
 		tt.Description,
 		tt.IsActive,
 		tt.ID.String(),
-	)
-	if err != nil {
+		tt.ReentryLimit,
+		string(tt.RefundPolicy),
+		tt.RefundDeadlineDays,
+		tt.RefundPercent,
+		tiers,
+		intPtrArg(tt.MaxPerOrder),
+		intPtrArg(tt.MaxPerUser),
+	); err != nil {
+		if isUniqueViolation(err) {
+			return apperror.New(apperror.CodeConflict, "ticket type with this name already exists for the event", err)
+		}
 		return apperror.New(apperror.CodeInternal, "update ticket type failed", err)
 	}
-	aff, _ := res.RowsAffected()
 	if aff == 0 {
-		return apperror.New(apperror.CodeNotFound, "ticket type not found", sql.ErrNoRows)
+		var taken int
+		if err := r.db.GetContext(ctx, &taken, `SELECT quantity_sold + quantity_held FROM ticket_types WHERE id=$1`, tt.ID.String()); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return apperror.New(apperror.CodeNotFound, "ticket type not found", err)
+			}
+			return apperror.New(apperror.CodeInternal, "update ticket type failed", err)
+		}
+		return apperror.New(apperror.CodeConflict, fmt.Sprintf("quantity_total cannot be less than sold plus held tickets (%d)", taken), nil)
 	}
 	return nil
 }
@@ -134,6 +205,9 @@ func (r *TicketTypeRepo) Update(ctx context.Context, tt entity.TicketType) error
 func (r *TicketTypeRepo) Delete(ctx context.Context, id valueobject.UUID) error {
 	res, err := r.db.ExecContext(ctx, `DELETE FROM ticket_types WHERE id=$1`, id.String())
 	if err != nil {
+		if isForeignKeyViolation(err) {
+			return apperror.New(apperror.CodeConflict, "ticket type has issued tickets; deactivate it instead", err)
+		}
 		return apperror.New(apperror.CodeInternal, "delete ticket type failed", err)
 	}
 	aff, _ := res.RowsAffected()
@@ -165,10 +239,17 @@ func mapTicketTypeRow(row dto.TicketTypeRow) (entity.TicketType, error) {
 		EventID:       eid,
 		Name:          row.Name,
 		Price:         price,
+		Currency:      valueobject.Currency(row.Currency),
 		QuantityTotal: row.QuantityTotal,
 		QuantitySold:  row.QuantitySold,
+		QuantityHeld:  row.QuantityHeld,
 		Description:   "",
 		IsActive:      row.IsActive,
+		ReentryLimit:  row.ReentryLimit,
+
+		RefundPolicy:       valueobject.RefundPolicy(row.RefundPolicy),
+		RefundDeadlineDays: row.RefundDeadlineDays,
+		RefundPercent:      row.RefundPercent,
 	}
 	if row.Description.Valid {
 		tt.Description = row.Description.String
@@ -181,15 +262,62 @@ func mapTicketTypeRow(row dto.TicketTypeRow) (entity.TicketType, error) {
 		t := row.SaleEnd.Time
 		tt.SaleEnd = &t
 	}
+	if row.MaxPerOrder.Valid {
+		n := int(row.MaxPerOrder.Int64)
+		tt.MaxPerOrder = &n
+	}
+	if row.MaxPerUser.Valid {
+		n := int(row.MaxPerUser.Int64)
+		tt.MaxPerUser = &n
+	}
 	if row.CreatedAt.Valid {
 		tt.CreatedAt = row.CreatedAt.Time
 	}
 	if row.UpdatedAt.Valid {
 		tt.UpdatedAt = row.UpdatedAt.Time
 	}
+	if tt.PriceTiers, err = parsePriceTiers(row.PriceTiers); err != nil {
+		return entity.TicketType{}, err
+	}
 	return tt, nil
 }
 
+func parsePriceTiers(raw json.RawMessage) (entity.PriceTiers, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var rows []dto.PriceTierJSON
+	if err := json.Unmarshal(raw, &rows); err != nil {
+		return nil, fmt.Errorf("invalid price tiers in db: %w", err)
+	}
+	tiers := make(entity.PriceTiers, 0, len(rows))
+	for _, row := range rows {
+		amt, err := decimal.NewFromString(row.Price.String())
+		if err != nil {
+			return nil, fmt.Errorf("invalid price tier price in db: %w", err)
+		}
+		price, err := valueobject.NewMoney(amt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price tier price in db: %w", err)
+		}
+		tiers = append(tiers, entity.PriceTier{Price: price, UpToQuantity: row.UpToQuantity, EndsAt: row.EndsAt})
+	}
+	return tiers, nil
+}
+
+// priceTiersArg encodes tiers for insertPriceTiersFrom.
+func priceTiersArg(tiers entity.PriceTiers) (string, error) {
+	rows := make([]dto.PriceTierJSON, 0, len(tiers))
+	for _, t := range tiers {
+		rows = append(rows, dto.PriceTierJSON{Price: json.Number(t.Price.Amount.StringFixed(2)), UpToQuantity: t.UpToQuantity, EndsAt: t.EndsAt})
+	}
+	b, err := json.Marshal(rows)
+	if err != nil {
+		return "", apperror.New(apperror.CodeInternal, "encode price tiers failed", err)
+	}
+	return string(b), nil
+}
+
 type TicketRepo struct{ db *sqlx.DB }
 
 func NewTicketRepo(db *sqlx.DB) *TicketRepo { return &TicketRepo{db: db} }
@@ -231,7 +359,8 @@ func (r *TicketRepo) Create(ctx context.Context, t entity.Ticket) (valueobject.U
 
 func (r *TicketRepo) GetByID(ctx context.Context, id valueobject.UUID) (entity.Ticket, error) {
 	q := `
-		SELECT id, ticket_type_id, buyer_id, purchase_date, status, qr_code, amount_paid, used_at, created_at, updated_at
+		SELECT id, ticket_type_id, buyer_id, COALESCE(holder_id, buyer_id) AS holder_id, order_id, purchase_date, status, qr_code,
+		       amount_paid, discount_amount, is_comp, comp_kind, used_at, used_by, entry_count, created_at, updated_at
 		FROM tickets
 		WHERE id = $1
 	`
@@ -245,7 +374,7 @@ func (r *TicketRepo) GetByID(ctx context.Context, id valueobject.UUID) (entity.T
 	return mapTicketRow(row)
 }
 
-func (r *TicketRepo) ListByBuyerID(ctx context.Context, buyerID valueobject.UUID, limit, offset int) ([]entity.Ticket, error) {
+func (r *TicketRepo) ListByHolderID(ctx context.Context, holderID valueobject.UUID, limit, offset int) ([]entity.Ticket, error) {
 	if limit <= 0 || limit > 500 {
 		limit = 50
 	}
@@ -253,14 +382,15 @@ func (r *TicketRepo) ListByBuyerID(ctx context.Context, buyerID valueobject.UUID
 		offset = 0
 	}
 	q := `
-		SELECT id, ticket_type_id, buyer_id, purchase_date, status, qr_code, amount_paid, used_at, created_at, updated_at
+		SELECT id, ticket_type_id, buyer_id, COALESCE(holder_id, buyer_id) AS holder_id, order_id, purchase_date, status, qr_code,
+		       amount_paid, discount_amount, is_comp, comp_kind, used_at, used_by, entry_count, created_at, updated_at
 		FROM tickets
-		WHERE buyer_id = $1
+		WHERE COALESCE(holder_id, buyer_id) = $1
 		ORDER BY purchase_date DESC
 		LIMIT $2 OFFSET $3
 	`
 	var rows []dto.TicketRow
-	if err := r.db.SelectContext(ctx, &rows, q, buyerID.String(), limit, offset); err != nil {
+	if err := r.db.SelectContext(ctx, &rows, q, holderID.String(), limit, offset); err != nil {
 		return nil, apperror.New(apperror.CodeInternal, "list tickets failed", err)
 	}
 	out := make([]entity.Ticket, 0, len(rows))
@@ -274,21 +404,57 @@ func (r *TicketRepo) ListByBuyerID(ctx context.Context, buyerID valueobject.UUID
 	return out, nil
 }
 
-func (r *TicketRepo) UpdateStatus(ctx context.Context, id valueobject.UUID, status string) error {
-	st := valueobject.TicketStatus(status)
-	if err := st.Validate(); err != nil {
-		return apperror.New(apperror.CodeValidation, "invalid ticket status", err)
+func (r *TicketRepo) ListByOrderID(ctx context.Context, orderID valueobject.UUID) ([]entity.Ticket, error) {
+	q := `
+		SELECT id, ticket_type_id, buyer_id, COALESCE(holder_id, buyer_id) AS holder_id, order_id, purchase_date, status, qr_code,
+		       amount_paid, discount_amount, is_comp, comp_kind, used_at, used_by, entry_count, created_at, updated_at
+		FROM tickets
+		WHERE order_id = $1
+		ORDER BY ticket_type_id, id
+	`
+	var rows []dto.TicketRow
+	if err := r.db.SelectContext(ctx, &rows, q, orderID.String()); err != nil {
+		return nil, apperror.New(apperror.CodeInternal, "list order tickets failed", err)
 	}
-	q := `UPDATE tickets SET status = $1 WHERE id = $2`
-	res, err := r.db.ExecContext(ctx, q, status, id.String())
-	if err != nil {
-		return apperror.New(apperror.CodeInternal, "update ticket status failed", err)
+	out := make([]entity.Ticket, 0, len(rows))
+	for _, row := range rows {
+		t, err := mapTicketRow(row)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
 	}
-	aff, _ := res.RowsAffected()
-	if aff == 0 {
-		return apperror.New(apperror.CodeNotFound, "ticket not found", sql.ErrNoRows)
+	return out, nil
+}
+
+func (r *TicketRepo) ListCheckinEntries(ctx context.Context, eventID valueobject.UUID) ([]repository.CheckinEntry, error) {
+	q := `
+		SELECT t.id, t.qr_code, t.status, t.entry_count, tt.reentry_limit
+		FROM tickets t
+		JOIN ticket_types tt ON tt.id = t.ticket_type_id
+		WHERE tt.event_id = $1 AND t.status IN ('paid', 'used')
+		  AND NOT EXISTS (SELECT 1 FROM refunds r WHERE r.ticket_id = t.id AND r.status = 'pending')
+		ORDER BY t.id ASC
+	`
+	var rows []dto.CheckinEntryRow
+	if err := r.db.SelectContext(ctx, &rows, q, eventID.String()); err != nil {
+		return nil, apperror.New(apperror.CodeInternal, "list checkin entries failed", err)
 	}
-	return nil
+	out := make([]repository.CheckinEntry, 0, len(rows))
+	for _, row := range rows {
+		id, err := valueobject.ParseUUID(row.ID)
+		if err != nil {
+			return nil, apperror.New(apperror.CodeInternal, "invalid ticket id in db", err)
+		}
+		out = append(out, repository.CheckinEntry{
+			TicketID:     id,
+			QRCode:       row.QRCode,
+			Status:       valueobject.TicketStatus(row.Status),
+			EntryCount:   row.EntryCount,
+			ReentryLimit: row.ReentryLimit,
+		})
+	}
+	return out, nil
 }
 
 func (r *TicketRepo) Delete(ctx context.Context, id valueobject.UUID) error {
@@ -316,6 +482,10 @@ func mapTicketRow(row dto.TicketRow) (entity.Ticket, error) {
 	if err != nil {
 		return entity.Ticket{}, apperror.New(apperror.CodeInternal, "invalid buyer_id in db", err)
 	}
+	hid, err := valueobject.ParseUUID(row.HolderID)
+	if err != nil {
+		return entity.Ticket{}, apperror.New(apperror.CodeInternal, "invalid holder_id in db", err)
+	}
 	st := valueobject.TicketStatus(row.Status)
 	if err := st.Validate(); err != nil {
 		return entity.Ticket{}, apperror.New(apperror.CodeInternal, "invalid ticket status in db", err)
@@ -328,13 +498,30 @@ func mapTicketRow(row dto.TicketRow) (entity.Ticket, error) {
 	if err != nil {
 		return entity.Ticket{}, fmt.Errorf("invalid money in db: %w", err)
 	}
+	discount, err := parseMoney(row.DiscountAmount)
+	if err != nil {
+		return entity.Ticket{}, apperror.New(apperror.CodeInternal, "invalid ticket discount_amount in db", err)
+	}
 	t := entity.Ticket{
-		ID:           tid,
-		TicketTypeID: ttid,
-		BuyerID:      bid,
-		Status:       st,
-		QRCode:       row.QRCode,
-		AmountPaid:   paid,
+		ID:             tid,
+		TicketTypeID:   ttid,
+		BuyerID:        bid,
+		HolderID:       hid,
+		Status:         st,
+		QRCode:         row.QRCode,
+		AmountPaid:     paid,
+		DiscountAmount: discount,
+		Comp:           row.IsComp,
+		EntryCount:     row.EntryCount,
+	}
+	if row.CompKind.Valid {
+		t.CompKind = valueobject.CompKind(row.CompKind.String)
+		if err := t.CompKind.Validate(); err != nil {
+			return entity.Ticket{}, apperror.New(apperror.CodeInternal, "invalid ticket comp_kind in db", err)
+		}
+	}
+	if t.OrderID, err = parseNullUUID(row.OrderID); err != nil {
+		return entity.Ticket{}, apperror.New(apperror.CodeInternal, "invalid order_id in db", err)
 	}
 	if row.PurchaseDate.Valid {
 		t.PurchaseDate = row.PurchaseDate.Time
@@ -343,6 +530,13 @@ func mapTicketRow(row dto.TicketRow) (entity.Ticket, error) {
 		tt := row.UsedAt.Time
 		t.UsedAt = &tt
 	}
+	if row.UsedBy.Valid {
+		uid, err := valueobject.ParseUUID(row.UsedBy.String)
+		if err != nil {
+			return entity.Ticket{}, apperror.New(apperror.CodeInternal, "invalid used_by in db", err)
+		}
+		t.UsedBy = &uid
+	}
 	if row.CreatedAt.Valid {
 		t.CreatedAt = row.CreatedAt.Time
 	}
@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"time2meet/internal/application/port/tickettx"
+	"time2meet/internal/domain/entity"
 	"time2meet/internal/domain/valueobject"
+	"time2meet/internal/infrastructure/persistence/postgres/dto"
 	"time2meet/pkg/apperror"
 
 	"github.com/jmoiron/sqlx"
@@ -19,41 +21,322 @@ func NewTicketTxQueries() *TicketTxQueries { return &TicketTxQueries{} }
 
 var _ tickettx.Queries = (*TicketTxQueries)(nil)
 
-func (q *TicketTxQueries) LockTicketTypeForUpdate(ctx context.Context, tx *sqlx.Tx, ticketTypeID valueobject.UUID) (qtyTotal int, qtySold int, err error) {
-	lockQ := `SELECT quantity_total, quantity_sold FROM ticket_types WHERE id = $1 FOR UPDATE`
-	if err := tx.QueryRowxContext(ctx, lockQ, ticketTypeID.String()).Scan(&qtyTotal, &qtySold); err != nil {
+func (q *TicketTxQueries) LockTicketTypeForUpdate(ctx context.Context, tx *sqlx.Tx, ticketTypeID valueobject.UUID) (tickettx.LockedTicketType, error) {
+	lockQ := `
+		SELECT tt.id, tt.event_id, tt.name, tt.price, tt.currency, tt.quantity_total, tt.quantity_sold, tt.quantity_held,
+		       tt.sale_start, tt.sale_end, tt.description, tt.is_active, tt.reentry_limit,
+		       tt.refund_policy, tt.refund_deadline_days, tt.refund_percent, tt.max_per_order, tt.max_per_user, tt.created_at, tt.updated_at,
+		       e.status AS event_status,
+		       (SELECT MIN(s.start_time) FROM event_schedules s
+		        WHERE s.event_id = tt.event_id AND s.status <> 'cancelled') AS event_starts_at
+		FROM ticket_types tt
+		JOIN events e ON e.id = tt.event_id
+		WHERE tt.id = $1
+		FOR UPDATE OF tt FOR SHARE OF e
+	`
+	var row dto.LockedTicketTypeRow
+	if err := tx.GetContext(ctx, &row, lockQ, ticketTypeID.String()); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return 0, 0, apperror.New(apperror.CodeNotFound, "ticket type not found", err)
+			return tickettx.LockedTicketType{}, apperror.New(apperror.CodeNotFound, "ticket type not found", err)
 		}
-		return 0, 0, apperror.New(apperror.CodeInternal, "lock ticket type failed", err)
+		return tickettx.LockedTicketType{}, apperror.New(apperror.CodeInternal, "lock ticket type failed", err)
+	}
+	// Tiers are read after the lock is granted, so they are never older than the locked row.
+	if err := tx.GetContext(ctx, &row.PriceTiers,
+		`SELECT `+priceTiersColumn+` FROM ticket_types tt WHERE tt.id = $1`, ticketTypeID.String()); err != nil {
+		return tickettx.LockedTicketType{}, apperror.New(apperror.CodeInternal, "load price tiers failed", err)
+	}
+	var waiting struct {
+		Count       int `db:"count"`
+		MinQuantity int `db:"min_quantity"`
+	}
+	if err := tx.GetContext(ctx, &waiting, `
+		SELECT COUNT(*) AS count, COALESCE(MIN(quantity), 0) AS min_quantity
+		FROM waitlist_entries WHERE ticket_type_id = $1 AND status = 'waiting'
+	`, ticketTypeID.String()); err != nil {
+		return tickettx.LockedTicketType{}, apperror.New(apperror.CodeInternal, "count waitlist failed", err)
+	}
+	tt, err := mapTicketTypeRow(row.TicketTypeRow)
+	if err != nil {
+		return tickettx.LockedTicketType{}, apperror.New(apperror.CodeInternal, "invalid ticket type row", err)
+	}
+	out := tickettx.LockedTicketType{
+		ID:            tt.ID,
+		EventID:       tt.EventID,
+		Price:         tt.Price,
+		PriceTiers:    tt.PriceTiers,
+		Currency:      tt.Currency,
+		QuantityTotal: tt.QuantityTotal,
+		QuantitySold:  tt.QuantitySold,
+		QuantityHeld:  tt.QuantityHeld,
+		SaleStart:     tt.SaleStart,
+		SaleEnd:       tt.SaleEnd,
+		IsActive:      tt.IsActive,
+		MaxPerOrder:   tt.MaxPerOrder,
+		MaxPerUser:    tt.MaxPerUser,
+		EventStatus:   valueobject.EventStatus(row.EventStatus),
+
+		WaitlistWaiting:     waiting.Count,
+		WaitlistMinQuantity: waiting.MinQuantity,
+
+		RefundPolicy:       tt.RefundPolicy,
+		RefundDeadlineDays: tt.RefundDeadlineDays,
+		RefundPercent:      tt.RefundPercent,
+	}
+	if row.EventStartsAt.Valid {
+		t := row.EventStartsAt.Time
+		out.EventStartsAt = &t
+	}
+	return out, nil
+}
+
+func (q *TicketTxQueries) InsertHold(ctx context.Context, tx *sqlx.Tx, h entity.TicketHold) error {
+	return insertTicketHold(ctx, tx, h)
+}
+
+func (q *TicketTxQueries) LockHoldForUpdate(ctx context.Context, tx *sqlx.Tx, holdID valueobject.UUID) (entity.TicketHold, error) {
+	return getTicketHold(ctx, tx, selectTicketHold+`WHERE id = $1 FOR UPDATE`, holdID)
+}
+
+func (q *TicketTxQueries) FinishHold(ctx context.Context, tx *sqlx.Tx, holdID valueobject.UUID, status valueobject.HoldStatus, orderID *valueobject.UUID) (bool, error) {
+	res, err := tx.ExecContext(ctx,
+		`UPDATE ticket_holds SET status = $2, order_id = $3 WHERE id = $1 AND status = 'active'`,
+		holdID.String(), string(status), uuidArg(orderID))
+	if err != nil {
+		return false, apperror.New(apperror.CodeInternal, "update hold failed", err)
+	}
+	aff, _ := res.RowsAffected()
+	return aff > 0, nil
+}
+
+func (q *TicketTxQueries) InsertOrder(ctx context.Context, tx *sqlx.Tx, o entity.Order) error {
+	return insertOrder(ctx, tx, o)
+}
+
+func (q *TicketTxQueries) LockOrderForUpdate(ctx context.Context, tx *sqlx.Tx, orderID valueobject.UUID) (entity.Order, error) {
+	return getOrder(ctx, tx, selectOrder+`WHERE id = $1 FOR UPDATE`, orderID)
+}
+
+func (q *TicketTxQueries) SetOrderStatus(ctx context.Context, tx *sqlx.Tx, orderID valueobject.UUID, status valueobject.OrderStatus) error {
+	if _, err := tx.ExecContext(ctx, `UPDATE orders SET status = $2 WHERE id = $1`, orderID.String(), string(status)); err != nil {
+		return apperror.New(apperror.CodeInternal, "update order status failed", err)
+	}
+	return nil
+}
+
+func (q *TicketTxQueries) SetOrderTicketsStatus(ctx context.Context, tx *sqlx.Tx, orderID valueobject.UUID, from, to valueobject.TicketStatus) (int, error) {
+	lockQ := `
+		SELECT id FROM ticket_types
+		WHERE id IN (SELECT ticket_type_id FROM order_items WHERE order_id = $1)
+		ORDER BY id
+		FOR UPDATE
+	`
+	var ids []string
+	if err := tx.SelectContext(ctx, &ids, lockQ, orderID.String()); err != nil {
+		return 0, apperror.New(apperror.CodeInternal, "lock order ticket types failed", err)
+	}
+	res, err := tx.ExecContext(ctx,
+		`UPDATE tickets SET status = $3 WHERE order_id = $1 AND status = $2`,
+		orderID.String(), string(from), string(to))
+	if err != nil {
+		return 0, apperror.New(apperror.CodeInternal, "update order tickets failed", err)
 	}
-	return qtyTotal, qtySold, nil
+	n, _ := res.RowsAffected()
+	return int(n), nil
 }
 
-func (q *TicketTxQueries) InsertPaidTicket(ctx context.Context, tx *sqlx.Tx, ticketTypeID, buyerID valueobject.UUID, purchaseDate time.Time, qrCode string, amountPaid string) (valueobject.UUID, error) {
+func (q *TicketTxQueries) InsertTicket(ctx context.Context, tx *sqlx.Tx, t entity.Ticket) error {
 	insQ := `
-		INSERT INTO tickets (ticket_type_id, buyer_id, purchase_date, status, qr_code, amount_paid)
-		VALUES ($1, $2, $3, 'paid', $4, $5)
-		RETURNING id
+		INSERT INTO tickets (id, order_id, ticket_type_id, buyer_id, purchase_date, status, qr_code, amount_paid, discount_amount, is_comp, comp_kind)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
-	var id string
-	if err := tx.QueryRowxContext(ctx, insQ, ticketTypeID.String(), buyerID.String(), purchaseDate, qrCode, amountPaid).Scan(&id); err != nil {
-		return valueobject.Nil, apperror.New(apperror.CodeInternal, "insert ticket failed", err)
+	var compKind any
+	if t.Comp {
+		compKind = string(t.CompKind)
+	}
+	if _, err := tx.ExecContext(ctx, insQ,
+		t.ID.String(), uuidArg(t.OrderID), t.TicketTypeID.String(), t.BuyerID.String(), t.PurchaseDate,
+		string(t.Status), t.QRCode, t.AmountPaid.Amount.StringFixed(2), t.DiscountAmount.Amount.StringFixed(2),
+		t.Comp, compKind,
+	); err != nil {
+		return apperror.New(apperror.CodeInternal, "insert ticket failed", err)
+	}
+	return nil
+}
+
+func (q *TicketTxQueries) InsertPayment(ctx context.Context, tx *sqlx.Tx, p entity.Payment) error {
+	return insertPayment(ctx, tx, p)
+}
+
+func (q *TicketTxQueries) LockPaymentForUpdate(ctx context.Context, tx *sqlx.Tx, paymentID valueobject.UUID) (entity.Payment, error) {
+	return getPayment(ctx, tx, selectPayment+`WHERE id = $1 FOR UPDATE`, paymentID.String())
+}
+
+func (q *TicketTxQueries) TransitionPayment(ctx context.Context, tx *sqlx.Tx, paymentID valueobject.UUID, from, to valueobject.PaymentStatus, failureReason string) (bool, error) {
+	res, err := tx.ExecContext(ctx,
+		`UPDATE payments SET status = $3, failure_reason = COALESCE(NULLIF($4, ''), failure_reason) WHERE id = $1 AND status = $2`,
+		paymentID.String(), string(from), string(to), failureReason)
+	if err != nil {
+		return false, apperror.New(apperror.CodeInternal, "update payment failed", err)
+	}
+	aff, _ := res.RowsAffected()
+	return aff > 0, nil
+}
+
+func (q *TicketTxQueries) RecordPaymentEvent(ctx context.Context, tx *sqlx.Tx, provider, eventID, eventType, intentID string) (bool, error) {
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO payment_webhook_events (provider, event_id, event_type, intent_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (provider, event_id) DO NOTHING
+	`, provider, eventID, eventType, intentID)
+	if err != nil {
+		return false, apperror.New(apperror.CodeInternal, "record payment event failed", err)
+	}
+	aff, _ := res.RowsAffected()
+	return aff > 0, nil
+}
+
+func (q *TicketTxQueries) InsertRefund(ctx context.Context, tx *sqlx.Tx, r entity.Refund) error {
+	return insertRefund(ctx, tx, r)
+}
+
+func (q *TicketTxQueries) FinishRefund(ctx context.Context, tx *sqlx.Tx, refundID valueobject.UUID, status valueobject.RefundStatus, providerRefundID, failureReason string) (bool, error) {
+	res, err := tx.ExecContext(ctx, `
+		UPDATE refunds
+		SET status = $2,
+		    provider_refund_id = COALESCE(NULLIF($3, ''), provider_refund_id),
+		    failure_reason = NULLIF($4, '')
+		WHERE id = $1 AND status = 'pending'
+	`, refundID.String(), string(status), providerRefundID, failureReason)
+	if err != nil {
+		return false, apperror.New(apperror.CodeInternal, "update refund failed", err)
+	}
+	aff, _ := res.RowsAffected()
+	return aff > 0, nil
+}
+
+func (q *TicketTxQueries) ReleaseRefundedTicket(ctx context.Context, tx *sqlx.Tx, ticketID valueobject.UUID) (bool, error) {
+	if _, err := tx.ExecContext(ctx, `
+		SELECT tt.id FROM ticket_types tt
+		WHERE tt.id = (SELECT ticket_type_id FROM tickets WHERE id = $1)
+		FOR UPDATE
+	`, ticketID.String()); err != nil {
+		return false, apperror.New(apperror.CodeInternal, "lock ticket type failed", err)
+	}
+	res, err := tx.ExecContext(ctx,
+		`UPDATE tickets SET status = 'refunded' WHERE id = $1 AND status = 'paid'`, ticketID.String())
+	if err != nil {
+		return false, apperror.New(apperror.CodeInternal, "update ticket status failed", err)
 	}
-	uid, err := valueobject.ParseUUID(id)
+	aff, _ := res.RowsAffected()
+	return aff > 0, nil
+}
+
+func (q *TicketTxQueries) LockPaymentRefunds(ctx context.Context, tx *sqlx.Tx, paymentID valueobject.UUID) ([]entity.Refund, error) {
+	return listRefunds(ctx, tx, selectRefund+`WHERE payment_id = $1 ORDER BY created_at, id FOR UPDATE`, paymentID.String())
+}
+
+func (q *TicketTxQueries) SetTicketStatus(ctx context.Context, tx *sqlx.Tx, ticketID valueobject.UUID, from, to valueobject.TicketStatus) (bool, error) {
+	res, err := tx.ExecContext(ctx,
+		`UPDATE tickets SET status = $3 WHERE id = $1 AND status = $2`,
+		ticketID.String(), string(from), string(to))
 	if err != nil {
-		return valueobject.Nil, apperror.New(apperror.CodeInternal, "invalid uuid returned from db", err)
+		return false, apperror.New(apperror.CodeInternal, "update ticket status failed", err)
+	}
+	aff, _ := res.RowsAffected()
+	return aff > 0, nil
+}
+
+func (q *TicketTxQueries) InsertTicketStatusChange(ctx context.Context, tx *sqlx.Tx, c entity.TicketStatusChange) error {
+	insQ := `
+		INSERT INTO ticket_status_changes (id, ticket_id, from_status, to_status, reason, changed_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	if _, err := tx.ExecContext(ctx, insQ,
+		c.ID.String(), c.TicketID.String(), string(c.From), string(c.To), c.Reason, uuidArg(&c.ChangedBy), c.CreatedAt,
+	); err != nil {
+		return apperror.New(apperror.CodeInternal, "insert ticket status change failed", err)
+	}
+	return nil
+}
+
+func (q *TicketTxQueries) LockTicketForUpdate(ctx context.Context, tx *sqlx.Tx, ticketID valueobject.UUID) (tickettx.LockedTicket, error) {
+	lockQ := `
+		SELECT t.id, t.ticket_type_id, tt.event_id, t.buyer_id, COALESCE(t.holder_id, t.buyer_id) AS holder_id,
+		       t.order_id, t.status, t.qr_code,
+		       t.amount_paid, t.used_at, t.used_by, t.used_by_device_id, t.entry_count, tt.reentry_limit,
+		       EXISTS (SELECT 1 FROM refunds r WHERE r.ticket_id = t.id AND r.status = 'pending') AS refund_pending
+		FROM tickets t
+		JOIN ticket_types tt ON tt.id = t.ticket_type_id
+		WHERE t.id = $1
+		FOR UPDATE OF t
+	`
+	var row dto.LockedTicketRow
+	if err := tx.GetContext(ctx, &row, lockQ, ticketID.String()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return tickettx.LockedTicket{}, apperror.New(apperror.CodeNotFound, "ticket not found", err)
+		}
+		return tickettx.LockedTicket{}, apperror.New(apperror.CodeInternal, "lock ticket failed", err)
+	}
+	out := tickettx.LockedTicket{
+		Status:        valueobject.TicketStatus(row.Status),
+		QRCode:        row.QRCode,
+		EntryCount:    row.EntryCount,
+		ReentryLimit:  row.ReentryLimit,
+		RefundPending: row.RefundPending,
+	}
+	var err error
+	if out.ID, err = valueobject.ParseUUID(row.ID); err != nil {
+		return tickettx.LockedTicket{}, apperror.New(apperror.CodeInternal, "invalid ticket id in db", err)
+	}
+	if out.TicketTypeID, err = valueobject.ParseUUID(row.TicketTypeID); err != nil {
+		return tickettx.LockedTicket{}, apperror.New(apperror.CodeInternal, "invalid ticket_type_id in db", err)
+	}
+	if out.EventID, err = valueobject.ParseUUID(row.EventID); err != nil {
+		return tickettx.LockedTicket{}, apperror.New(apperror.CodeInternal, "invalid event_id in db", err)
+	}
+	if out.BuyerID, err = valueobject.ParseUUID(row.BuyerID); err != nil {
+		return tickettx.LockedTicket{}, apperror.New(apperror.CodeInternal, "invalid buyer_id in db", err)
+	}
+	if out.HolderID, err = valueobject.ParseUUID(row.HolderID); err != nil {
+		return tickettx.LockedTicket{}, apperror.New(apperror.CodeInternal, "invalid holder_id in db", err)
+	}
+	if out.OrderID, err = parseNullUUID(row.OrderID); err != nil {
+		return tickettx.LockedTicket{}, apperror.New(apperror.CodeInternal, "invalid order_id in db", err)
+	}
+	if out.AmountPaid, err = parseMoney(row.AmountPaid); err != nil {
+		return tickettx.LockedTicket{}, apperror.New(apperror.CodeInternal, "invalid amount_paid in db", err)
 	}
-	return uid, nil
+	if row.UsedAt.Valid {
+		t := row.UsedAt.Time
+		out.UsedAt = &t
+	}
+	if row.UsedBy.Valid {
+		uid, err := valueobject.ParseUUID(row.UsedBy.String)
+		if err != nil {
+			return tickettx.LockedTicket{}, apperror.New(apperror.CodeInternal, "invalid used_by in db", err)
+		}
+		out.UsedBy = &uid
+	}
+	if out.UsedByDeviceID, err = parseNullUUID(row.UsedByDevice); err != nil {
+		return tickettx.LockedTicket{}, apperror.New(apperror.CodeInternal, "invalid used_by_device_id in db", err)
+	}
+	return out, nil
 }
 
-func (q *TicketTxQueries) MarkTicketUsed(ctx context.Context, tx *sqlx.Tx, ticketID valueobject.UUID, usedAt time.Time) (bool, error) {
+func (q *TicketTxQueries) MarkTicketUsed(ctx context.Context, tx *sqlx.Tx, ticketID valueobject.UUID, usedAt time.Time, staffID, deviceID *valueobject.UUID, maxEntries int) (bool, error) {
+	// The scanner is only recorded with the first admission, when used_at is still NULL.
 	upd := `
 		UPDATE tickets
-		SET status = 'used', used_at = $1
-		WHERE id = $2 AND status IN ('paid', 'used')
+		SET status = 'used',
+		    used_by = CASE WHEN used_at IS NULL THEN $2 ELSE used_by END,
+		    used_by_device_id = CASE WHEN used_at IS NULL THEN $3 ELSE used_by_device_id END,
+		    used_at = COALESCE(used_at, $1),
+		    entry_count = entry_count + 1
+		WHERE id = $4
+		  AND (status = 'paid' OR (status = 'used' AND entry_count < $5))
 	`
-	res, err := tx.ExecContext(ctx, upd, usedAt, ticketID.String())
+	res, err := tx.ExecContext(ctx, upd, usedAt, uuidArg(staffID), uuidArg(deviceID), ticketID.String(), maxEntries)
 	if err != nil {
 		return false, apperror.New(apperror.CodeInternal, "validate ticket failed", err)
 	}
@@ -61,4 +344,205 @@ func (q *TicketTxQueries) MarkTicketUsed(ctx context.Context, tx *sqlx.Tx, ticke
 	return aff > 0, nil
 }
 
+func (q *TicketTxQueries) InsertCheckin(ctx context.Context, tx *sqlx.Tx, c entity.Checkin) error {
+	_, err := insertCheckin(ctx, tx, c)
+	return err
+}
+
+func (q *TicketTxQueries) InsertTransfer(ctx context.Context, tx *sqlx.Tx, t entity.TicketTransfer) error {
+	return insertTicketTransfer(ctx, tx, t)
+}
+
+func (q *TicketTxQueries) LockTransferForUpdate(ctx context.Context, tx *sqlx.Tx, transferID valueobject.UUID) (entity.TicketTransfer, error) {
+	return getTicketTransfer(ctx, tx, selectTicketTransfer+`WHERE id = $1 FOR UPDATE`, transferID)
+}
+
+func (q *TicketTxQueries) FinishTransfer(ctx context.Context, tx *sqlx.Tx, transferID valueobject.UUID, status valueobject.TransferStatus, toUserID *valueobject.UUID, acceptedAt *time.Time) (bool, error) {
+	var accepted any
+	if acceptedAt != nil {
+		accepted = *acceptedAt
+	}
+	res, err := tx.ExecContext(ctx,
+		`UPDATE ticket_transfers SET status = $2, to_user_id = $3, accepted_at = $4 WHERE id = $1 AND status = 'pending'`,
+		transferID.String(), string(status), uuidArg(toUserID), accepted)
+	if err != nil {
+		return false, apperror.New(apperror.CodeInternal, "update transfer failed", err)
+	}
+	aff, _ := res.RowsAffected()
+	return aff > 0, nil
+}
+
+func (q *TicketTxQueries) SetTicketHolder(ctx context.Context, tx *sqlx.Tx, ticketID, holderID valueobject.UUID, qrCode string) error {
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE tickets SET holder_id = $2, qr_code = $3 WHERE id = $1`,
+		ticketID.String(), holderID.String(), qrCode); err != nil {
+		return apperror.New(apperror.CodeInternal, "update ticket holder failed", err)
+	}
+	return nil
+}
+
+func (q *TicketTxQueries) LockPromoCodeByCode(ctx context.Context, tx *sqlx.Tx, code string, eventIDs []valueobject.UUID) (entity.PromoCode, error) {
+	return lockPromoCodeByCode(ctx, tx, code, eventIDs)
+}
+
+func (q *TicketTxQueries) CountPromoRedemptions(ctx context.Context, tx *sqlx.Tx, promoCodeID, userID valueobject.UUID) (int, error) {
+	var n int
+	if err := tx.GetContext(ctx, &n,
+		`SELECT COUNT(*) FROM promo_redemptions WHERE promo_code_id = $1 AND user_id = $2 AND status = 'applied'`,
+		promoCodeID.String(), userID.String()); err != nil {
+		return 0, apperror.New(apperror.CodeInternal, "count promo redemptions failed", err)
+	}
+	return n, nil
+}
+
+func (q *TicketTxQueries) InsertPromoRedemption(ctx context.Context, tx *sqlx.Tx, r entity.PromoRedemption) error {
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO promo_redemptions (id, promo_code_id, order_id, user_id, discount_amount, currency, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, r.ID.String(), r.PromoCodeID.String(), r.OrderID.String(), r.UserID.String(),
+		r.DiscountAmount.Amount.StringFixed(2), r.Currency.String(), string(r.Status)); err != nil {
+		return apperror.New(apperror.CodeInternal, "insert promo redemption failed", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE promo_codes SET used_count = used_count + 1 WHERE id = $1`, r.PromoCodeID.String()); err != nil {
+		return apperror.New(apperror.CodeInternal, "update promo code usage failed", err)
+	}
+	return nil
+}
+
+func (q *TicketTxQueries) ReleasePromoRedemption(ctx context.Context, tx *sqlx.Tx, orderID valueobject.UUID) error {
+	if _, err := tx.ExecContext(ctx, `
+		WITH r AS (
+			UPDATE promo_redemptions SET status = 'released'
+			WHERE order_id = $1 AND status = 'applied'
+			RETURNING promo_code_id
+		)
+		UPDATE promo_codes SET used_count = used_count - 1
+		WHERE id IN (SELECT promo_code_id FROM r)
+	`, orderID.String()); err != nil {
+		return apperror.New(apperror.CodeInternal, "release promo redemption failed", err)
+	}
+	return nil
+}
+
+func (q *TicketTxQueries) CountBuyerTickets(ctx context.Context, tx *sqlx.Tx, buyerID, ticketTypeID valueobject.UUID) (int, error) {
+	var n int
+	if err := tx.GetContext(ctx, &n, `
+		SELECT (SELECT COUNT(*) FROM tickets
+		        WHERE buyer_id = $1 AND ticket_type_id = $2 AND status IN ('pending_payment', 'paid', 'used') AND NOT is_comp)
+		     + (SELECT COALESCE(SUM(quantity), 0) FROM ticket_holds
+		        WHERE user_id = $1 AND ticket_type_id = $2 AND status = 'active')
+	`, buyerID.String(), ticketTypeID.String()); err != nil {
+		return 0, apperror.New(apperror.CodeInternal, "count buyer tickets failed", err)
+	}
+	return n, nil
+}
+
+func (q *TicketTxQueries) CountIPOrders(ctx context.Context, tx *sqlx.Tx, ip string, since time.Time) (int, int, error) {
+	var row dto.IPOrderCountRow
+	if err := tx.GetContext(ctx, &row, `
+		SELECT COUNT(*) AS orders, COUNT(DISTINCT buyer_id) AS buyers
+		FROM orders
+		WHERE client_ip = $1 AND created_at >= $2
+	`, ip, since); err != nil {
+		return 0, 0, apperror.New(apperror.CodeInternal, "count ip orders failed", err)
+	}
+	return row.Orders, row.Buyers, nil
+}
+
+func (q *TicketTxQueries) InsertPurchaseFlag(ctx context.Context, tx *sqlx.Tx, f entity.PurchaseFlag) error {
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO purchase_flags (id, order_id, buyer_id, client_ip, reason, observed, threshold, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (order_id, reason) DO NOTHING
+	`, f.ID.String(), f.OrderID.String(), f.BuyerID.String(), f.ClientIP, string(f.Reason),
+		f.Observed, f.Threshold, f.CreatedAt); err != nil {
+		return apperror.New(apperror.CodeInternal, "insert purchase flag failed", err)
+	}
+	return nil
+}
+
+func (q *TicketTxQueries) InsertWaitlistEntry(ctx context.Context, tx *sqlx.Tx, e entity.WaitlistEntry) error {
+	return insertWaitlistEntry(ctx, tx, e)
+}
+
+func (q *TicketTxQueries) LockWaitlistEntryForUpdate(ctx context.Context, tx *sqlx.Tx, entryID valueobject.UUID) (entity.WaitlistEntry, error) {
+	return getWaitlistEntry(ctx, tx, selectWaitlistEntry+`WHERE w.id = $1 FOR UPDATE OF w`, entryID.String())
+}
+
+func (q *TicketTxQueries) LockNextWaitlistEntry(ctx context.Context, tx *sqlx.Tx, ticketTypeID valueobject.UUID, maxQuantity int) (entity.WaitlistEntry, bool, error) {
+	var row dto.WaitlistEntryRow
+	if err := tx.GetContext(ctx, &row, selectWaitlistEntry+`
+		WHERE w.ticket_type_id = $1 AND w.status = 'waiting' AND w.quantity <= $2
+		ORDER BY w.created_at, w.id
+		LIMIT 1
+		FOR UPDATE OF w SKIP LOCKED
+	`, ticketTypeID.String(), maxQuantity); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.WaitlistEntry{}, false, nil
+		}
+		return entity.WaitlistEntry{}, false, apperror.New(apperror.CodeInternal, "lock next waitlist entry failed", err)
+	}
+	e, err := mapWaitlistEntryRow(row)
+	if err != nil {
+		return entity.WaitlistEntry{}, false, err
+	}
+	return e, true, nil
+}
+
+func (q *TicketTxQueries) OfferWaitlistEntry(ctx context.Context, tx *sqlx.Tx, entryID, holdID valueobject.UUID, offeredAt, expiresAt time.Time) (bool, error) {
+	res, err := tx.ExecContext(ctx, `
+		UPDATE waitlist_entries SET status = 'offered', hold_id = $2, offered_at = $3, offer_expires_at = $4
+		WHERE id = $1 AND status = 'waiting'
+	`, entryID.String(), holdID.String(), offeredAt, expiresAt)
+	if err != nil {
+		return false, apperror.New(apperror.CodeInternal, "offer waitlist entry failed", err)
+	}
+	aff, _ := res.RowsAffected()
+	return aff > 0, nil
+}
+
+func (q *TicketTxQueries) SetWaitlistEntryStatus(ctx context.Context, tx *sqlx.Tx, entryID valueobject.UUID, from, to valueobject.WaitlistStatus) (bool, error) {
+	res, err := tx.ExecContext(ctx,
+		`UPDATE waitlist_entries SET status = $3 WHERE id = $1 AND status = $2`,
+		entryID.String(), string(from), string(to))
+	if err != nil {
+		return false, apperror.New(apperror.CodeInternal, "update waitlist entry failed", err)
+	}
+	aff, _ := res.RowsAffected()
+	return aff > 0, nil
+}
 
+// EnsureUserByEmail stores an empty password hash for new users; it never verifies, so the
+// account cannot sign in until its owner claims it through /auth/claim or an admin sets a
+// password.
+func (q *TicketTxQueries) EnsureUserByEmail(ctx context.Context, tx *sqlx.Tx, email valueobject.Email, fullName string) (valueobject.UUID, bool, error) {
+	var row struct {
+		ID      string `db:"id"`
+		Created bool   `db:"created"`
+	}
+	err := tx.GetContext(ctx, &row, `
+		WITH ins AS (
+			INSERT INTO users (email, password_hash, full_name, role, is_active)
+			VALUES ($1, '', $2, 'attendee', true)
+			ON CONFLICT (email) DO NOTHING
+			RETURNING id
+		)
+		SELECT id, true AS created FROM ins
+		UNION ALL
+		SELECT id, false AS created FROM users WHERE email = $1
+		LIMIT 1
+	`, email.String(), fullName)
+	if errors.Is(err, sql.ErrNoRows) {
+		// A concurrent insert of the same email is not visible to this statement yet.
+		return valueobject.Nil, false, apperror.New(apperror.CodeConflict, "user is being created concurrently, retry", err)
+	}
+	if err != nil {
+		return valueobject.Nil, false, apperror.New(apperror.CodeInternal, "ensure user failed", err)
+	}
+	id, err := valueobject.ParseUUID(row.ID)
+	if err != nil {
+		return valueobject.Nil, false, apperror.New(apperror.CodeInternal, "invalid user id in db", err)
+	}
+	return id, row.Created, nil
+}
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/internal/infrastructure/persistence/postgres/dto"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const selectTicketTransfer = `
+	SELECT id, ticket_id, from_user_id, to_email, to_user_id, status, expires_at, accepted_at, created_at, updated_at
+	FROM ticket_transfers
+`
+
+type TicketTransferRepo struct{ db *sqlx.DB }
+
+func NewTicketTransferRepo(db *sqlx.DB) *TicketTransferRepo { return &TicketTransferRepo{db: db} }
+
+var _ repository.TicketTransferRepository = (*TicketTransferRepo)(nil)
+
+func (r *TicketTransferRepo) GetByID(ctx context.Context, id valueobject.UUID) (entity.TicketTransfer, error) {
+	return getTicketTransfer(ctx, r.db, selectTicketTransfer+`WHERE id = $1`, id)
+}
+
+func (r *TicketTransferRepo) ListByTicketID(ctx context.Context, ticketID valueobject.UUID) ([]entity.TicketTransfer, error) {
+	return listTicketTransfers(ctx, r.db, selectTicketTransfer+`WHERE ticket_id = $1 ORDER BY created_at, id`, ticketID.String())
+}
+
+func (r *TicketTransferRepo) ListPendingByEmail(ctx context.Context, email valueobject.Email, now time.Time) ([]entity.TicketTransfer, error) {
+	return listTicketTransfers(ctx, r.db, selectTicketTransfer+`
+		WHERE to_email = $1 AND status = 'pending' AND expires_at > $2
+		ORDER BY created_at, id
+	`, email.String(), now)
+}
+
+func (r *TicketTransferRepo) ExpireDue(ctx context.Context, now time.Time, limit int) (int, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE ticket_transfers SET status = 'expired'
+		WHERE id IN (
+			SELECT id FROM ticket_transfers
+			WHERE status = 'pending' AND expires_at <= $1
+			ORDER BY expires_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+	`, now, limit)
+	if err != nil {
+		return 0, apperror.New(apperror.CodeInternal, "expire transfers failed", err)
+	}
+	aff, _ := res.RowsAffected()
+	return int(aff), nil
+}
+
+func insertTicketTransfer(ctx context.Context, db sqlx.ExtContext, t entity.TicketTransfer) error {
+	q := `
+		INSERT INTO ticket_transfers (id, ticket_id, from_user_id, to_email, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	if _, err := db.ExecContext(ctx, q,
+		t.ID.String(), t.TicketID.String(), t.FromUserID.String(), t.ToEmail.String(), string(t.Status), t.ExpiresAt,
+	); err != nil {
+		if isUniqueViolation(err) {
+			return apperror.New(apperror.CodeConflict, "ticket already has a pending transfer", err)
+		}
+		return apperror.New(apperror.CodeInternal, "insert transfer failed", err)
+	}
+	return nil
+}
+
+func getTicketTransfer(ctx context.Context, db sqlx.QueryerContext, q string, id valueobject.UUID) (entity.TicketTransfer, error) {
+	var row dto.TicketTransferRow
+	if err := sqlx.GetContext(ctx, db, &row, q, id.String()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.TicketTransfer{}, apperror.New(apperror.CodeNotFound, "transfer not found", err)
+		}
+		return entity.TicketTransfer{}, apperror.New(apperror.CodeInternal, "get transfer failed", err)
+	}
+	return mapTicketTransferRow(row)
+}
+
+func listTicketTransfers(ctx context.Context, db sqlx.QueryerContext, q string, args ...any) ([]entity.TicketTransfer, error) {
+	var rows []dto.TicketTransferRow
+	if err := sqlx.SelectContext(ctx, db, &rows, q, args...); err != nil {
+		return nil, apperror.New(apperror.CodeInternal, "list transfers failed", err)
+	}
+	out := make([]entity.TicketTransfer, 0, len(rows))
+	for _, row := range rows {
+		t, err := mapTicketTransferRow(row)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+func mapTicketTransferRow(row dto.TicketTransferRow) (entity.TicketTransfer, error) {
+	id, err := valueobject.ParseUUID(row.ID)
+	if err != nil {
+		return entity.TicketTransfer{}, apperror.New(apperror.CodeInternal, "invalid transfer id in db", err)
+	}
+	ticketID, err := valueobject.ParseUUID(row.TicketID)
+	if err != nil {
+		return entity.TicketTransfer{}, apperror.New(apperror.CodeInternal, "invalid transfer ticket_id in db", err)
+	}
+	fromID, err := valueobject.ParseUUID(row.FromUserID)
+	if err != nil {
+		return entity.TicketTransfer{}, apperror.New(apperror.CodeInternal, "invalid transfer from_user_id in db", err)
+	}
+	toID, err := parseNullUUID(row.ToUserID)
+	if err != nil {
+		return entity.TicketTransfer{}, apperror.New(apperror.CodeInternal, "invalid transfer to_user_id in db", err)
+	}
+	st := valueobject.TransferStatus(row.Status)
+	if err := st.Validate(); err != nil {
+		return entity.TicketTransfer{}, apperror.New(apperror.CodeInternal, "invalid transfer status in db", err)
+	}
+	t := entity.TicketTransfer{
+		ID:         id,
+		TicketID:   ticketID,
+		FromUserID: fromID,
+		ToEmail:    valueobject.Email(row.ToEmail),
+		ToUserID:   toID,
+		Status:     st,
+	}
+	if row.ExpiresAt.Valid {
+		t.ExpiresAt = row.ExpiresAt.Time
+	}
+	if row.AcceptedAt.Valid {
+		at := row.AcceptedAt.Time
+		t.AcceptedAt = &at
+	}
+	if row.CreatedAt.Valid {
+		t.CreatedAt = row.CreatedAt.Time
+	}
+	if row.UpdatedAt.Valid {
+		t.UpdatedAt = row.UpdatedAt.Time
+	}
+	return t, nil
+}
@@ -105,7 +105,7 @@ func (r *UserRepo) List(ctx context.Context, limit, offset int) ([]entity.User,
 func (r *UserRepo) Update(ctx context.Context, u entity.User) error {
 	q := `
 		UPDATE users
-		SET email = $1, password_hash = $2, full_name = $3, phone = NULLIF($4, ''), role = $5, is_active = $6
+		SET email = $1, password_hash = COALESCE(NULLIF($2, ''), password_hash), full_name = $3, phone = NULLIF($4, ''), role = $5, is_active = $6
 		WHERE id = $7
 	`
 	res, err := r.db.ExecContext(ctx, q,
@@ -127,6 +127,30 @@ func (r *UserRepo) Update(ctx context.Context, u entity.User) error {
 	return nil
 }
 
+func (r *UserRepo) UpdatePasswordHash(ctx context.Context, id valueobject.UUID, hash string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE users SET password_hash = $1 WHERE id = $2`, hash, id.String())
+	if err != nil {
+		return apperror.New(apperror.CodeInternal, "update password hash failed", err)
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return apperror.New(apperror.CodeNotFound, "user not found", sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *UserRepo) ClaimPasswordHash(ctx context.Context, id valueobject.UUID, hash string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE users SET password_hash = $1
+		WHERE id = $2 AND password_hash = '' AND is_active
+	`, hash, id.String())
+	if err != nil {
+		return false, apperror.New(apperror.CodeInternal, "claim password hash failed", err)
+	}
+	aff, _ := res.RowsAffected()
+	return aff > 0, nil
+}
+
 func (r *UserRepo) Delete(ctx context.Context, id valueobject.UUID) error {
 	res, err := r.db.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, id.String())
 	if err != nil {
@@ -0,0 +1,209 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/internal/infrastructure/persistence/postgres/dto"
+	"time2meet/pkg/apperror"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// selectWaitlistEntry computes the queue position of waiting entries; ties on created_at are
+// broken by id, as when entries are offered.
+const selectWaitlistEntry = `
+	SELECT w.id, w.ticket_type_id, w.user_id, w.quantity, w.status, w.hold_id, w.offered_at, w.offer_expires_at,
+	       CASE WHEN w.status = 'waiting' THEN (
+	           SELECT COUNT(*) FROM waitlist_entries o
+	           WHERE o.ticket_type_id = w.ticket_type_id AND o.status = 'waiting'
+	             AND (o.created_at, o.id) <= (w.created_at, w.id)
+	       ) ELSE 0 END AS position,
+	       w.created_at, w.updated_at
+	FROM waitlist_entries w
+`
+
+type WaitlistRepo struct{ db *sqlx.DB }
+
+func NewWaitlistRepo(db *sqlx.DB) *WaitlistRepo { return &WaitlistRepo{db: db} }
+
+var _ repository.WaitlistRepository = (*WaitlistRepo)(nil)
+
+func (r *WaitlistRepo) GetByID(ctx context.Context, id valueobject.UUID) (entity.WaitlistEntry, error) {
+	return getWaitlistEntry(ctx, r.db, selectWaitlistEntry+`WHERE w.id = $1`, id.String())
+}
+
+func (r *WaitlistRepo) ListByUserID(ctx context.Context, userID valueobject.UUID, limit, offset int) ([]entity.WaitlistEntry, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return listWaitlistEntries(ctx, r.db, selectWaitlistEntry+`
+		WHERE w.user_id = $1
+		ORDER BY w.created_at DESC, w.id
+		LIMIT $2 OFFSET $3
+	`, userID.String(), limit, offset)
+}
+
+func (r *WaitlistRepo) ListByTicketTypeID(ctx context.Context, ticketTypeID valueobject.UUID, limit, offset int) ([]entity.WaitlistEntry, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return listWaitlistEntries(ctx, r.db, selectWaitlistEntry+`
+		WHERE w.ticket_type_id = $1 AND w.status IN ('waiting', 'offered')
+		ORDER BY w.status = 'waiting', w.created_at, w.id
+		LIMIT $2 OFFSET $3
+	`, ticketTypeID.String(), limit, offset)
+}
+
+func (r *WaitlistRepo) SettleOffers(ctx context.Context, limit int) (int, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE waitlist_entries w
+		SET status = CASE h.status
+		                 WHEN 'converted' THEN 'purchased'
+		                 WHEN 'expired' THEN 'expired'
+		                 ELSE 'cancelled'
+		             END
+		FROM ticket_holds h
+		WHERE h.id = w.hold_id AND w.id IN (
+			SELECT e.id FROM waitlist_entries e
+			JOIN ticket_holds eh ON eh.id = e.hold_id
+			WHERE e.status = 'offered' AND eh.status <> 'active'
+			LIMIT $1
+			FOR UPDATE OF e SKIP LOCKED
+		)
+	`, limit)
+	if err != nil {
+		return 0, apperror.New(apperror.CodeInternal, "settle waitlist offers failed", err)
+	}
+	aff, _ := res.RowsAffected()
+	return int(aff), nil
+}
+
+func (r *WaitlistRepo) ListOfferable(ctx context.Context, now time.Time, limit int) ([]valueobject.UUID, error) {
+	var ids []string
+	if err := r.db.SelectContext(ctx, &ids, `
+		SELECT tt.id FROM ticket_types tt
+		JOIN events e ON e.id = tt.event_id
+		WHERE e.status = 'published' AND tt.is_active
+		  AND (tt.sale_start IS NULL OR tt.sale_start <= $1)
+		  AND (tt.sale_end IS NULL OR tt.sale_end > $1)
+		  AND EXISTS (
+		      SELECT 1 FROM waitlist_entries w
+		      WHERE w.ticket_type_id = tt.id AND w.status = 'waiting'
+		        AND w.quantity <= tt.quantity_total - tt.quantity_sold - tt.quantity_held)
+		ORDER BY tt.id
+		LIMIT $2
+	`, now, limit); err != nil {
+		return nil, apperror.New(apperror.CodeInternal, "list offerable ticket types failed", err)
+	}
+	out := make([]valueobject.UUID, 0, len(ids))
+	for _, s := range ids {
+		id, err := valueobject.ParseUUID(s)
+		if err != nil {
+			return nil, apperror.New(apperror.CodeInternal, "invalid ticket type id in db", err)
+		}
+		out = append(out, id)
+	}
+	return out, nil
+}
+
+func insertWaitlistEntry(ctx context.Context, db sqlx.ExtContext, e entity.WaitlistEntry) error {
+	q := `
+		INSERT INTO waitlist_entries (id, ticket_type_id, user_id, quantity, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	if _, err := db.ExecContext(ctx, q,
+		e.ID.String(), e.TicketTypeID.String(), e.UserID.String(), e.Quantity, string(e.Status), e.CreatedAt, e.UpdatedAt,
+	); err != nil {
+		if isUniqueViolation(err) {
+			return apperror.New(apperror.CodeConflict, "already on the waitlist for this ticket type", err)
+		}
+		return apperror.New(apperror.CodeInternal, "insert waitlist entry failed", err)
+	}
+	return nil
+}
+
+func getWaitlistEntry(ctx context.Context, db sqlx.QueryerContext, q string, args ...any) (entity.WaitlistEntry, error) {
+	var row dto.WaitlistEntryRow
+	if err := sqlx.GetContext(ctx, db, &row, q, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.WaitlistEntry{}, apperror.New(apperror.CodeNotFound, "waitlist entry not found", err)
+		}
+		return entity.WaitlistEntry{}, apperror.New(apperror.CodeInternal, "get waitlist entry failed", err)
+	}
+	return mapWaitlistEntryRow(row)
+}
+
+func listWaitlistEntries(ctx context.Context, db sqlx.QueryerContext, q string, args ...any) ([]entity.WaitlistEntry, error) {
+	var rows []dto.WaitlistEntryRow
+	if err := sqlx.SelectContext(ctx, db, &rows, q, args...); err != nil {
+		return nil, apperror.New(apperror.CodeInternal, "list waitlist entries failed", err)
+	}
+	out := make([]entity.WaitlistEntry, 0, len(rows))
+	for _, row := range rows {
+		e, err := mapWaitlistEntryRow(row)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func mapWaitlistEntryRow(row dto.WaitlistEntryRow) (entity.WaitlistEntry, error) {
+	id, err := valueobject.ParseUUID(row.ID)
+	if err != nil {
+		return entity.WaitlistEntry{}, apperror.New(apperror.CodeInternal, "invalid waitlist entry id in db", err)
+	}
+	typeID, err := valueobject.ParseUUID(row.TicketTypeID)
+	if err != nil {
+		return entity.WaitlistEntry{}, apperror.New(apperror.CodeInternal, "invalid waitlist ticket_type_id in db", err)
+	}
+	userID, err := valueobject.ParseUUID(row.UserID)
+	if err != nil {
+		return entity.WaitlistEntry{}, apperror.New(apperror.CodeInternal, "invalid waitlist user_id in db", err)
+	}
+	holdID, err := parseNullUUID(row.HoldID)
+	if err != nil {
+		return entity.WaitlistEntry{}, apperror.New(apperror.CodeInternal, "invalid waitlist hold_id in db", err)
+	}
+	st := valueobject.WaitlistStatus(row.Status)
+	if err := st.Validate(); err != nil {
+		return entity.WaitlistEntry{}, apperror.New(apperror.CodeInternal, "invalid waitlist status in db", err)
+	}
+	e := entity.WaitlistEntry{
+		ID:           id,
+		TicketTypeID: typeID,
+		UserID:       userID,
+		Quantity:     row.Quantity,
+		Status:       st,
+		HoldID:       holdID,
+		Position:     row.Position,
+	}
+	if row.OfferedAt.Valid {
+		t := row.OfferedAt.Time
+		e.OfferedAt = &t
+	}
+	if row.OfferExpiresAt.Valid {
+		t := row.OfferExpiresAt.Time
+		e.OfferExpiresAt = &t
+	}
+	if row.CreatedAt.Valid {
+		e.CreatedAt = row.CreatedAt.Time
+	}
+	if row.UpdatedAt.Valid {
+		e.UpdatedAt = row.UpdatedAt.Time
+	}
+	return e, nil
+}
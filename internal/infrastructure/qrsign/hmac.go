@@ -0,0 +1,97 @@
+package qrsign
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+
+	"time2meet/internal/application/port/qrtoken"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+)
+
+// tokenVersion prefixes every token so the format can evolve without ambiguity.
+const tokenVersion = "T1"
+
+const payloadLen = 16 + 16 + 8
+
+var b64 = base64.RawURLEncoding
+
+// HMACSigner produces compact tokens "T1.<kid>.<payload>.<sig>" where payload is
+// ticket id, event id and issue time (unix seconds) and sig is HMAC-SHA256 over
+// everything before it. Tokens signed with any configured key verify, so keys can be
+// rotated by adding a new key, switching the active id, and removing the old key later.
+type HMACSigner struct {
+	keys   map[string][]byte
+	active string
+}
+
+// NewHMACSigner expects keys already validated by config (active id present, secrets >= 32 bytes).
+func NewHMACSigner(keys map[string]string, activeKeyID string) *HMACSigner {
+	s := &HMACSigner{keys: make(map[string][]byte, len(keys)), active: activeKeyID}
+	for id, secret := range keys {
+		s.keys[id] = []byte(secret)
+	}
+	return s
+}
+
+var _ qrtoken.Signer = (*HMACSigner)(nil)
+
+func (s *HMACSigner) Sign(p qrtoken.Payload) (string, error) {
+	if p.TicketID == valueobject.Nil || p.EventID == valueobject.Nil {
+		return "", apperror.New(apperror.CodeInternal, "qr payload requires ticket and event ids", nil)
+	}
+	buf := make([]byte, payloadLen)
+	copy(buf[0:16], p.TicketID[:])
+	copy(buf[16:32], p.EventID[:])
+	binary.BigEndian.PutUint64(buf[32:40], uint64(p.IssuedAt.Unix()))
+
+	key, ok := s.keys[s.active]
+	if !ok {
+		return "", apperror.New(apperror.CodeInternal, "active qr signing key is not configured", nil)
+	}
+	signed := tokenVersion + "." + s.active + "." + b64.EncodeToString(buf)
+	return signed + "." + b64.EncodeToString(s.mac(key, signed)), nil
+}
+
+func (s *HMACSigner) Verify(token string) (qrtoken.Payload, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 4 || parts[0] != tokenVersion {
+		return qrtoken.Payload{}, invalid(nil)
+	}
+	key, ok := s.keys[parts[1]]
+	if !ok {
+		return qrtoken.Payload{}, invalid(fmt.Errorf("unknown key id %q", parts[1]))
+	}
+	sig, err := b64.DecodeString(parts[3])
+	if err != nil {
+		return qrtoken.Payload{}, invalid(err)
+	}
+	signed := parts[0] + "." + parts[1] + "." + parts[2]
+	if !hmac.Equal(sig, s.mac(key, signed)) {
+		return qrtoken.Payload{}, invalid(fmt.Errorf("signature mismatch"))
+	}
+	buf, err := b64.DecodeString(parts[2])
+	if err != nil || len(buf) != payloadLen {
+		return qrtoken.Payload{}, invalid(err)
+	}
+	var p qrtoken.Payload
+	p.TicketID = valueobject.UUID(buf[0:16])
+	p.EventID = valueobject.UUID(buf[16:32])
+	p.IssuedAt = time.Unix(int64(binary.BigEndian.Uint64(buf[32:40])), 0).UTC()
+	return p, nil
+}
+
+func (s *HMACSigner) mac(key []byte, msg string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(msg))
+	return m.Sum(nil)
+}
+
+func invalid(cause error) error {
+	return apperror.New(apperror.CodeInvalidQRCode, "invalid qr code", cause)
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"time2meet/internal/domain/repository"
+
+	"go.uber.org/zap"
+)
+
+// sweepBatch bounds how many holds one pass expires, so a backlog is drained over several
+// ticks instead of one long pass.
+const sweepBatch = 500
+
+// HoldSweeper periodically expires checkout holds whose TTL has passed, returning their
+// tickets to the pool.
+type HoldSweeper struct {
+	holds    repository.TicketHoldRepository
+	interval time.Duration
+	log      *zap.Logger
+}
+
+func NewHoldSweeper(holds repository.TicketHoldRepository, interval time.Duration, log *zap.Logger) *HoldSweeper {
+	return &HoldSweeper{holds: holds, interval: interval, log: log}
+}
+
+// Run sweeps until ctx is cancelled.
+func (s *HoldSweeper) Run(ctx context.Context) {
+	runEvery(ctx, s.interval, s.sweep)
+}
+
+func (s *HoldSweeper) sweep(ctx context.Context) {
+	n, err := s.holds.ExpireDue(ctx, time.Now().UTC(), sweepBatch)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.log.Error("hold sweep failed", zap.Int("expired", n), zap.Error(err))
+		}
+		return
+	}
+	if n > 0 {
+		s.log.Info("expired ticket holds", zap.Int("count", n))
+	}
+}
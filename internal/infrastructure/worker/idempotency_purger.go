@@ -0,0 +1,39 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"time2meet/internal/application/port/idempotency"
+
+	"go.uber.org/zap"
+)
+
+// IdempotencyPurger deletes idempotency records once their retention has passed.
+type IdempotencyPurger struct {
+	store    idempotency.Store
+	interval time.Duration
+	log      *zap.Logger
+}
+
+func NewIdempotencyPurger(store idempotency.Store, interval time.Duration, log *zap.Logger) *IdempotencyPurger {
+	return &IdempotencyPurger{store: store, interval: interval, log: log}
+}
+
+// Run purges until ctx is cancelled.
+func (p *IdempotencyPurger) Run(ctx context.Context) {
+	runEvery(ctx, p.interval, p.purge)
+}
+
+func (p *IdempotencyPurger) purge(ctx context.Context) {
+	n, err := p.store.PurgeExpired(ctx, time.Now().UTC())
+	if err != nil {
+		if ctx.Err() == nil {
+			p.log.Error("idempotency purge failed", zap.Error(err))
+		}
+		return
+	}
+	if n > 0 {
+		p.log.Info("purged idempotency keys", zap.Int("count", n))
+	}
+}
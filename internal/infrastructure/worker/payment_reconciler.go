@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"time2meet/internal/application/usecase/ticket"
+
+	"go.uber.org/zap"
+)
+
+// PaymentReconciler periodically settles payments and refunds left pending by gateway timeouts
+// or lost webhooks. It must share the API's payment gateway: the fake provider keeps its
+// intents in process memory.
+type PaymentReconciler struct {
+	payments  *ticket.PaymentProcessor
+	olderThan time.Duration
+	interval  time.Duration
+	log       *zap.Logger
+}
+
+func NewPaymentReconciler(payments *ticket.PaymentProcessor, olderThan, interval time.Duration, log *zap.Logger) *PaymentReconciler {
+	return &PaymentReconciler{payments: payments, olderThan: olderThan, interval: interval, log: log}
+}
+
+// Run reconciles until ctx is cancelled.
+func (r *PaymentReconciler) Run(ctx context.Context) {
+	runEvery(ctx, r.interval, r.reconcile)
+}
+
+func (r *PaymentReconciler) reconcile(ctx context.Context) {
+	res, err := r.payments.Reconcile(ctx, r.olderThan, sweepBatch)
+	if err != nil && ctx.Err() == nil {
+		r.log.Error("payment reconciliation failed", zap.Int("checked", res.Checked), zap.Error(err))
+		return
+	}
+	if res.Checked > 0 || res.RefundsChecked > 0 {
+		r.log.Info("payment reconciliation",
+			zap.Int("checked", res.Checked),
+			zap.Int("paid", res.Paid),
+			zap.Int("failed", res.Failed),
+			zap.Int("still_pending", res.StillPending),
+			zap.Int("refunds_checked", res.RefundsChecked),
+			zap.Int("refunds_succeeded", res.RefundsSucceeded),
+			zap.Int("refunds_failed", res.RefundsFailed),
+			zap.Int("refunds_still_pending", res.RefundsStillPending),
+		)
+	}
+}
@@ -0,0 +1,39 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"time2meet/internal/domain/repository"
+
+	"go.uber.org/zap"
+)
+
+// TransferSweeper periodically expires ticket transfers that were not accepted in time.
+type TransferSweeper struct {
+	transfers repository.TicketTransferRepository
+	interval  time.Duration
+	log       *zap.Logger
+}
+
+func NewTransferSweeper(transfers repository.TicketTransferRepository, interval time.Duration, log *zap.Logger) *TransferSweeper {
+	return &TransferSweeper{transfers: transfers, interval: interval, log: log}
+}
+
+// Run sweeps until ctx is cancelled.
+func (s *TransferSweeper) Run(ctx context.Context) {
+	runEvery(ctx, s.interval, s.sweep)
+}
+
+func (s *TransferSweeper) sweep(ctx context.Context) {
+	n, err := s.transfers.ExpireDue(ctx, time.Now().UTC(), sweepBatch)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.log.Error("transfer sweep failed", zap.Error(err))
+		}
+		return
+	}
+	if n > 0 {
+		s.log.Info("expired ticket transfers", zap.Int("count", n))
+	}
+}
@@ -0,0 +1,38 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"time2meet/internal/application/usecase/ticket"
+
+	"go.uber.org/zap"
+)
+
+// WaitlistOfferer periodically offers tickets freed by refunds, released or expired holds and
+// capacity increases to the waitlists of their ticket types.
+type WaitlistOfferer struct {
+	waitlist *ticket.WaitlistUseCase
+	interval time.Duration
+	log      *zap.Logger
+}
+
+func NewWaitlistOfferer(waitlist *ticket.WaitlistUseCase, interval time.Duration, log *zap.Logger) *WaitlistOfferer {
+	return &WaitlistOfferer{waitlist: waitlist, interval: interval, log: log}
+}
+
+// Run offers until ctx is cancelled.
+func (o *WaitlistOfferer) Run(ctx context.Context) {
+	runEvery(ctx, o.interval, o.offer)
+}
+
+func (o *WaitlistOfferer) offer(ctx context.Context) {
+	res, err := o.waitlist.OfferFreed(ctx, sweepBatch)
+	if err != nil && ctx.Err() == nil {
+		o.log.Error("waitlist offers failed", zap.Int("settled", res.Settled), zap.Int("offered", res.Offered), zap.Error(err))
+		return
+	}
+	if res.Settled > 0 || res.Offered > 0 {
+		o.log.Info("waitlist offers", zap.Int("settled", res.Settled), zap.Int("offered", res.Offered))
+	}
+}
@@ -0,0 +1,20 @@
+package worker
+
+import (
+	"context"
+	"time"
+)
+
+// runEvery calls fn on every tick of interval until ctx is cancelled.
+func runEvery(ctx context.Context, interval time.Duration, fn func(context.Context)) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			fn(ctx)
+		}
+	}
+}
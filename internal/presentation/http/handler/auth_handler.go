@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"time2meet/internal/application/usecase/auth"
+	"time2meet/internal/presentation/http/middleware"
+	"time2meet/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthHandler struct {
+	uc *auth.UseCase
+}
+
+func NewAuthHandler(uc *auth.UseCase) *AuthHandler { return &AuthHandler{uc: uc} }
+
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type ClaimRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+type ConfirmClaimRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type TokenResponse struct {
+	TokenType        string    `json:"token_type"`
+	AccessToken      string    `json:"access_token"`
+	AccessExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+func newTokenResponse(p auth.TokenPair) TokenResponse {
+	return TokenResponse{
+		TokenType:        "Bearer",
+		AccessToken:      p.AccessToken,
+		AccessExpiresAt:  p.AccessExpiresAt,
+		RefreshToken:     p.RefreshToken,
+		RefreshExpiresAt: p.RefreshExpiresAt,
+	}
+}
+
+// @Summary Вход по email и паролю
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body LoginRequest true "Учётные данные"
+// @Success 200 {object} TokenResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
+		return
+	}
+	ipAny, _ := c.Get(middleware.CtxIPKey)
+	ip, _ := ipAny.(string)
+
+	out, err := h.uc.Login(c.Request.Context(), auth.LoginInput{
+		Email:    req.Email,
+		Password: req.Password,
+		IP:       ip,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newTokenResponse(out))
+}
+
+// @Summary Обновить пару токенов по refresh-токену
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body RefreshRequest true "Refresh-токен"
+// @Success 200 {object} TokenResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
+		return
+	}
+	ipAny, _ := c.Get(middleware.CtxIPKey)
+	ip, _ := ipAny.(string)
+
+	out, err := h.uc.Refresh(c.Request.Context(), auth.RefreshInput{
+		RefreshToken: req.RefreshToken,
+		IP:           ip,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newTokenResponse(out))
+}
+
+// @Summary Выход (отзыв refresh-токена)
+// @Tags auth
+// @Accept json
+// @Param body body RefreshRequest true "Refresh-токен"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
+		return
+	}
+	if err := h.uc.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Запросить на email токен для аккаунта без пароля (например, созданного для пригласительных билетов)
+// @Tags auth
+// @Accept json
+// @Param body body ClaimRequest true "Email"
+// @Success 202
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/claim [post]
+func (h *AuthHandler) RequestClaim(c *gin.Context) {
+	var req ClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
+		return
+	}
+	if err := h.uc.RequestClaim(c.Request.Context(), req.Email); err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// @Summary Задать первый пароль аккаунта по токену из письма
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body ConfirmClaimRequest true "Токен и пароль"
+// @Success 200 {object} TokenResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/claim/confirm [post]
+func (h *AuthHandler) Claim(c *gin.Context) {
+	var req ConfirmClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
+		return
+	}
+	ipAny, _ := c.Get(middleware.CtxIPKey)
+	ip, _ := ipAny.(string)
+
+	out, err := h.uc.Claim(c.Request.Context(), auth.ClaimInput{
+		Token:    req.Token,
+		Password: req.Password,
+		IP:       ip,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newTokenResponse(out))
+}
@@ -5,8 +5,6 @@ import (
 
 	"time2meet/internal/application/port/batchimport"
 	"time2meet/internal/application/usecase/batch"
-	"time2meet/internal/domain/valueobject"
-	"time2meet/internal/presentation/http/middleware"
 	"time2meet/pkg/apperror"
 
 	"github.com/gin-gonic/gin"
@@ -19,19 +17,25 @@ type BatchHandler struct {
 func NewBatchHandler(uc *batch.UseCase) *BatchHandler { return &BatchHandler{uc: uc} }
 
 type importUsersRequest struct {
-	ContinueOnError bool                       `json:"continue_on_error"`
-	Items           []batchimport.ImportUsersItem `json:"items" binding:"required"`
+	ContinueOnError bool `json:"continue_on_error"`
+	// MigrationMode (admin only) accepts pre-computed password_hash values instead of passwords.
+	MigrationMode bool                          `json:"migration_mode"`
+	Items         []batchimport.ImportUsersItem `json:"items" binding:"required"`
 }
 
 // @Summary Батч-импорт пользователей
 // @Tags batch
+// @Security BearerAuth
 // @Accept json
 // @Produce json
-// @Param X-User-Id header string false "User ID (UUID) for audit"
 // @Param body body importUsersRequest true "Пакет пользователей"
+// @Param Idempotency-Key header string false "Ключ идемпотентности для безопасных повторов"
 // @Success 200 {object} batchimport.Result
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /batch/import/users [post]
 func (h *BatchHandler) ImportUsers(c *gin.Context) {
@@ -40,15 +44,11 @@ func (h *BatchHandler) ImportUsers(c *gin.Context) {
 		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
 		return
 	}
-	uidAny, _ := c.Get(middleware.CtxUserIDKey)
-	userID, _ := uidAny.(valueobject.UUID)
-	ipAny, _ := c.Get(middleware.CtxIPKey)
-	ip, _ := ipAny.(string)
-
 	out, err := h.uc.ImportUsers(c.Request.Context(), batch.ImportUsersInput{
-		UserID:          userID,
-		IP:              ip,
+		Actor:           actorFromContext(c),
+		IP:              clientIP(c),
 		ContinueOnError: req.ContinueOnError,
+		MigrationMode:   req.MigrationMode,
 		Items:           req.Items,
 	})
 	if err != nil {
@@ -65,13 +65,17 @@ type importEventsRequest struct {
 
 // @Summary Батч-импорт мероприятий
 // @Tags batch
+// @Security BearerAuth
 // @Accept json
 // @Produce json
-// @Param X-User-Id header string false "User ID (UUID) for audit"
 // @Param body body importEventsRequest true "Пакет мероприятий"
+// @Param Idempotency-Key header string false "Ключ идемпотентности для безопасных повторов"
 // @Success 200 {object} batchimport.Result
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /batch/import/events [post]
 func (h *BatchHandler) ImportEvents(c *gin.Context) {
@@ -80,14 +84,9 @@ func (h *BatchHandler) ImportEvents(c *gin.Context) {
 		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
 		return
 	}
-	uidAny, _ := c.Get(middleware.CtxUserIDKey)
-	userID, _ := uidAny.(valueobject.UUID)
-	ipAny, _ := c.Get(middleware.CtxIPKey)
-	ip, _ := ipAny.(string)
-
 	out, err := h.uc.ImportEvents(c.Request.Context(), batch.ImportEventsInput{
-		UserID:          userID,
-		IP:              ip,
+		Actor:           actorFromContext(c),
+		IP:              clientIP(c),
 		ContinueOnError: req.ContinueOnError,
 		Items:           req.Items,
 	})
@@ -105,13 +104,17 @@ type importTicketsRequest struct {
 
 // @Summary Батч-импорт билетов
 // @Tags batch
+// @Security BearerAuth
 // @Accept json
 // @Produce json
-// @Param X-User-Id header string false "User ID (UUID) for audit"
 // @Param body body importTicketsRequest true "Пакет билетов"
+// @Param Idempotency-Key header string false "Ключ идемпотентности для безопасных повторов"
 // @Success 200 {object} batchimport.Result
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /batch/import/tickets [post]
 func (h *BatchHandler) ImportTickets(c *gin.Context) {
@@ -120,14 +123,9 @@ func (h *BatchHandler) ImportTickets(c *gin.Context) {
 		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
 		return
 	}
-	uidAny, _ := c.Get(middleware.CtxUserIDKey)
-	userID, _ := uidAny.(valueobject.UUID)
-	ipAny, _ := c.Get(middleware.CtxIPKey)
-	ip, _ := ipAny.(string)
-
 	out, err := h.uc.ImportTickets(c.Request.Context(), batch.ImportTicketsInput{
-		UserID:          userID,
-		IP:              ip,
+		Actor:           actorFromContext(c),
+		IP:              clientIP(c),
 		ContinueOnError: req.ContinueOnError,
 		Items:           req.Items,
 	})
@@ -0,0 +1,422 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"time2meet/internal/application/usecase/checkin"
+	"time2meet/internal/application/usecase/ticket"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CheckinHandler struct {
+	offline  *ticket.OfflineCheckinUseCase
+	checkins *checkin.UseCase
+}
+
+func NewCheckinHandler(offline *ticket.OfflineCheckinUseCase, checkins *checkin.UseCase) *CheckinHandler {
+	return &CheckinHandler{offline: offline, checkins: checkins}
+}
+
+type ManifestEntryResponse struct {
+	TicketID   string `json:"ticket_id"`
+	CodeSHA256 string `json:"code_sha256"`
+	Status     string `json:"status"`
+	EntryCount int    `json:"entry_count"`
+	MaxEntries int    `json:"max_entries"`
+}
+
+type ManifestResponse struct {
+	EventID     string                  `json:"event_id"`
+	DeviceID    string                  `json:"device_id"`
+	GeneratedAt time.Time               `json:"generated_at"`
+	KeyID       string                  `json:"key_id"`
+	Signature   string                  `json:"signature"`
+	Tickets     []ManifestEntryResponse `json:"tickets"`
+}
+
+// @Summary Манифест билетов мероприятия для офлайн-сканеров
+// @Tags checkin
+// @Security BearerAuth
+// @Security DeviceToken
+// @Produce json
+// @Param id path string true "Event ID (UUID)"
+// @Param device_id query string false "Device ID (UUID), обязателен для организаторов"
+// @Success 200 {object} ManifestResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/checkin/manifest [get]
+func (h *CheckinHandler) Manifest(c *gin.Context) {
+	eventID, err := valueobject.ParseUUID(c.Param("id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid event id", err))
+		return
+	}
+	var deviceID valueobject.UUID
+	if v := c.Query("device_id"); v != "" {
+		id, err := valueobject.ParseUUID(v)
+		if err != nil {
+			RespondError(c, apperror.New(apperror.CodeValidation, "invalid device_id", err))
+			return
+		}
+		deviceID = id
+	}
+	m, err := h.offline.Manifest(c.Request.Context(), actorFromContext(c), eventID, deviceID)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ManifestResponse{
+		EventID:     m.EventID.String(),
+		DeviceID:    m.DeviceID.String(),
+		GeneratedAt: m.GeneratedAt,
+		KeyID:       m.KeyID,
+		Signature:   m.Signature,
+		Tickets: mapList(m.Entries, func(e ticket.ManifestEntry) ManifestEntryResponse {
+			return ManifestEntryResponse{
+				TicketID:   e.TicketID.String(),
+				CodeSHA256: e.CodeHash,
+				Status:     string(e.Status),
+				EntryCount: e.EntryCount,
+				MaxEntries: e.MaxEntries,
+			}
+		}),
+	})
+}
+
+type OfflineScanRequest struct {
+	Payload   string    `json:"payload" binding:"required"`
+	ScannedAt time.Time `json:"scanned_at" binding:"required"`
+	Gate      string    `json:"gate"`
+}
+
+type SyncCheckinsRequest struct {
+	Scans []OfflineScanRequest `json:"scans" binding:"required,dive"`
+}
+
+type SyncItemResponse struct {
+	Index          int           `json:"index"`
+	TicketID       string        `json:"ticket_id,omitempty"`
+	Gate           string        `json:"gate,omitempty"`
+	Outcome        string        `json:"outcome"`
+	Code           apperror.Code `json:"code,omitempty"`
+	Message        string        `json:"message,omitempty"`
+	UsedAt         *time.Time    `json:"used_at,omitempty"`
+	UsedBy         string        `json:"used_by,omitempty"`
+	UsedByDeviceID string        `json:"used_by_device_id,omitempty"`
+	FirstGate      string        `json:"first_gate,omitempty"`
+}
+
+type SyncCheckinsResponse struct {
+	Total     int                `json:"total"`
+	Accepted  int                `json:"accepted"`
+	Conflicts int                `json:"conflicts"`
+	Rejected  int                `json:"rejected"`
+	Items     []SyncItemResponse `json:"items"`
+}
+
+// @Summary Загрузка офлайн-сканирований и сверка
+// @Tags checkin
+// @Security BearerAuth
+// @Security DeviceToken
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID (UUID)"
+// @Param body body SyncCheckinsRequest true "Сканирования"
+// @Success 200 {object} SyncCheckinsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/checkin/sync [post]
+func (h *CheckinHandler) Sync(c *gin.Context) {
+	eventID, err := valueobject.ParseUUID(c.Param("id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid event id", err))
+		return
+	}
+	var req SyncCheckinsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
+		return
+	}
+	scans := make([]ticket.OfflineScan, 0, len(req.Scans))
+	for _, s := range req.Scans {
+		scans = append(scans, ticket.OfflineScan{Payload: s.Payload, ScannedAt: s.ScannedAt, Gate: s.Gate})
+	}
+	res, err := h.offline.Sync(c.Request.Context(), ticket.SyncInput{
+		Actor:   actorFromContext(c),
+		IP:      clientIP(c),
+		EventID: eventID,
+		Scans:   scans,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SyncCheckinsResponse{
+		Total:     res.Total,
+		Accepted:  res.Accepted,
+		Conflicts: res.Conflicts,
+		Rejected:  res.Rejected,
+		Items:     mapList(res.Items, newSyncItemResponse),
+	})
+}
+
+func newSyncItemResponse(it ticket.SyncItem) SyncItemResponse {
+	out := SyncItemResponse{
+		Index:     it.Index,
+		Gate:      it.Gate,
+		Outcome:   string(it.Outcome),
+		Code:      it.Code,
+		Message:   it.Message,
+		UsedAt:    it.UsedAt,
+		FirstGate: it.FirstGate,
+	}
+	if it.TicketID != valueobject.Nil {
+		out.TicketID = it.TicketID.String()
+	}
+	if it.UsedBy != nil {
+		out.UsedBy = it.UsedBy.String()
+	}
+	if it.UsedByDeviceID != nil {
+		out.UsedByDeviceID = it.UsedByDeviceID.String()
+	}
+	return out
+}
+
+type RegisterDeviceRequest struct {
+	Name string `json:"name" binding:"required"`
+	Gate string `json:"gate"`
+}
+
+type CheckinDeviceResponse struct {
+	ID         string     `json:"id"`
+	EventID    string     `json:"event_id"`
+	Name       string     `json:"name"`
+	Gate       string     `json:"gate"`
+	CreatedBy  *string    `json:"created_by"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func newCheckinDeviceResponse(d entity.CheckinDevice) CheckinDeviceResponse {
+	return CheckinDeviceResponse{
+		ID:         d.ID.String(),
+		EventID:    d.EventID.String(),
+		Name:       d.Name,
+		Gate:       d.Gate,
+		CreatedBy:  optionalID(d.CreatedBy),
+		LastSeenAt: d.LastSeenAt,
+		RevokedAt:  d.RevokedAt,
+		CreatedAt:  d.CreatedAt,
+	}
+}
+
+type RegisteredDeviceResponse struct {
+	Device CheckinDeviceResponse `json:"device"`
+	// Token is sent by the device in the X-Device-Token header. It cannot be retrieved again.
+	Token string `json:"token"`
+	// ManifestKeyID and ManifestPublicKey (base64url Ed25519) verify offline manifests.
+	ManifestKeyID     string `json:"manifest_key_id"`
+	ManifestPublicKey string `json:"manifest_public_key"`
+}
+
+type CheckinResponse struct {
+	ID         string    `json:"id"`
+	EventID    *string   `json:"event_id"`
+	TicketID   *string   `json:"ticket_id"`
+	DeviceID   *string   `json:"device_id"`
+	StaffID    *string   `json:"staff_id"`
+	Gate       string    `json:"gate"`
+	Result     string    `json:"result"`
+	Message    string    `json:"message"`
+	Offline    bool      `json:"offline"`
+	ScannedAt  time.Time `json:"scanned_at"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+func newCheckinResponse(ci entity.Checkin) CheckinResponse {
+	return CheckinResponse{
+		ID:         ci.ID.String(),
+		EventID:    optionalID(ci.EventID),
+		TicketID:   optionalID(ci.TicketID),
+		DeviceID:   optionalID(ci.DeviceID),
+		StaffID:    optionalID(ci.StaffID),
+		Gate:       ci.Gate,
+		Result:     string(ci.Result),
+		Message:    ci.Message,
+		Offline:    ci.Offline,
+		ScannedAt:  ci.ScannedAt,
+		RecordedAt: ci.RecordedAt,
+	}
+}
+
+// @Summary Зарегистрировать устройство-сканер мероприятия
+// @Tags checkin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID (UUID)"
+// @Param body body RegisterDeviceRequest true "Устройство"
+// @Success 201 {object} RegisteredDeviceResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/devices [post]
+func (h *CheckinHandler) RegisterDevice(c *gin.Context) {
+	eventID, err := valueobject.ParseUUID(c.Param("id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid event id", err))
+		return
+	}
+	var req RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
+		return
+	}
+	out, err := h.checkins.RegisterDevice(c.Request.Context(), actorFromContext(c), checkin.RegisterDeviceInput{
+		EventID: eventID,
+		Name:    req.Name,
+		Gate:    req.Gate,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, RegisteredDeviceResponse{
+		Device:            newCheckinDeviceResponse(out.Device),
+		Token:             out.Token,
+		ManifestKeyID:     out.ManifestKeyID,
+		ManifestPublicKey: out.ManifestPublicKey,
+	})
+}
+
+// @Summary Список устройств-сканеров мероприятия
+// @Tags checkin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Event ID (UUID)"
+// @Success 200 {array} CheckinDeviceResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/devices [get]
+func (h *CheckinHandler) ListDevices(c *gin.Context) {
+	eventID, err := valueobject.ParseUUID(c.Param("id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid event id", err))
+		return
+	}
+	devices, err := h.checkins.ListDevices(c.Request.Context(), actorFromContext(c), eventID)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, mapList(devices, newCheckinDeviceResponse))
+}
+
+// @Summary Отозвать устройство-сканер
+// @Tags checkin
+// @Security BearerAuth
+// @Param id path string true "Event ID (UUID)"
+// @Param device_id path string true "Device ID (UUID)"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/devices/{device_id} [delete]
+func (h *CheckinHandler) RevokeDevice(c *gin.Context) {
+	eventID, err := valueobject.ParseUUID(c.Param("id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid event id", err))
+		return
+	}
+	deviceID, err := valueobject.ParseUUID(c.Param("device_id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid device id", err))
+		return
+	}
+	if err := h.checkins.RevokeDevice(c.Request.Context(), actorFromContext(c), eventID, deviceID); err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary История проходов мероприятия
+// @Tags checkin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Event ID (UUID)"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {array} CheckinResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/checkins [get]
+func (h *CheckinHandler) EventHistory(c *gin.Context) {
+	eventID, err := valueobject.ParseUUID(c.Param("id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid event id", err))
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	rows, err := h.checkins.EventHistory(c.Request.Context(), actorFromContext(c), eventID, limit, offset)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, mapList(rows, newCheckinResponse))
+}
+
+// @Summary История проходов по билету
+// @Tags checkin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ticket ID (UUID)"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {array} CheckinResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tickets/{id}/checkins [get]
+func (h *CheckinHandler) TicketHistory(c *gin.Context) {
+	ticketID, err := valueobject.ParseUUID(c.Param("id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid ticket id", err))
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	rows, err := h.checkins.TicketHistory(c.Request.Context(), actorFromContext(c), ticketID, limit, offset)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, mapList(rows, newCheckinResponse))
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+
+	"time2meet/internal/application/usecase/ticket"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CompHandler struct {
+	uc *ticket.CompUseCase
+}
+
+func NewCompHandler(uc *ticket.CompUseCase) *CompHandler {
+	return &CompHandler{uc: uc}
+}
+
+type IssueCompRequest struct {
+	Emails []string `json:"emails" binding:"required"`
+	// Kind is guest (default), press or staff.
+	Kind string `json:"kind" enums:"guest,press,staff"`
+	// QuantityEach defaults to 1.
+	QuantityEach int `json:"quantity_each"`
+}
+
+// @Summary Выдать пригласительные билеты по списку email
+// @Tags ticket-types
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID (UUID)"
+// @Param type_id path string true "Ticket type ID (UUID)"
+// @Param Idempotency-Key header string false "Ключ идемпотентности для безопасных повторов"
+// @Param body body IssueCompRequest true "Получатели"
+// @Success 201 {array} CompRecipientResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/ticket-types/{type_id}/comps [post]
+func (h *CompHandler) Issue(c *gin.Context) {
+	eventID, typeID, ok := ticketTypePath(c)
+	if !ok {
+		return
+	}
+	var req IssueCompRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
+		return
+	}
+	rows, err := h.uc.Issue(c.Request.Context(), ticket.IssueCompInput{
+		Actor:        actorFromContext(c),
+		IP:           clientIP(c),
+		EventID:      eventID,
+		TicketTypeID: typeID,
+		Kind:         valueobject.CompKind(req.Kind),
+		Emails:       req.Emails,
+		QuantityEach: req.QuantityEach,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, mapList(rows, newCompRecipientResponse))
+}
+
+type CompRecipientResponse struct {
+	Email  string `json:"email"`
+	UserID string `json:"user_id"`
+	// Created is true when a placeholder account was made for the email; it cannot sign in
+	// until an admin sets a password.
+	Created bool             `json:"created"`
+	Tickets []TicketResponse `json:"tickets"`
+}
+
+func newCompRecipientResponse(r ticket.CompRecipient) CompRecipientResponse {
+	return CompRecipientResponse{
+		Email:   r.Email.String(),
+		UserID:  r.UserID.String(),
+		Created: r.Created,
+		Tickets: mapList(r.Tickets, newTicketResponse),
+	}
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"time2meet/internal/application/authz"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/internal/presentation/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func actorFromContext(c *gin.Context) authz.Actor {
+	uidAny, _ := c.Get(middleware.CtxUserIDKey)
+	userID, _ := uidAny.(valueobject.UUID)
+	roleAny, _ := c.Get(middleware.CtxRoleKey)
+	role, _ := roleAny.(entity.UserRole)
+	a := authz.Actor{UserID: userID, Role: role}
+	if devAny, ok := c.Get(middleware.CtxDeviceKey); ok {
+		if d, ok := devAny.(authz.Device); ok {
+			a.Device = &d
+		}
+	}
+	return a
+}
+
+func clientIP(c *gin.Context) string {
+	ipAny, _ := c.Get(middleware.CtxIPKey)
+	ip, _ := ipAny.(string)
+	return ip
+}
@@ -12,10 +12,14 @@ import (
 func RespondError(c *gin.Context, err error) {
 	var ae *apperror.AppError
 	if errors.As(err, &ae) {
-		c.JSON(mapCodeToStatus(ae.Code), gin.H{
+		body := gin.H{
 			"code":    ae.Code,
 			"message": ae.Message,
-		})
+		}
+		if len(ae.Details) > 0 {
+			body["details"] = ae.Details
+		}
+		c.JSON(mapCodeToStatus(ae.Code), body)
 		return
 	}
 	c.JSON(http.StatusInternalServerError, gin.H{
@@ -28,10 +32,18 @@ func mapCodeToStatus(code apperror.Code) int {
 	switch code {
 	case apperror.CodeNotFound:
 		return http.StatusNotFound
-	case apperror.CodeConflict:
+	case apperror.CodeConflict, apperror.CodeInvalidState,
+		apperror.CodeSoldOut, apperror.CodeSaleNotStarted, apperror.CodeSaleEnded,
+		apperror.CodeTicketTypeInactive, apperror.CodeEventNotOnSale, apperror.CodeAlreadyUsed,
+		apperror.CodeWrongEvent, apperror.CodeHoldExpired, apperror.CodeRefundNotAllowed,
+		apperror.CodeTransferExpired, apperror.CodePromoInvalid, apperror.CodePurchaseLimit:
 		return http.StatusConflict
-	case apperror.CodeValidation:
+	case apperror.CodeValidation, apperror.CodeInvalidQRCode:
 		return http.StatusBadRequest
+	case apperror.CodePaymentFailed:
+		return http.StatusPaymentRequired
+	case apperror.CodeIdempotencyMismatch:
+		return http.StatusUnprocessableEntity
 	case apperror.CodeUnauthorized:
 		return http.StatusUnauthorized
 	case apperror.CodeForbidden:
@@ -42,5 +54,3 @@ func mapCodeToStatus(code apperror.Code) int {
 		return http.StatusInternalServerError
 	}
 }
-
-
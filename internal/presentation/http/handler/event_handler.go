@@ -18,7 +18,7 @@ type EventHandler struct {
 func NewEventHandler(uc *event.UseCase) *EventHandler { return &EventHandler{uc: uc} }
 
 type CreateEventRequest struct {
-	OrganizerID     string `json:"organizer_id" binding:"required"`
+	OrganizerID     string `json:"organizer_id"`
 	Title           string `json:"title" binding:"required"`
 	Description     string `json:"description"`
 	Status          string `json:"status" binding:"required"`
@@ -29,11 +29,14 @@ type CreateEventRequest struct {
 
 // @Summary Создать мероприятие
 // @Tags events
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param body body CreateEventRequest true "Мероприятие"
 // @Success 201 {object} IDResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /events [post]
@@ -43,12 +46,16 @@ func (h *EventHandler) Create(c *gin.Context) {
 		RespondError(c, err)
 		return
 	}
-	orgID, err := valueobject.ParseUUID(req.OrganizerID)
-	if err != nil {
-		RespondError(c, apperror.New(apperror.CodeValidation, "invalid organizer_id", err))
-		return
+	var orgID valueobject.UUID
+	if req.OrganizerID != "" {
+		var err error
+		orgID, err = valueobject.ParseUUID(req.OrganizerID)
+		if err != nil {
+			RespondError(c, apperror.New(apperror.CodeValidation, "invalid organizer_id", err))
+			return
+		}
 	}
-	id, err := h.uc.Create(c.Request.Context(), event.CreateEventInput{
+	id, err := h.uc.Create(c.Request.Context(), actorFromContext(c), event.CreateEventInput{
 		OrganizerID:     orgID,
 		Title:           req.Title,
 		Description:     req.Description,
@@ -66,10 +73,13 @@ func (h *EventHandler) Create(c *gin.Context) {
 
 // @Summary Получить мероприятие по id
 // @Tags events
+// @Security BearerAuth
 // @Produce json
 // @Param id path string true "Event ID (UUID)"
 // @Success 200 {object} EventSwagger
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /events/{id} [get]
@@ -84,17 +94,20 @@ func (h *EventHandler) Get(c *gin.Context) {
 		RespondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, e)
+	c.JSON(http.StatusOK, newEventResponse(e))
 }
 
 // @Summary Список мероприятий
 // @Tags events
+// @Security BearerAuth
 // @Produce json
 // @Param organizer_id query string false "Organizer ID (UUID)"
 // @Param status query string false "Status"
 // @Param limit query int false "Limit"
 // @Param offset query int false "Offset"
 // @Success 200 {array} EventSwagger
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /events [get]
 func (h *EventHandler) List(c *gin.Context) {
@@ -117,7 +130,7 @@ func (h *EventHandler) List(c *gin.Context) {
 		RespondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, events)
+	c.JSON(http.StatusOK, mapList(events, newEventResponse))
 }
 
 type UpdateEventRequest struct {
@@ -131,11 +144,14 @@ type UpdateEventRequest struct {
 
 // @Summary Обновить мероприятие
 // @Tags events
+// @Security BearerAuth
 // @Accept json
 // @Param id path string true "Event ID (UUID)"
 // @Param body body UpdateEventRequest true "Поля мероприятия"
 // @Success 204
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /events/{id} [put]
@@ -150,7 +166,7 @@ func (h *EventHandler) Update(c *gin.Context) {
 		RespondError(c, err)
 		return
 	}
-	err = h.uc.Update(c.Request.Context(), event.UpdateEventInput{
+	err = h.uc.Update(c.Request.Context(), actorFromContext(c), event.UpdateEventInput{
 		ID:              id,
 		Title:           req.Title,
 		Description:     req.Description,
@@ -168,9 +184,12 @@ func (h *EventHandler) Update(c *gin.Context) {
 
 // @Summary Удалить мероприятие
 // @Tags events
+// @Security BearerAuth
 // @Param id path string true "Event ID (UUID)"
 // @Success 204
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /events/{id} [delete]
@@ -180,7 +199,7 @@ func (h *EventHandler) Delete(c *gin.Context) {
 		RespondError(c, apperror.New(apperror.CodeValidation, "invalid id", err))
 		return
 	}
-	if err := h.uc.Delete(c.Request.Context(), id); err != nil {
+	if err := h.uc.Delete(c.Request.Context(), actorFromContext(c), id); err != nil {
 		RespondError(c, err)
 		return
 	}
@@ -189,9 +208,12 @@ func (h *EventHandler) Delete(c *gin.Context) {
 
 // @Summary Отменить мероприятие
 // @Tags events
+// @Security BearerAuth
 // @Param id path string true "Event ID (UUID)"
 // @Success 204
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /events/{id}/cancel [post]
@@ -201,7 +223,7 @@ func (h *EventHandler) Cancel(c *gin.Context) {
 		RespondError(c, apperror.New(apperror.CodeValidation, "invalid id", err))
 		return
 	}
-	if err := h.uc.Cancel(c.Request.Context(), id); err != nil {
+	if err := h.uc.Cancel(c.Request.Context(), actorFromContext(c), id); err != nil {
 		RespondError(c, err)
 		return
 	}
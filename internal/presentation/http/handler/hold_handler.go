@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"net/http"
+
+	"time2meet/internal/application/usecase/ticket"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HoldHandler struct {
+	uc *ticket.HoldUseCase
+}
+
+func NewHoldHandler(uc *ticket.HoldUseCase) *HoldHandler {
+	return &HoldHandler{uc: uc}
+}
+
+type CreateHoldRequest struct {
+	TicketTypeID string `json:"ticket_type_id" binding:"required"`
+	Quantity     int    `json:"quantity" binding:"required"`
+}
+
+type ConfirmHoldRequest struct {
+	// TotalAmount is the total the client expects to pay; it is only checked, never charged.
+	TotalAmount string `json:"total_amount"`
+	Currency    string `json:"currency"`
+	PromoCode   string `json:"promo_code"`
+}
+
+// @Summary Зарезервировать билеты на время оформления
+// @Tags holds
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body CreateHoldRequest true "Резерв"
+// @Param Idempotency-Key header string false "Ключ идемпотентности для безопасных повторов"
+// @Success 201 {object} HoldResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /holds [post]
+func (h *HoldHandler) Create(c *gin.Context) {
+	var req CreateHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
+		return
+	}
+	ttID, err := valueobject.ParseUUID(req.TicketTypeID)
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid ticket_type_id", err))
+		return
+	}
+	hold, err := h.uc.Create(c.Request.Context(), ticket.CreateHoldInput{
+		Actor:        actorFromContext(c),
+		IP:           clientIP(c),
+		TicketTypeID: ttID,
+		Quantity:     req.Quantity,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, newHoldResponse(hold))
+}
+
+// @Summary Получить резерв
+// @Tags holds
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Hold ID (UUID)"
+// @Success 200 {object} HoldResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /holds/{id} [get]
+func (h *HoldHandler) Get(c *gin.Context) {
+	id, ok := holdID(c)
+	if !ok {
+		return
+	}
+	hold, err := h.uc.Get(c.Request.Context(), actorFromContext(c), id)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newHoldResponse(hold))
+}
+
+// @Summary Отменить резерв
+// @Tags holds
+// @Security BearerAuth
+// @Param id path string true "Hold ID (UUID)"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /holds/{id} [delete]
+func (h *HoldHandler) Release(c *gin.Context) {
+	id, ok := holdID(c)
+	if !ok {
+		return
+	}
+	if err := h.uc.Release(c.Request.Context(), actorFromContext(c), clientIP(c), id); err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Подтвердить резерв и оформить заказ
+// @Tags holds
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Hold ID (UUID)"
+// @Param body body ConfirmHoldRequest false "Ожидаемая сумма и промокод"
+// @Param Idempotency-Key header string false "Ключ идемпотентности для безопасных повторов"
+// @Success 201 {object} OrderResponse
+// @Success 202 {object} OrderResponse "Оплата ещё не подтверждена"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 402 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /holds/{id}/confirm [post]
+func (h *HoldHandler) Confirm(c *gin.Context) {
+	id, ok := holdID(c)
+	if !ok {
+		return
+	}
+	var req ConfirmHoldRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
+			return
+		}
+	}
+	out, err := h.uc.Confirm(c.Request.Context(), ticket.ConfirmHoldInput{
+		Actor:         actorFromContext(c),
+		IP:            clientIP(c),
+		HoldID:        id,
+		PromoCode:     req.PromoCode,
+		ExpectedTotal: req.TotalAmount,
+		Currency:      req.Currency,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(checkoutStatus(out.Order.Status), newOrderResponse(out.Order, out.Tickets))
+}
+
+func holdID(c *gin.Context) (valueobject.UUID, bool) {
+	id, err := valueobject.ParseUUID(c.Param("id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid id", err))
+		return valueobject.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"time2meet/internal/application/usecase/ticket"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OrderHandler struct {
+	uc *ticket.OrderUseCase
+}
+
+func NewOrderHandler(uc *ticket.OrderUseCase) *OrderHandler {
+	return &OrderHandler{uc: uc}
+}
+
+type OrderItemRequest struct {
+	TicketTypeID string `json:"ticket_type_id" binding:"required"`
+	Quantity     int    `json:"quantity" binding:"required"`
+}
+
+type PlaceOrderRequest struct {
+	Items []OrderItemRequest `json:"items" binding:"required,dive"`
+	// TotalAmount is the total the client expects to pay; it is only checked, never charged.
+	TotalAmount string `json:"total_amount"`
+	Currency    string `json:"currency"`
+	PromoCode   string `json:"promo_code"`
+}
+
+// @Summary Оформить заказ на несколько билетов (транзакция)
+// @Tags orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body PlaceOrderRequest true "Корзина"
+// @Param Idempotency-Key header string false "Ключ идемпотентности для безопасных повторов"
+// @Success 201 {object} OrderResponse
+// @Success 202 {object} OrderResponse "Оплата ещё не подтверждена"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 402 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /orders [post]
+func (h *OrderHandler) Place(c *gin.Context) {
+	var req PlaceOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
+		return
+	}
+	items := make([]ticket.OrderLine, 0, len(req.Items))
+	for _, it := range req.Items {
+		id, err := valueobject.ParseUUID(it.TicketTypeID)
+		if err != nil {
+			RespondError(c, apperror.New(apperror.CodeValidation, "invalid ticket_type_id", err))
+			return
+		}
+		items = append(items, ticket.OrderLine{TicketTypeID: id, Quantity: it.Quantity})
+	}
+	out, err := h.uc.Place(c.Request.Context(), ticket.PlaceOrderInput{
+		Actor:         actorFromContext(c),
+		IP:            clientIP(c),
+		Items:         items,
+		PromoCode:     req.PromoCode,
+		ExpectedTotal: req.TotalAmount,
+		Currency:      req.Currency,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(checkoutStatus(out.Order.Status), newOrderResponse(out.Order, out.Tickets))
+}
+
+// checkoutStatus is 201 for settled orders and 202 while the payment outcome is unknown.
+func checkoutStatus(st valueobject.OrderStatus) int {
+	if st == valueobject.OrderStatusPendingPayment {
+		return http.StatusAccepted
+	}
+	return http.StatusCreated
+}
+
+// @Summary Получить заказ с билетами
+// @Tags orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Order ID (UUID)"
+// @Success 200 {object} OrderResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /orders/{id} [get]
+func (h *OrderHandler) Get(c *gin.Context) {
+	id, err := valueobject.ParseUUID(c.Param("id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid id", err))
+		return
+	}
+	out, err := h.uc.Get(c.Request.Context(), actorFromContext(c), id)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newOrderResponse(out.Order, out.Tickets))
+}
+
+// @Summary Список заказов покупателя
+// @Tags orders
+// @Security BearerAuth
+// @Produce json
+// @Param buyer_id query string false "Buyer ID (UUID), по умолчанию текущий пользователь"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {array} OrderResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /orders [get]
+func (h *OrderHandler) List(c *gin.Context) {
+	var buyerID valueobject.UUID
+	if v := c.Query("buyer_id"); v != "" {
+		id, err := valueobject.ParseUUID(v)
+		if err != nil {
+			RespondError(c, apperror.New(apperror.CodeValidation, "invalid buyer_id", err))
+			return
+		}
+		buyerID = id
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	orders, err := h.uc.ListByBuyer(c.Request.Context(), actorFromContext(c), buyerID, limit, offset)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, mapList(orders, func(o entity.Order) OrderResponse { return newOrderResponse(o, nil) }))
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+
+	"time2meet/internal/application/usecase/ticket"
+	"time2meet/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PaymentSignatureHeader carries the provider's signature of the webhook body.
+const PaymentSignatureHeader = "X-Payment-Signature"
+
+type PaymentHandler struct {
+	payments *ticket.PaymentProcessor
+}
+
+func NewPaymentHandler(payments *ticket.PaymentProcessor) *PaymentHandler {
+	return &PaymentHandler{payments: payments}
+}
+
+type WebhookResponse struct {
+	// Status is processed, duplicate (redelivery) or ignored.
+	Status string `json:"status"`
+}
+
+// @Summary Webhook платёжного провайдера
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param X-Payment-Signature header string true "Подпись тела запроса"
+// @Success 200 {object} WebhookResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /webhooks/payments [post]
+func (h *PaymentHandler) Webhook(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
+		return
+	}
+	outcome, err := h.payments.HandleWebhook(c.Request.Context(), clientIP(c), body, c.GetHeader(PaymentSignatureHeader))
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, WebhookResponse{Status: string(outcome)})
+}
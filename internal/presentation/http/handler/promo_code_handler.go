@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"time2meet/internal/application/usecase/ticket"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PromoCodeHandler struct {
+	uc *ticket.PromoCodeUseCase
+}
+
+func NewPromoCodeHandler(uc *ticket.PromoCodeUseCase) *PromoCodeHandler {
+	return &PromoCodeHandler{uc: uc}
+}
+
+type PromoCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+	// DiscountValue is a percent (1-100) or, for fixed discounts, an amount per ticket in Currency.
+	DiscountType  string `json:"discount_type" binding:"required" enums:"percent,fixed"`
+	DiscountValue string `json:"discount_value" binding:"required"`
+	Currency      string `json:"currency"`
+	// TicketTypeIDs limits the code to some of the event's ticket types; empty means all.
+	TicketTypeIDs []string `json:"ticket_type_ids"`
+	// MaxUses and MaxUsesPerUser count orders; omit them for no limit.
+	MaxUses        *int       `json:"max_uses"`
+	MaxUsesPerUser *int       `json:"max_uses_per_user"`
+	ValidFrom      *time.Time `json:"valid_from"`
+	ValidUntil     *time.Time `json:"valid_until"`
+	IsActive       *bool      `json:"is_active"`
+}
+
+func (r PromoCodeRequest) input() (ticket.PromoCodeInput, error) {
+	active := true
+	if r.IsActive != nil {
+		active = *r.IsActive
+	}
+	ids := make([]valueobject.UUID, 0, len(r.TicketTypeIDs))
+	for _, s := range r.TicketTypeIDs {
+		id, err := valueobject.ParseUUID(s)
+		if err != nil {
+			return ticket.PromoCodeInput{}, apperror.New(apperror.CodeValidation, "invalid ticket_type_ids", err)
+		}
+		ids = append(ids, id)
+	}
+	return ticket.PromoCodeInput{
+		Code:           r.Code,
+		DiscountType:   r.DiscountType,
+		DiscountValue:  r.DiscountValue,
+		Currency:       r.Currency,
+		TicketTypeIDs:  ids,
+		MaxUses:        r.MaxUses,
+		MaxUsesPerUser: r.MaxUsesPerUser,
+		ValidFrom:      r.ValidFrom,
+		ValidUntil:     r.ValidUntil,
+		IsActive:       active,
+	}, nil
+}
+
+// @Summary Создать промокод мероприятия
+// @Tags promo-codes
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID (UUID)"
+// @Param body body PromoCodeRequest true "Промокод"
+// @Success 201 {object} PromoCodeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/promo-codes [post]
+func (h *PromoCodeHandler) Create(c *gin.Context) {
+	eventID, err := valueobject.ParseUUID(c.Param("id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid event id", err))
+		return
+	}
+	var req PromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
+		return
+	}
+	in, err := req.input()
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	p, err := h.uc.Create(c.Request.Context(), actorFromContext(c), ticket.CreatePromoCodeInput{
+		EventID:        eventID,
+		PromoCodeInput: in,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, newPromoCodeResponse(p))
+}
+
+// @Summary Список промокодов мероприятия
+// @Tags promo-codes
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Event ID (UUID)"
+// @Success 200 {array} PromoCodeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/promo-codes [get]
+func (h *PromoCodeHandler) List(c *gin.Context) {
+	eventID, err := valueobject.ParseUUID(c.Param("id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid event id", err))
+		return
+	}
+	rows, err := h.uc.List(c.Request.Context(), actorFromContext(c), eventID)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, mapList(rows, newPromoCodeResponse))
+}
+
+// @Summary Получить промокод
+// @Tags promo-codes
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Event ID (UUID)"
+// @Param code_id path string true "Promo code ID (UUID)"
+// @Success 200 {object} PromoCodeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/promo-codes/{code_id} [get]
+func (h *PromoCodeHandler) Get(c *gin.Context) {
+	eventID, codeID, ok := promoCodePath(c)
+	if !ok {
+		return
+	}
+	p, err := h.uc.Get(c.Request.Context(), actorFromContext(c), eventID, codeID)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newPromoCodeResponse(p))
+}
+
+// @Summary Обновить промокод
+// @Tags promo-codes
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID (UUID)"
+// @Param code_id path string true "Promo code ID (UUID)"
+// @Param body body PromoCodeRequest true "Промокод"
+// @Success 200 {object} PromoCodeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/promo-codes/{code_id} [put]
+func (h *PromoCodeHandler) Update(c *gin.Context) {
+	eventID, codeID, ok := promoCodePath(c)
+	if !ok {
+		return
+	}
+	var req PromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
+		return
+	}
+	in, err := req.input()
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	p, err := h.uc.Update(c.Request.Context(), actorFromContext(c), ticket.UpdatePromoCodeInput{
+		EventID:        eventID,
+		PromoCodeID:    codeID,
+		PromoCodeInput: in,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newPromoCodeResponse(p))
+}
+
+// @Summary Удалить промокод
+// @Tags promo-codes
+// @Security BearerAuth
+// @Param id path string true "Event ID (UUID)"
+// @Param code_id path string true "Promo code ID (UUID)"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/promo-codes/{code_id} [delete]
+func (h *PromoCodeHandler) Delete(c *gin.Context) {
+	eventID, codeID, ok := promoCodePath(c)
+	if !ok {
+		return
+	}
+	if err := h.uc.Delete(c.Request.Context(), actorFromContext(c), eventID, codeID); err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func promoCodePath(c *gin.Context) (eventID, codeID valueobject.UUID, ok bool) {
+	eventID, err := valueobject.ParseUUID(c.Param("id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid event id", err))
+		return valueobject.Nil, valueobject.Nil, false
+	}
+	codeID, err = valueobject.ParseUUID(c.Param("code_id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid promo code id", err))
+		return valueobject.Nil, valueobject.Nil, false
+	}
+	return eventID, codeID, true
+}
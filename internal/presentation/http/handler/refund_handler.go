@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"net/http"
+
+	"time2meet/internal/application/usecase/ticket"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RefundHandler struct {
+	uc *ticket.RefundUseCase
+}
+
+func NewRefundHandler(uc *ticket.RefundUseCase) *RefundHandler {
+	return &RefundHandler{uc: uc}
+}
+
+type RefundRequest struct {
+	// Amount is a decimal string; omitted means the most the refund policy allows.
+	Amount string `json:"amount"`
+	Reason string `json:"reason"`
+}
+
+// @Summary Вернуть билет
+// @Tags tickets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Ticket ID (UUID)"
+// @Param body body RefundRequest false "Сумма и причина возврата"
+// @Param Idempotency-Key header string false "Ключ идемпотентности для безопасных повторов"
+// @Success 201 {object} RefundResponse
+// @Success 202 {object} RefundResponse "Возврат ещё не подтверждён провайдером"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 402 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tickets/{id}/refund [post]
+func (h *RefundHandler) Refund(c *gin.Context) {
+	id, err := valueobject.ParseUUID(c.Param("id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid id", err))
+		return
+	}
+	var req RefundRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
+			return
+		}
+	}
+	r, err := h.uc.Refund(c.Request.Context(), ticket.RefundInput{
+		Actor:    actorFromContext(c),
+		IP:       clientIP(c),
+		TicketID: id,
+		Amount:   req.Amount,
+		Reason:   req.Reason,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	status := http.StatusCreated
+	if r.Status == valueobject.RefundStatusPending {
+		status = http.StatusAccepted
+	}
+	c.JSON(status, newRefundResponse(r))
+}
+
+// @Summary Возвраты по билету
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ticket ID (UUID)"
+// @Success 200 {array} RefundResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tickets/{id}/refunds [get]
+func (h *RefundHandler) List(c *gin.Context) {
+	id, err := valueobject.ParseUUID(c.Param("id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid id", err))
+		return
+	}
+	refunds, err := h.uc.ListByTicket(c.Request.Context(), actorFromContext(c), id)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, mapList(refunds, newRefundResponse))
+}
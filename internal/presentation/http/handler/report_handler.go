@@ -20,44 +20,38 @@ func NewReportHandler(uc *report.UseCase) *ReportHandler { return &ReportHandler
 
 // @Summary Отчёт по продажам
 // @Tags reports
+// @Security BearerAuth
 // @Produce json
 // @Param start query string true "Start date (YYYY-MM-DD)"
 // @Param end query string true "End date (YYYY-MM-DD)"
 // @Success 200 {array} SalesReportRowSwagger
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /reports/sales [get]
 func (h *ReportHandler) Sales(c *gin.Context) {
-	startStr := c.Query("start")
-	endStr := c.Query("end")
-	if startStr == "" || endStr == "" {
-		RespondError(c, apperror.New(apperror.CodeValidation, "start and end are required (YYYY-MM-DD)", nil))
-		return
-	}
-	start, err := time.Parse("2006-01-02", startStr)
-	if err != nil {
-		RespondError(c, apperror.New(apperror.CodeValidation, "invalid start date (YYYY-MM-DD)", err))
+	start, end, ok := dateRange(c)
+	if !ok {
 		return
 	}
-	end, err := time.Parse("2006-01-02", endStr)
-	if err != nil {
-		RespondError(c, apperror.New(apperror.CodeValidation, "invalid end date (YYYY-MM-DD)", err))
-		return
-	}
-	rows, err := h.uc.Sales(c.Request.Context(), start, end)
+	rows, err := h.uc.Sales(c.Request.Context(), actorFromContext(c), start, end)
 	if err != nil {
 		RespondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, rows)
+	c.JSON(http.StatusOK, mapList(rows, newSalesReportRowResponse))
 }
 
 // @Summary Статистика посещаемости по мероприятию
 // @Tags reports
+// @Security BearerAuth
 // @Produce json
 // @Param event_id query string true "Event ID (UUID)"
 // @Success 200 {array} AttendanceRowSwagger
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /reports/attendance [get]
 func (h *ReportHandler) Attendance(c *gin.Context) {
@@ -67,29 +61,104 @@ func (h *ReportHandler) Attendance(c *gin.Context) {
 		RespondError(c, apperror.New(apperror.CodeValidation, "invalid event_id", err))
 		return
 	}
-	rows, err := h.uc.Attendance(c.Request.Context(), eventID)
+	rows, err := h.uc.Attendance(c.Request.Context(), actorFromContext(c), eventID)
 	if err != nil {
 		RespondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, rows)
+	c.JSON(http.StatusOK, mapList(rows, newAttendanceRowResponse))
 }
 
-// @Summary Популярные мероприятия
+// @Summary Популярные мероприятия (организатор видит только свои)
 // @Tags analytics
+// @Security BearerAuth
 // @Produce json
 // @Param limit query int false "Limit"
 // @Param days query int false "Days"
 // @Success 200 {array} PopularEventRowSwagger
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /analytics/popular-events [get]
 func (h *ReportHandler) Popular(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
-	rows, err := h.uc.Popular(c.Request.Context(), limit, days)
+	rows, err := h.uc.Popular(c.Request.Context(), actorFromContext(c), limit, days)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, mapList(rows, newPopularEventRowResponse))
+}
+
+// @Summary Покупки, отмеченные проверками частоты заказов
+// @Tags reports
+// @Security BearerAuth
+// @Produce json
+// @Param start query string true "Start date (YYYY-MM-DD)"
+// @Param end query string true "End date (YYYY-MM-DD)"
+// @Success 200 {array} FlaggedPurchaseRowResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /reports/flagged-purchases [get]
+func (h *ReportHandler) FlaggedPurchases(c *gin.Context) {
+	start, end, ok := dateRange(c)
+	if !ok {
+		return
+	}
+	rows, err := h.uc.FlaggedPurchases(c.Request.Context(), actorFromContext(c), start, end)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, mapList(rows, newFlaggedPurchaseRowResponse))
+}
+
+// @Summary Листы ожидания мероприятия и судьба предложений
+// @Tags reports
+// @Security BearerAuth
+// @Produce json
+// @Param event_id query string true "Event ID (UUID)"
+// @Success 200 {array} WaitlistReportRowResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /reports/waitlist [get]
+func (h *ReportHandler) Waitlist(c *gin.Context) {
+	eventID, err := valueobject.ParseUUID(c.Query("event_id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid event_id", err))
+		return
+	}
+	rows, err := h.uc.Waitlist(c.Request.Context(), actorFromContext(c), eventID)
 	if err != nil {
 		RespondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, rows)
+	c.JSON(http.StatusOK, mapList(rows, newWaitlistReportRowResponse))
+}
+
+// dateRange reads the required start and end query dates of a report.
+func dateRange(c *gin.Context) (start, end time.Time, ok bool) {
+	startStr := c.Query("start")
+	endStr := c.Query("end")
+	if startStr == "" || endStr == "" {
+		RespondError(c, apperror.New(apperror.CodeValidation, "start and end are required (YYYY-MM-DD)", nil))
+		return time.Time{}, time.Time{}, false
+	}
+	start, err := time.Parse("2006-01-02", startStr)
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid start date (YYYY-MM-DD)", err))
+		return time.Time{}, time.Time{}, false
+	}
+	end, err = time.Parse("2006-01-02", endStr)
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid end date (YYYY-MM-DD)", err))
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
 }
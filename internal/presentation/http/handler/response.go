@@ -0,0 +1,603 @@
+package handler
+
+import (
+	"time"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/repository"
+	"time2meet/internal/domain/valueobject"
+)
+
+// Response DTOs define the public JSON contract. Domain entities are never serialized directly.
+
+type UserResponse struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	FullName  string    `json:"full_name"`
+	Phone     string    `json:"phone"`
+	Role      string    `json:"role"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newUserResponse(u entity.User) UserResponse {
+	return UserResponse{
+		ID:        u.ID.String(),
+		Email:     u.Email.String(),
+		FullName:  u.FullName,
+		Phone:     u.Phone,
+		Role:      string(u.Role),
+		IsActive:  u.IsActive,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}
+}
+
+type EventResponse struct {
+	ID              string    `json:"id"`
+	OrganizerID     string    `json:"organizer_id"`
+	Title           string    `json:"title"`
+	Description     string    `json:"description"`
+	Status          string    `json:"status"`
+	IsPublic        bool      `json:"is_public"`
+	MaxParticipants *int      `json:"max_participants"`
+	CoverImage      string    `json:"cover_image"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func newEventResponse(e entity.Event) EventResponse {
+	return EventResponse{
+		ID:              e.ID.String(),
+		OrganizerID:     e.OrganizerID.String(),
+		Title:           e.Title,
+		Description:     e.Description,
+		Status:          string(e.Status),
+		IsPublic:        e.IsPublic,
+		MaxParticipants: e.MaxParticipants,
+		CoverImage:      e.CoverImage,
+		CreatedAt:       e.CreatedAt,
+		UpdatedAt:       e.UpdatedAt,
+	}
+}
+
+type VenueResponse struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Address      string    `json:"address"`
+	City         string    `json:"city"`
+	Country      string    `json:"country"`
+	Capacity     int       `json:"capacity"`
+	ContactPhone string    `json:"contact_phone"`
+	ContactEmail string    `json:"contact_email"`
+	Website      string    `json:"website"`
+	IsActive     bool      `json:"is_active"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func newVenueResponse(v entity.Venue) VenueResponse {
+	return VenueResponse{
+		ID:           v.ID.String(),
+		Name:         v.Name,
+		Address:      v.Address,
+		City:         v.City,
+		Country:      v.Country,
+		Capacity:     v.Capacity,
+		ContactPhone: v.ContactPhone,
+		ContactEmail: v.ContactEmail,
+		Website:      v.Website,
+		IsActive:     v.IsActive,
+		CreatedAt:    v.CreatedAt,
+		UpdatedAt:    v.UpdatedAt,
+	}
+}
+
+type RoomResponse struct {
+	ID          string         `json:"id"`
+	VenueID     string         `json:"venue_id"`
+	Name        string         `json:"name"`
+	Capacity    int            `json:"capacity"`
+	Floor       *int           `json:"floor"`
+	Equipment   map[string]any `json:"equipment"`
+	HourlyRate  string         `json:"hourly_rate"`
+	IsAvailable bool           `json:"is_available"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+func newRoomResponse(r entity.Room) RoomResponse {
+	return RoomResponse{
+		ID:          r.ID.String(),
+		VenueID:     r.VenueID.String(),
+		Name:        r.Name,
+		Capacity:    r.Capacity,
+		Floor:       r.Floor,
+		Equipment:   r.Equipment,
+		HourlyRate:  r.HourlyRate,
+		IsAvailable: r.IsAvailable,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+type TicketTypeResponse struct {
+	ID         string              `json:"id"`
+	EventID    string              `json:"event_id"`
+	Name       string              `json:"name"`
+	Price      string              `json:"price"`
+	PriceTiers []PriceTierResponse `json:"price_tiers"`
+	// CurrentTier prices the next ticket sold; NextTier is the price after it runs out, or
+	// null when the base price already applies.
+	CurrentTier        PriceTierResponse  `json:"current_tier"`
+	NextTier           *PriceTierResponse `json:"next_tier"`
+	Currency           string             `json:"currency"`
+	QuantityTotal      int                `json:"quantity_total"`
+	QuantitySold       int                `json:"quantity_sold"`
+	QuantityHeld       int                `json:"quantity_held"`
+	SaleStart          *time.Time         `json:"sale_start"`
+	SaleEnd            *time.Time         `json:"sale_end"`
+	Description        string             `json:"description"`
+	IsActive           bool               `json:"is_active"`
+	ReentryLimit       int                `json:"reentry_limit"`
+	MaxPerOrder        *int               `json:"max_per_order"`
+	MaxPerUser         *int               `json:"max_per_user"`
+	RefundPolicy       string             `json:"refund_policy"`
+	RefundDeadlineDays int                `json:"refund_deadline_days"`
+	RefundPercent      int                `json:"refund_percent"`
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+}
+
+type PriceTierResponse struct {
+	Price        string     `json:"price"`
+	UpToQuantity *int       `json:"up_to_quantity"`
+	EndsAt       *time.Time `json:"ends_at"`
+}
+
+func newPriceTierResponse(t entity.PriceTier) PriceTierResponse {
+	return PriceTierResponse{Price: formatMoney(t.Price), UpToQuantity: t.UpToQuantity, EndsAt: t.EndsAt}
+}
+
+func newTicketTypeResponse(tt entity.TicketType) TicketTypeResponse {
+	current, next := tt.PriceTierAt(time.Now())
+	var nextTier *PriceTierResponse
+	if next != nil {
+		r := newPriceTierResponse(*next)
+		nextTier = &r
+	}
+	return TicketTypeResponse{
+		ID:                 tt.ID.String(),
+		EventID:            tt.EventID.String(),
+		Name:               tt.Name,
+		Price:              formatMoney(tt.Price),
+		PriceTiers:         mapList(tt.PriceTiers, newPriceTierResponse),
+		CurrentTier:        newPriceTierResponse(current),
+		NextTier:           nextTier,
+		Currency:           tt.Currency.String(),
+		QuantityTotal:      tt.QuantityTotal,
+		QuantitySold:       tt.QuantitySold,
+		QuantityHeld:       tt.QuantityHeld,
+		SaleStart:          tt.SaleStart,
+		SaleEnd:            tt.SaleEnd,
+		Description:        tt.Description,
+		IsActive:           tt.IsActive,
+		ReentryLimit:       tt.ReentryLimit,
+		MaxPerOrder:        tt.MaxPerOrder,
+		MaxPerUser:         tt.MaxPerUser,
+		RefundPolicy:       string(tt.RefundPolicy),
+		RefundDeadlineDays: tt.RefundDeadlineDays,
+		RefundPercent:      tt.RefundPercent,
+		CreatedAt:          tt.CreatedAt,
+		UpdatedAt:          tt.UpdatedAt,
+	}
+}
+
+type TicketResponse struct {
+	ID             string     `json:"id"`
+	TicketTypeID   string     `json:"ticket_type_id"`
+	BuyerID        string     `json:"buyer_id"`
+	HolderID       string     `json:"holder_id"`
+	OrderID        *string    `json:"order_id"`
+	PurchaseDate   time.Time  `json:"purchase_date"`
+	Status         string     `json:"status"`
+	QRCode         string     `json:"qr_code"`
+	AmountPaid     string     `json:"amount_paid"`
+	DiscountAmount string     `json:"discount_amount"`
+	Comp           bool       `json:"comp"`
+	CompKind       *string    `json:"comp_kind"`
+	UsedAt         *time.Time `json:"used_at"`
+	UsedBy         *string    `json:"used_by"`
+	EntryCount     int        `json:"entry_count"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func newTicketResponse(t entity.Ticket) TicketResponse {
+	var usedBy *string
+	if t.UsedBy != nil {
+		s := t.UsedBy.String()
+		usedBy = &s
+	}
+	var compKind *string
+	if t.Comp {
+		s := string(t.CompKind)
+		compKind = &s
+	}
+	return TicketResponse{
+		ID:             t.ID.String(),
+		TicketTypeID:   t.TicketTypeID.String(),
+		BuyerID:        t.BuyerID.String(),
+		HolderID:       t.HolderID.String(),
+		OrderID:        optionalID(t.OrderID),
+		PurchaseDate:   t.PurchaseDate,
+		Status:         string(t.Status),
+		QRCode:         t.QRCode,
+		AmountPaid:     formatMoney(t.AmountPaid),
+		DiscountAmount: formatMoney(t.DiscountAmount),
+		Comp:           t.Comp,
+		CompKind:       compKind,
+		UsedAt:         t.UsedAt,
+		UsedBy:         usedBy,
+		EntryCount:     t.EntryCount,
+		CreatedAt:      t.CreatedAt,
+		UpdatedAt:      t.UpdatedAt,
+	}
+}
+
+type OrderItemResponse struct {
+	TicketTypeID   string `json:"ticket_type_id"`
+	Quantity       int    `json:"quantity"`
+	UnitPrice      string `json:"unit_price"`
+	LineTotal      string `json:"line_total"`
+	DiscountAmount string `json:"discount_amount"`
+}
+
+type OrderResponse struct {
+	ID          string  `json:"id"`
+	BuyerID     string  `json:"buyer_id"`
+	Status      string  `json:"status"`
+	Currency    string  `json:"currency"`
+	TotalAmount string  `json:"total_amount"`
+	TicketCount int     `json:"ticket_count"`
+	PromoCodeID *string `json:"promo_code_id"`
+	// DiscountAmount is what the promo code took off; TotalAmount is already net of it.
+	DiscountAmount string              `json:"discount_amount"`
+	Items          []OrderItemResponse `json:"items,omitempty"`
+	Tickets        []TicketResponse    `json:"tickets,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+// newOrderResponse renders an order; items and tickets are omitted when not loaded (lists).
+func newOrderResponse(o entity.Order, tickets []entity.Ticket) OrderResponse {
+	out := OrderResponse{
+		ID:             o.ID.String(),
+		BuyerID:        o.BuyerID.String(),
+		Status:         string(o.Status),
+		Currency:       o.Currency.String(),
+		TotalAmount:    formatMoney(o.TotalAmount),
+		TicketCount:    o.TicketCount,
+		PromoCodeID:    optionalID(o.PromoCodeID),
+		DiscountAmount: formatMoney(o.DiscountAmount),
+		CreatedAt:      o.CreatedAt,
+		UpdatedAt:      o.UpdatedAt,
+	}
+	if o.Items != nil {
+		out.Items = mapList(o.Items, func(it entity.OrderItem) OrderItemResponse {
+			return OrderItemResponse{
+				TicketTypeID:   it.TicketTypeID.String(),
+				Quantity:       it.Quantity,
+				UnitPrice:      formatMoney(it.UnitPrice),
+				LineTotal:      formatMoney(it.LineTotal),
+				DiscountAmount: formatMoney(it.DiscountAmount),
+			}
+		})
+	}
+	if tickets != nil {
+		out.Tickets = mapList(tickets, newTicketResponse)
+	}
+	return out
+}
+
+type HoldResponse struct {
+	ID           string    `json:"id"`
+	TicketTypeID string    `json:"ticket_type_id"`
+	UserID       string    `json:"user_id"`
+	Quantity     int       `json:"quantity"`
+	Status       string    `json:"status"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	OrderID      *string   `json:"order_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func newHoldResponse(h entity.TicketHold) HoldResponse {
+	return HoldResponse{
+		ID:           h.ID.String(),
+		TicketTypeID: h.TicketTypeID.String(),
+		UserID:       h.UserID.String(),
+		Quantity:     h.Quantity,
+		Status:       string(h.Status),
+		ExpiresAt:    h.ExpiresAt,
+		OrderID:      optionalID(h.OrderID),
+		CreatedAt:    h.CreatedAt,
+		UpdatedAt:    h.UpdatedAt,
+	}
+}
+
+type RefundResponse struct {
+	ID            string    `json:"id"`
+	TicketID      string    `json:"ticket_id"`
+	OrderID       *string   `json:"order_id"`
+	Amount        string    `json:"amount"`
+	Currency      string    `json:"currency"`
+	Status        string    `json:"status"`
+	Reason        string    `json:"reason"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+	RequestedBy   *string   `json:"requested_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func newRefundResponse(r entity.Refund) RefundResponse {
+	var requestedBy *valueobject.UUID
+	if r.RequestedBy != valueobject.Nil {
+		requestedBy = &r.RequestedBy
+	}
+	return RefundResponse{
+		ID:            r.ID.String(),
+		TicketID:      r.TicketID.String(),
+		OrderID:       optionalID(r.OrderID),
+		Amount:        formatMoney(r.Amount),
+		Currency:      r.Currency.String(),
+		Status:        string(r.Status),
+		Reason:        r.Reason,
+		FailureReason: r.FailureReason,
+		RequestedBy:   optionalID(requestedBy),
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.UpdatedAt,
+	}
+}
+
+type TransferResponse struct {
+	ID         string     `json:"id"`
+	TicketID   string     `json:"ticket_id"`
+	FromUserID string     `json:"from_user_id"`
+	ToEmail    string     `json:"to_email"`
+	ToUserID   *string    `json:"to_user_id"`
+	Status     string     `json:"status"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func newTransferResponse(t entity.TicketTransfer) TransferResponse {
+	return TransferResponse{
+		ID:         t.ID.String(),
+		TicketID:   t.TicketID.String(),
+		FromUserID: t.FromUserID.String(),
+		ToEmail:    t.ToEmail.String(),
+		ToUserID:   optionalID(t.ToUserID),
+		Status:     string(t.Status),
+		ExpiresAt:  t.ExpiresAt,
+		AcceptedAt: t.AcceptedAt,
+		CreatedAt:  t.CreatedAt,
+		UpdatedAt:  t.UpdatedAt,
+	}
+}
+
+type WaitlistEntryResponse struct {
+	ID           string `json:"id"`
+	TicketTypeID string `json:"ticket_type_id"`
+	UserID       string `json:"user_id"`
+	Quantity     int    `json:"quantity"`
+	Status       string `json:"status" enums:"waiting,offered,purchased,expired,cancelled"`
+	// Position is the 1-based place in the queue while the entry is waiting, otherwise 0.
+	Position int `json:"position"`
+	// HoldID is the hold to confirm (POST /holds/{id}/confirm) once tickets have been offered.
+	HoldID         *string    `json:"hold_id"`
+	OfferedAt      *time.Time `json:"offered_at"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func newWaitlistEntryResponse(e entity.WaitlistEntry) WaitlistEntryResponse {
+	return WaitlistEntryResponse{
+		ID:             e.ID.String(),
+		TicketTypeID:   e.TicketTypeID.String(),
+		UserID:         e.UserID.String(),
+		Quantity:       e.Quantity,
+		Status:         string(e.Status),
+		Position:       e.Position,
+		HoldID:         optionalID(e.HoldID),
+		OfferedAt:      e.OfferedAt,
+		OfferExpiresAt: e.OfferExpiresAt,
+		CreatedAt:      e.CreatedAt,
+		UpdatedAt:      e.UpdatedAt,
+	}
+}
+
+type PromoCodeResponse struct {
+	ID            string `json:"id"`
+	EventID       string `json:"event_id"`
+	Code          string `json:"code"`
+	DiscountType  string `json:"discount_type"`
+	DiscountValue string `json:"discount_value"`
+	// Currency is set for fixed discounts only.
+	Currency       *string    `json:"currency"`
+	TicketTypeIDs  []string   `json:"ticket_type_ids"`
+	MaxUses        *int       `json:"max_uses"`
+	MaxUsesPerUser *int       `json:"max_uses_per_user"`
+	UsedCount      int        `json:"used_count"`
+	ValidFrom      *time.Time `json:"valid_from"`
+	ValidUntil     *time.Time `json:"valid_until"`
+	IsActive       bool       `json:"is_active"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func newPromoCodeResponse(p entity.PromoCode) PromoCodeResponse {
+	var currency *string
+	if p.Currency != "" {
+		s := p.Currency.String()
+		currency = &s
+	}
+	return PromoCodeResponse{
+		ID:             p.ID.String(),
+		EventID:        p.EventID.String(),
+		Code:           p.Code,
+		DiscountType:   string(p.DiscountType),
+		DiscountValue:  p.DiscountValue.StringFixed(2),
+		Currency:       currency,
+		TicketTypeIDs:  mapList(p.TicketTypeIDs, valueobject.UUID.String),
+		MaxUses:        p.MaxUses,
+		MaxUsesPerUser: p.MaxUsesPerUser,
+		UsedCount:      p.UsedCount,
+		ValidFrom:      p.ValidFrom,
+		ValidUntil:     p.ValidUntil,
+		IsActive:       p.IsActive,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}
+}
+
+type SalesReportRowResponse struct {
+	EventID     string `json:"event_id"`
+	EventTitle  string `json:"event_title"`
+	TicketsSold int64  `json:"tickets_sold"`
+	Revenue     string `json:"revenue"`
+	// Discounts is what promo codes took off the tickets counted in Revenue.
+	Discounts    string `json:"discounts"`
+	UniqueBuyers int64  `json:"unique_buyers"`
+	// CompTickets counts complimentary tickets, which are left out of the other figures.
+	CompTickets int64 `json:"comp_tickets"`
+}
+
+func newSalesReportRowResponse(r repository.SalesReportRow) SalesReportRowResponse {
+	return SalesReportRowResponse{
+		EventID:      r.EventID.String(),
+		EventTitle:   r.EventTitle,
+		TicketsSold:  r.TicketsSold,
+		Revenue:      r.Revenue,
+		Discounts:    r.Discounts,
+		UniqueBuyers: r.UniqueBuyers,
+		CompTickets:  r.CompTickets,
+	}
+}
+
+type FlaggedPurchaseRowResponse struct {
+	FlagID     string `json:"flag_id"`
+	OrderID    string `json:"order_id"`
+	BuyerID    string `json:"buyer_id"`
+	BuyerEmail string `json:"buyer_email"`
+	ClientIP   string `json:"client_ip"`
+	// Reason is the check tripped; Observed is what it counted within its window and
+	// Threshold the limit that was exceeded.
+	Reason      string    `json:"reason" enums:"ip_order_velocity,ip_buyer_velocity"`
+	Observed    int       `json:"observed"`
+	Threshold   int       `json:"threshold"`
+	OrderStatus string    `json:"order_status"`
+	TotalAmount string    `json:"total_amount"`
+	Currency    string    `json:"currency"`
+	TicketCount int       `json:"ticket_count"`
+	FlaggedAt   time.Time `json:"flagged_at"`
+}
+
+func newFlaggedPurchaseRowResponse(r repository.FlaggedPurchaseRow) FlaggedPurchaseRowResponse {
+	return FlaggedPurchaseRowResponse{
+		FlagID:      r.FlagID.String(),
+		OrderID:     r.OrderID.String(),
+		BuyerID:     r.BuyerID.String(),
+		BuyerEmail:  r.BuyerEmail,
+		ClientIP:    r.ClientIP,
+		Reason:      string(r.Reason),
+		Observed:    r.Observed,
+		Threshold:   r.Threshold,
+		OrderStatus: string(r.OrderStatus),
+		TotalAmount: r.TotalAmount,
+		Currency:    r.Currency,
+		TicketCount: r.TicketCount,
+		FlaggedAt:   r.FlaggedAt,
+	}
+}
+
+type WaitlistReportRowResponse struct {
+	TicketTypeID   string `json:"ticket_type_id"`
+	TicketTypeName string `json:"ticket_type_name"`
+	Waiting        int64  `json:"waiting"`
+	// Offered counts open offers; Purchased and Expired count offers confirmed or left to run out.
+	Offered        int64 `json:"offered"`
+	Purchased      int64 `json:"purchased"`
+	Expired        int64 `json:"expired"`
+	Cancelled      int64 `json:"cancelled"`
+	WaitingTickets int64 `json:"waiting_tickets"`
+}
+
+func newWaitlistReportRowResponse(r repository.WaitlistReportRow) WaitlistReportRowResponse {
+	return WaitlistReportRowResponse{
+		TicketTypeID:   r.TicketTypeID.String(),
+		TicketTypeName: r.TicketTypeName,
+		Waiting:        r.Waiting,
+		Offered:        r.Offered,
+		Purchased:      r.Purchased,
+		Expired:        r.Expired,
+		Cancelled:      r.Cancelled,
+		WaitingTickets: r.WaitingTickets,
+	}
+}
+
+type AttendanceRowResponse struct {
+	TicketType     string `json:"ticket_type"`
+	Sold           int64  `json:"sold"`
+	Used           int64  `json:"used"`
+	AttendanceRate string `json:"attendance_rate"`
+}
+
+func newAttendanceRowResponse(r repository.AttendanceRow) AttendanceRowResponse {
+	return AttendanceRowResponse{
+		TicketType:     r.TicketType,
+		Sold:           r.Sold,
+		Used:           r.Used,
+		AttendanceRate: r.AttendanceRate,
+	}
+}
+
+type PopularEventRowResponse struct {
+	EventID       string `json:"event_id"`
+	Title         string `json:"title"`
+	Registrations int64  `json:"registrations"`
+	TicketsSold   int64  `json:"tickets_sold"`
+}
+
+func newPopularEventRowResponse(r repository.PopularEventRow) PopularEventRowResponse {
+	return PopularEventRowResponse{
+		EventID:       r.EventID.String(),
+		Title:         r.Title,
+		Registrations: r.Registrations,
+		TicketsSold:   r.TicketsSold,
+	}
+}
+
+func formatMoney(m valueobject.Money) string { return m.Amount.StringFixed(2) }
+
+func optionalID(id *valueobject.UUID) *string {
+	if id == nil {
+		return nil
+	}
+	s := id.String()
+	return &s
+}
+
+// mapList converts a slice of domain values to response DTOs. It never returns nil,
+// so empty collections are rendered as [] rather than null.
+func mapList[T, R any](in []T, f func(T) R) []R {
+	out := make([]R, 0, len(in))
+	for _, v := range in {
+		out = append(out, f(v))
+	}
+	return out
+}
@@ -1,8 +1,8 @@
 package handler
 
 import (
-	"time2meet/internal/domain/entity"
-	"time2meet/internal/domain/repository"
+	"time"
+
 	"time2meet/pkg/apperror"
 )
 
@@ -10,21 +10,40 @@ type IDResponse struct {
 	ID string `json:"id"`
 }
 
-type TicketIDResponse struct {
-	TicketID string `json:"ticket_id"`
+type PurchaseResponse struct {
+	OrderID string `json:"order_id"`
+	// Status is the order status: paid, or pending_payment while the payment is being confirmed.
+	Status     string `json:"status"`
+	TicketID   string `json:"ticket_id"`
+	QRCode     string `json:"qr_code"`
+	AmountPaid string `json:"amount_paid"`
+	// DiscountAmount is what the promo code took off; AmountPaid is already net of it.
+	DiscountAmount string `json:"discount_amount"`
+	Currency       string `json:"currency"`
+}
+
+type ValidateTicketResponse struct {
+	TicketID         string    `json:"ticket_id"`
+	TicketTypeID     string    `json:"ticket_type_id"`
+	EventID          string    `json:"event_id"`
+	UsedAt           time.Time `json:"used_at"`
+	EntryNumber      int       `json:"entry_number"`
+	EntriesRemaining int       `json:"entries_remaining"`
 }
 
 type ErrorResponse struct {
-	Code    apperror.Code `json:"code"`
-	Message string        `json:"message"`
+	Code    apperror.Code  `json:"code"`
+	Message string         `json:"message"`
+	Details map[string]any `json:"details,omitempty"`
 }
 
-type UserSwagger = entity.User
-type EventSwagger = entity.Event
-type VenueSwagger = entity.Venue
-type RoomSwagger = entity.Room
-type TicketSwagger = entity.Ticket
+type UserSwagger = UserResponse
+type EventSwagger = EventResponse
+type VenueSwagger = VenueResponse
+type RoomSwagger = RoomResponse
+type TicketTypeSwagger = TicketTypeResponse
+type TicketSwagger = TicketResponse
 
-type SalesReportRowSwagger = repository.SalesReportRow
-type AttendanceRowSwagger = repository.AttendanceRow
-type PopularEventRowSwagger = repository.PopularEventRow
+type SalesReportRowSwagger = SalesReportRowResponse
+type AttendanceRowSwagger = AttendanceRowResponse
+type PopularEventRowSwagger = PopularEventRowResponse
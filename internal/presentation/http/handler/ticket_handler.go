@@ -6,7 +6,6 @@ import (
 
 	"time2meet/internal/application/usecase/ticket"
 	"time2meet/internal/domain/valueobject"
-	"time2meet/internal/presentation/http/middleware"
 	"time2meet/pkg/apperror"
 
 	"github.com/gin-gonic/gin"
@@ -24,21 +23,29 @@ func NewTicketHandler(purchase *ticket.PurchaseUseCase, tickets *ticket.TicketUs
 
 type PurchaseTicketRequest struct {
 	TicketTypeID string `json:"ticket_type_id" binding:"required"`
-	QRCode       string `json:"qr_code" binding:"required"`
-	AmountPaid   string `json:"amount_paid" binding:"required"`
-	Currency     string `json:"currency"`
+	// AmountPaid is the price the client expects to pay. It is optional and only checked
+	// against the server-computed price; the charged amount is never taken from the client.
+	AmountPaid string `json:"amount_paid"`
+	Currency   string `json:"currency"`
+	PromoCode  string `json:"promo_code"`
 }
 
 // @Summary Купить билет (транзакция)
 // @Tags tickets
+// @Security BearerAuth
 // @Accept json
 // @Produce json
-// @Param X-User-Id header string false "User ID (UUID) for audit"
 // @Param body body PurchaseTicketRequest true "Покупка"
-// @Success 201 {object} TicketIDResponse
+// @Param Idempotency-Key header string false "Ключ идемпотентности для безопасных повторов"
+// @Success 201 {object} PurchaseResponse
+// @Success 202 {object} PurchaseResponse "Оплата ещё не подтверждена"
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 402 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /tickets/purchase [post]
 func (h *TicketHandler) Purchase(c *gin.Context) {
@@ -48,11 +55,6 @@ func (h *TicketHandler) Purchase(c *gin.Context) {
 		return
 	}
 
-	uidAny, _ := c.Get(middleware.CtxUserIDKey)
-	userID, _ := uidAny.(valueobject.UUID)
-	ipAny, _ := c.Get(middleware.CtxIPKey)
-	ip, _ := ipAny.(string)
-
 	ttid, err := valueobject.ParseUUID(req.TicketTypeID)
 	if err != nil {
 		RespondError(c, apperror.New(apperror.CodeValidation, "invalid ticket_type_id", err))
@@ -60,26 +62,37 @@ func (h *TicketHandler) Purchase(c *gin.Context) {
 	}
 
 	out, err := h.purchase.Purchase(c.Request.Context(), ticket.PurchaseInput{
-		UserID:       userID,
-		IP:           ip,
-		TicketTypeID: ttid,
-		QRCode:       req.QRCode,
-		AmountPaid:   req.AmountPaid,
-		Currency:     req.Currency,
+		Actor:          actorFromContext(c),
+		IP:             clientIP(c),
+		TicketTypeID:   ttid,
+		PromoCode:      req.PromoCode,
+		ExpectedAmount: req.AmountPaid,
+		Currency:       req.Currency,
 	})
 	if err != nil {
 		RespondError(c, err)
 		return
 	}
-	c.JSON(http.StatusCreated, TicketIDResponse{TicketID: out.TicketID.String()})
+	c.JSON(checkoutStatus(out.Status), PurchaseResponse{
+		OrderID:        out.OrderID.String(),
+		Status:         string(out.Status),
+		TicketID:       out.TicketID.String(),
+		QRCode:         out.QRCode,
+		AmountPaid:     formatMoney(out.AmountPaid),
+		DiscountAmount: formatMoney(out.DiscountAmount),
+		Currency:       out.Currency.String(),
+	})
 }
 
 // @Summary Получить билет по id
 // @Tags tickets
+// @Security BearerAuth
 // @Produce json
 // @Param id path string true "Ticket ID (UUID)"
 // @Success 200 {object} TicketSwagger
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /tickets/{id} [get]
@@ -89,53 +102,66 @@ func (h *TicketHandler) Get(c *gin.Context) {
 		RespondError(c, apperror.New(apperror.CodeValidation, "invalid id", err))
 		return
 	}
-	t, err := h.tickets.Get(c.Request.Context(), id)
+	t, err := h.tickets.Get(c.Request.Context(), actorFromContext(c), id)
 	if err != nil {
 		RespondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, t)
+	c.JSON(http.StatusOK, newTicketResponse(t))
 }
 
-// @Summary Список билетов по покупателю
+// @Summary Список билетов владельца
 // @Tags tickets
+// @Security BearerAuth
 // @Produce json
-// @Param buyer_id query string true "Buyer ID (UUID)"
+// @Param holder_id query string false "Holder ID (UUID), по умолчанию текущий пользователь"
+// @Param buyer_id query string false "Устаревший синоним holder_id"
 // @Param limit query int false "Limit"
 // @Param offset query int false "Offset"
 // @Success 200 {array} TicketSwagger
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /tickets [get]
-func (h *TicketHandler) ListByBuyer(c *gin.Context) {
-	buyerIDStr := c.Query("buyer_id")
-	buyerID, err := valueobject.ParseUUID(buyerIDStr)
-	if err != nil {
-		RespondError(c, apperror.New(apperror.CodeValidation, "invalid buyer_id", err))
-		return
+func (h *TicketHandler) ListByHolder(c *gin.Context) {
+	var holderID valueobject.UUID
+	if v := c.DefaultQuery("holder_id", c.Query("buyer_id")); v != "" {
+		id, err := valueobject.ParseUUID(v)
+		if err != nil {
+			RespondError(c, apperror.New(apperror.CodeValidation, "invalid holder_id", err))
+			return
+		}
+		holderID = id
 	}
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	rows, err := h.tickets.ListByBuyer(c.Request.Context(), buyerID, limit, offset)
+	rows, err := h.tickets.ListByHolder(c.Request.Context(), actorFromContext(c), holderID, limit, offset)
 	if err != nil {
 		RespondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, rows)
+	c.JSON(http.StatusOK, mapList(rows, newTicketResponse))
 }
 
 type UpdateTicketStatusRequest struct {
-	Status string `json:"status" binding:"required"`
+	Status string `json:"status" binding:"required" enums:"paid,void,used"`
+	// Reason is recorded with the change.
+	Reason string `json:"reason" binding:"required"`
 }
 
 // @Summary Обновить статус билета
 // @Tags tickets
+// @Security BearerAuth
 // @Accept json
 // @Param id path string true "Ticket ID (UUID)"
 // @Param body body UpdateTicketStatusRequest true "Статус"
 // @Success 204
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /tickets/{id}/status [patch]
 func (h *TicketHandler) UpdateStatus(c *gin.Context) {
@@ -146,10 +172,16 @@ func (h *TicketHandler) UpdateStatus(c *gin.Context) {
 	}
 	var req UpdateTicketStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		RespondError(c, err)
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
 		return
 	}
-	if err := h.tickets.UpdateStatus(c.Request.Context(), id, req.Status); err != nil {
+	if err := h.tickets.UpdateStatus(c.Request.Context(), ticket.UpdateStatusInput{
+		Actor:    actorFromContext(c),
+		IP:       clientIP(c),
+		TicketID: id,
+		Status:   req.Status,
+		Reason:   req.Reason,
+	}); err != nil {
 		RespondError(c, err)
 		return
 	}
@@ -158,9 +190,12 @@ func (h *TicketHandler) UpdateStatus(c *gin.Context) {
 
 // @Summary Удалить билет
 // @Tags tickets
+// @Security BearerAuth
 // @Param id path string true "Ticket ID (UUID)"
 // @Success 204
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /tickets/{id} [delete]
@@ -170,40 +205,67 @@ func (h *TicketHandler) Delete(c *gin.Context) {
 		RespondError(c, apperror.New(apperror.CodeValidation, "invalid id", err))
 		return
 	}
-	if err := h.tickets.Delete(c.Request.Context(), id); err != nil {
+	if err := h.tickets.Delete(c.Request.Context(), actorFromContext(c), id); err != nil {
 		RespondError(c, err)
 		return
 	}
 	c.Status(http.StatusNoContent)
 }
 
-// @Summary Валидация (использование) билета
+type ValidateTicketRequest struct {
+	Payload string `json:"payload" binding:"required"`
+	// EventID is the event being checked in; tickets of other events are rejected as wrong_event.
+	EventID string `json:"event_id"`
+	Gate    string `json:"gate"`
+}
+
+// @Summary Валидация (использование) билета по QR-коду
 // @Tags tickets
-// @Param X-User-Id header string true "User ID (UUID) for audit"
-// @Param id path string true "Ticket ID (UUID)"
-// @Success 204
+// @Security BearerAuth
+// @Security DeviceToken
+// @Accept json
+// @Produce json
+// @Param body body ValidateTicketRequest true "Содержимое QR-кода"
+// @Success 200 {object} ValidateTicketResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
-// @Router /tickets/{id}/validate [post]
+// @Router /tickets/validate [post]
 func (h *TicketHandler) Validate(c *gin.Context) {
-	id, err := valueobject.ParseUUID(c.Param("id"))
-	if err != nil {
-		RespondError(c, apperror.New(apperror.CodeValidation, "invalid id", err))
+	var req ValidateTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
 		return
 	}
-	uidAny, _ := c.Get(middleware.CtxUserIDKey)
-	userID, _ := uidAny.(valueobject.UUID)
-	ipAny, _ := c.Get(middleware.CtxIPKey)
-	ip, _ := ipAny.(string)
-
-	if err := h.validate.Validate(c.Request.Context(), ticket.ValidateInput{
-		UserID:   userID,
-		IP:       ip,
-		TicketID: id,
-	}); err != nil {
+	var eventID valueobject.UUID
+	if req.EventID != "" {
+		id, err := valueobject.ParseUUID(req.EventID)
+		if err != nil {
+			RespondError(c, apperror.New(apperror.CodeValidation, "invalid event_id", err))
+			return
+		}
+		eventID = id
+	}
+	out, err := h.validate.Validate(c.Request.Context(), ticket.ValidateInput{
+		Actor:   actorFromContext(c),
+		IP:      clientIP(c),
+		Payload: req.Payload,
+		EventID: eventID,
+		Gate:    req.Gate,
+	})
+	if err != nil {
 		RespondError(c, err)
 		return
 	}
-	c.Status(http.StatusNoContent)
+	c.JSON(http.StatusOK, ValidateTicketResponse{
+		TicketID:         out.TicketID.String(),
+		TicketTypeID:     out.TicketTypeID.String(),
+		EventID:          out.EventID.String(),
+		UsedAt:           out.UsedAt,
+		EntryNumber:      out.EntryNumber,
+		EntriesRemaining: out.EntriesRemaining,
+	})
 }
@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"time2meet/internal/application/usecase/ticket"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TicketTypeHandler struct {
+	uc *ticket.TicketTypeUseCase
+}
+
+func NewTicketTypeHandler(uc *ticket.TicketTypeUseCase) *TicketTypeHandler {
+	return &TicketTypeHandler{uc: uc}
+}
+
+type TicketTypeRequest struct {
+	Name string `json:"name" binding:"required"`
+	// Price is the base price, charged once every tier in PriceTiers has run out.
+	Price         string             `json:"price" binding:"required"`
+	PriceTiers    []PriceTierRequest `json:"price_tiers"`
+	Currency      string             `json:"currency"`
+	QuantityTotal int                `json:"quantity_total" binding:"required"`
+	SaleStart     *time.Time         `json:"sale_start"`
+	SaleEnd       *time.Time         `json:"sale_end"`
+	Description   string             `json:"description"`
+	IsActive      *bool              `json:"is_active"`
+	// ReentryLimit is how many extra admissions are allowed after the first scan.
+	ReentryLimit int `json:"reentry_limit"`
+	// MaxPerOrder and MaxPerUser limit how many tickets of the type one order and one buyer
+	// may take; omit them for no limit.
+	MaxPerOrder *int `json:"max_per_order"`
+	MaxPerUser  *int `json:"max_per_user"`
+	// RefundPolicy is full (default), partial or none; it applies until RefundDeadlineDays
+	// before the event starts.
+	RefundPolicy       string `json:"refund_policy" enums:"full,partial,none"`
+	RefundDeadlineDays int    `json:"refund_deadline_days"`
+	RefundPercent      int    `json:"refund_percent"`
+}
+
+// PriceTierRequest sells tickets at Price until UpToQuantity tickets are sold or reserved, or
+// until EndsAt, whichever comes first. Tiers apply in the order given.
+type PriceTierRequest struct {
+	Price        string     `json:"price" binding:"required"`
+	UpToQuantity *int       `json:"up_to_quantity"`
+	EndsAt       *time.Time `json:"ends_at"`
+}
+
+func (r TicketTypeRequest) input() ticket.TicketTypeInput {
+	active := true
+	if r.IsActive != nil {
+		active = *r.IsActive
+	}
+	tiers := make([]ticket.PriceTierInput, 0, len(r.PriceTiers))
+	for _, t := range r.PriceTiers {
+		tiers = append(tiers, ticket.PriceTierInput{Price: t.Price, UpToQuantity: t.UpToQuantity, EndsAt: t.EndsAt})
+	}
+	return ticket.TicketTypeInput{
+		Name:          r.Name,
+		Price:         r.Price,
+		PriceTiers:    tiers,
+		Currency:      r.Currency,
+		QuantityTotal: r.QuantityTotal,
+		SaleStart:     r.SaleStart,
+		SaleEnd:       r.SaleEnd,
+		Description:   r.Description,
+		IsActive:      active,
+		ReentryLimit:  r.ReentryLimit,
+		MaxPerOrder:   r.MaxPerOrder,
+		MaxPerUser:    r.MaxPerUser,
+
+		RefundPolicy:       r.RefundPolicy,
+		RefundDeadlineDays: r.RefundDeadlineDays,
+		RefundPercent:      r.RefundPercent,
+	}
+}
+
+// @Summary Создать тип билета мероприятия
+// @Tags ticket-types
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID (UUID)"
+// @Param body body TicketTypeRequest true "Тип билета"
+// @Success 201 {object} IDResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/ticket-types [post]
+func (h *TicketTypeHandler) Create(c *gin.Context) {
+	eventID, err := valueobject.ParseUUID(c.Param("id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid event id", err))
+		return
+	}
+	var req TicketTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
+		return
+	}
+	id, err := h.uc.Create(c.Request.Context(), actorFromContext(c), ticket.CreateTicketTypeInput{
+		EventID:         eventID,
+		TicketTypeInput: req.input(),
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, IDResponse{ID: id.String()})
+}
+
+// @Summary Список типов билетов мероприятия
+// @Tags ticket-types
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Event ID (UUID)"
+// @Success 200 {array} TicketTypeSwagger
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/ticket-types [get]
+func (h *TicketTypeHandler) List(c *gin.Context) {
+	eventID, err := valueobject.ParseUUID(c.Param("id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid event id", err))
+		return
+	}
+	types, err := h.uc.List(c.Request.Context(), eventID)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, mapList(types, newTicketTypeResponse))
+}
+
+// @Summary Получить тип билета
+// @Tags ticket-types
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Event ID (UUID)"
+// @Param type_id path string true "Ticket type ID (UUID)"
+// @Success 200 {object} TicketTypeSwagger
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/ticket-types/{type_id} [get]
+func (h *TicketTypeHandler) Get(c *gin.Context) {
+	eventID, typeID, ok := ticketTypePath(c)
+	if !ok {
+		return
+	}
+	tt, err := h.uc.Get(c.Request.Context(), eventID, typeID)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newTicketTypeResponse(tt))
+}
+
+// @Summary Обновить тип билета
+// @Tags ticket-types
+// @Security BearerAuth
+// @Accept json
+// @Param id path string true "Event ID (UUID)"
+// @Param type_id path string true "Ticket type ID (UUID)"
+// @Param body body TicketTypeRequest true "Тип билета"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/ticket-types/{type_id} [put]
+func (h *TicketTypeHandler) Update(c *gin.Context) {
+	eventID, typeID, ok := ticketTypePath(c)
+	if !ok {
+		return
+	}
+	var req TicketTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
+		return
+	}
+	if err := h.uc.Update(c.Request.Context(), actorFromContext(c), ticket.UpdateTicketTypeInput{
+		EventID:         eventID,
+		TicketTypeID:    typeID,
+		TicketTypeInput: req.input(),
+	}); err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Удалить тип билета
+// @Tags ticket-types
+// @Security BearerAuth
+// @Param id path string true "Event ID (UUID)"
+// @Param type_id path string true "Ticket type ID (UUID)"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/ticket-types/{type_id} [delete]
+func (h *TicketTypeHandler) Delete(c *gin.Context) {
+	eventID, typeID, ok := ticketTypePath(c)
+	if !ok {
+		return
+	}
+	if err := h.uc.Delete(c.Request.Context(), actorFromContext(c), eventID, typeID); err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func ticketTypePath(c *gin.Context) (eventID, typeID valueobject.UUID, ok bool) {
+	eventID, err := valueobject.ParseUUID(c.Param("id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid event id", err))
+		return valueobject.Nil, valueobject.Nil, false
+	}
+	typeID, err = valueobject.ParseUUID(c.Param("type_id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid ticket type id", err))
+		return valueobject.Nil, valueobject.Nil, false
+	}
+	return eventID, typeID, true
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"net/http"
+
+	"time2meet/internal/application/usecase/ticket"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TransferHandler struct {
+	uc *ticket.TransferUseCase
+}
+
+func NewTransferHandler(uc *ticket.TransferUseCase) *TransferHandler {
+	return &TransferHandler{uc: uc}
+}
+
+type TransferRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// @Summary Передать билет другому пользователю
+// @Tags transfers
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Ticket ID (UUID)"
+// @Param body body TransferRequest true "Email получателя"
+// @Success 201 {object} TransferResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tickets/{id}/transfers [post]
+func (h *TransferHandler) Initiate(c *gin.Context) {
+	id, ok := transferPathID(c)
+	if !ok {
+		return
+	}
+	var req TransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
+		return
+	}
+	t, err := h.uc.Initiate(c.Request.Context(), ticket.InitiateTransferInput{
+		Actor:    actorFromContext(c),
+		IP:       clientIP(c),
+		TicketID: id,
+		ToEmail:  req.Email,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, newTransferResponse(t))
+}
+
+// @Summary История передач билета
+// @Tags transfers
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ticket ID (UUID)"
+// @Success 200 {array} TransferResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tickets/{id}/transfers [get]
+func (h *TransferHandler) ListByTicket(c *gin.Context) {
+	id, ok := transferPathID(c)
+	if !ok {
+		return
+	}
+	rows, err := h.uc.ListByTicket(c.Request.Context(), actorFromContext(c), id)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, mapList(rows, newTransferResponse))
+}
+
+// @Summary Входящие передачи билетов
+// @Tags transfers
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} TransferResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /transfers [get]
+func (h *TransferHandler) ListIncoming(c *gin.Context) {
+	rows, err := h.uc.ListIncoming(c.Request.Context(), actorFromContext(c))
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, mapList(rows, newTransferResponse))
+}
+
+// @Summary Принять билет
+// @Tags transfers
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Transfer ID (UUID)"
+// @Success 200 {object} TransferResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /transfers/{id}/accept [post]
+func (h *TransferHandler) Accept(c *gin.Context) {
+	id, ok := transferPathID(c)
+	if !ok {
+		return
+	}
+	t, err := h.uc.Accept(c.Request.Context(), actorFromContext(c), clientIP(c), id)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newTransferResponse(t))
+}
+
+// @Summary Отменить или отклонить передачу билета
+// @Tags transfers
+// @Security BearerAuth
+// @Param id path string true "Transfer ID (UUID)"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /transfers/{id} [delete]
+func (h *TransferHandler) Cancel(c *gin.Context) {
+	id, ok := transferPathID(c)
+	if !ok {
+		return
+	}
+	if err := h.uc.Cancel(c.Request.Context(), actorFromContext(c), clientIP(c), id); err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func transferPathID(c *gin.Context) (valueobject.UUID, bool) {
+	id, err := valueobject.ParseUUID(c.Param("id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid id", err))
+		return valueobject.Nil, false
+	}
+	return id, true
+}
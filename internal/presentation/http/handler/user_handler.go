@@ -18,20 +18,23 @@ type UserHandler struct {
 func NewUserHandler(uc *user.UseCase) *UserHandler { return &UserHandler{uc: uc} }
 
 type CreateUserRequest struct {
-	Email        string `json:"email" binding:"required"`
-	PasswordHash string `json:"password_hash" binding:"required"`
-	FullName     string `json:"full_name" binding:"required"`
-	Phone        string `json:"phone"`
-	Role         string `json:"role" binding:"required"`
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	FullName string `json:"full_name" binding:"required"`
+	Phone    string `json:"phone"`
+	Role     string `json:"role" binding:"required"`
 }
 
 // @Summary Создать пользователя
 // @Tags users
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param body body CreateUserRequest true "Пользователь"
 // @Success 201 {object} IDResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /users [post]
@@ -41,7 +44,38 @@ func (h *UserHandler) Create(c *gin.Context) {
 		RespondError(c, err)
 		return
 	}
-	id, err := h.uc.Create(c.Request.Context(), user.CreateUserInput(req))
+	id, err := h.uc.Create(c.Request.Context(), actorFromContext(c), user.CreateUserInput(req))
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, IDResponse{ID: id.String()})
+}
+
+type RegisterRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	FullName string `json:"full_name" binding:"required"`
+	Phone    string `json:"phone"`
+}
+
+// @Summary Регистрация участника
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body RegisterRequest true "Участник"
+// @Success 201 {object} IDResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/register [post]
+func (h *UserHandler) Register(c *gin.Context) {
+	var req RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, err)
+		return
+	}
+	id, err := h.uc.Register(c.Request.Context(), user.RegisterInput(req))
 	if err != nil {
 		RespondError(c, err)
 		return
@@ -51,10 +85,13 @@ func (h *UserHandler) Create(c *gin.Context) {
 
 // @Summary Получить пользователя по id
 // @Tags users
+// @Security BearerAuth
 // @Produce json
 // @Param id path string true "User ID (UUID)"
 // @Success 200 {object} UserSwagger
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /users/{id} [get]
@@ -64,49 +101,56 @@ func (h *UserHandler) Get(c *gin.Context) {
 		RespondError(c, apperror.New(apperror.CodeValidation, "invalid id", err))
 		return
 	}
-	u, err := h.uc.Get(c.Request.Context(), id)
+	u, err := h.uc.Get(c.Request.Context(), actorFromContext(c), id)
 	if err != nil {
 		RespondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, u)
+	c.JSON(http.StatusOK, newUserResponse(u))
 }
 
 // @Summary Список пользователей
 // @Tags users
+// @Security BearerAuth
 // @Produce json
 // @Param limit query int false "Limit"
 // @Param offset query int false "Offset"
 // @Success 200 {array} UserSwagger
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /users [get]
 func (h *UserHandler) List(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	users, err := h.uc.List(c.Request.Context(), limit, offset)
+	users, err := h.uc.List(c.Request.Context(), actorFromContext(c), limit, offset)
 	if err != nil {
 		RespondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, users)
+	c.JSON(http.StatusOK, mapList(users, newUserResponse))
 }
 
 type UpdateUserRequest struct {
-	Email        string `json:"email" binding:"required"`
-	PasswordHash string `json:"password_hash" binding:"required"`
-	FullName     string `json:"full_name" binding:"required"`
-	Phone        string `json:"phone"`
-	Role         string `json:"role" binding:"required"`
-	IsActive     bool   `json:"is_active"`
+	Email string `json:"email" binding:"required"`
+	// Password is optional; omit it to keep the current password.
+	Password string `json:"password"`
+	FullName string `json:"full_name" binding:"required"`
+	Phone    string `json:"phone"`
+	Role     string `json:"role" binding:"required"`
+	IsActive bool   `json:"is_active"`
 }
 
 // @Summary Обновить пользователя
 // @Tags users
+// @Security BearerAuth
 // @Accept json
 // @Param id path string true "User ID (UUID)"
 // @Param body body UpdateUserRequest true "Поля пользователя"
 // @Success 204
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -122,14 +166,14 @@ func (h *UserHandler) Update(c *gin.Context) {
 		RespondError(c, err)
 		return
 	}
-	err = h.uc.Update(c.Request.Context(), user.UpdateUserInput{
-		ID:           id,
-		Email:        req.Email,
-		PasswordHash: req.PasswordHash,
-		FullName:     req.FullName,
-		Phone:        req.Phone,
-		Role:         req.Role,
-		IsActive:     req.IsActive,
+	err = h.uc.Update(c.Request.Context(), actorFromContext(c), user.UpdateUserInput{
+		ID:       id,
+		Email:    req.Email,
+		Password: req.Password,
+		FullName: req.FullName,
+		Phone:    req.Phone,
+		Role:     req.Role,
+		IsActive: req.IsActive,
 	})
 	if err != nil {
 		RespondError(c, err)
@@ -140,9 +184,12 @@ func (h *UserHandler) Update(c *gin.Context) {
 
 // @Summary Удалить пользователя
 // @Tags users
+// @Security BearerAuth
 // @Param id path string true "User ID (UUID)"
 // @Success 204
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /users/{id} [delete]
@@ -152,7 +199,7 @@ func (h *UserHandler) Delete(c *gin.Context) {
 		RespondError(c, apperror.New(apperror.CodeValidation, "invalid id", err))
 		return
 	}
-	if err := h.uc.Delete(c.Request.Context(), id); err != nil {
+	if err := h.uc.Delete(c.Request.Context(), actorFromContext(c), id); err != nil {
 		RespondError(c, err)
 		return
 	}
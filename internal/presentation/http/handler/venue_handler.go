@@ -30,11 +30,14 @@ type CreateVenueRequest struct {
 
 // @Summary Создать площадку
 // @Tags venues
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param body body CreateVenueRequest true "Площадка"
 // @Success 201 {object} IDResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /venues [post]
 func (h *VenueHandler) CreateVenue(c *gin.Context) {
@@ -43,7 +46,7 @@ func (h *VenueHandler) CreateVenue(c *gin.Context) {
 		RespondError(c, err)
 		return
 	}
-	id, err := h.uc.CreateVenue(c.Request.Context(), venue.CreateVenueInput(req))
+	id, err := h.uc.CreateVenue(c.Request.Context(), actorFromContext(c), venue.CreateVenueInput(req))
 	if err != nil {
 		RespondError(c, err)
 		return
@@ -53,10 +56,13 @@ func (h *VenueHandler) CreateVenue(c *gin.Context) {
 
 // @Summary Получить площадку по id
 // @Tags venues
+// @Security BearerAuth
 // @Produce json
 // @Param id path string true "Venue ID (UUID)"
 // @Success 200 {object} VenueSwagger
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /venues/{id} [get]
@@ -71,15 +77,18 @@ func (h *VenueHandler) GetVenue(c *gin.Context) {
 		RespondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, v)
+	c.JSON(http.StatusOK, newVenueResponse(v))
 }
 
 // @Summary Список площадок
 // @Tags venues
+// @Security BearerAuth
 // @Produce json
 // @Param limit query int false "Limit"
 // @Param offset query int false "Offset"
 // @Success 200 {array} VenueSwagger
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /venues [get]
 func (h *VenueHandler) ListVenues(c *gin.Context) {
@@ -90,7 +99,7 @@ func (h *VenueHandler) ListVenues(c *gin.Context) {
 		RespondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, venues)
+	c.JSON(http.StatusOK, mapList(venues, newVenueResponse))
 }
 
 type UpdateVenueRequest struct {
@@ -107,11 +116,14 @@ type UpdateVenueRequest struct {
 
 // @Summary Обновить площадку
 // @Tags venues
+// @Security BearerAuth
 // @Accept json
 // @Param id path string true "Venue ID (UUID)"
 // @Param body body UpdateVenueRequest true "Поля площадки"
 // @Success 204
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /venues/{id} [put]
@@ -126,7 +138,7 @@ func (h *VenueHandler) UpdateVenue(c *gin.Context) {
 		RespondError(c, err)
 		return
 	}
-	err = h.uc.UpdateVenue(c.Request.Context(), venue.UpdateVenueInput{
+	err = h.uc.UpdateVenue(c.Request.Context(), actorFromContext(c), venue.UpdateVenueInput{
 		ID:           id,
 		Name:         req.Name,
 		Address:      req.Address,
@@ -147,9 +159,12 @@ func (h *VenueHandler) UpdateVenue(c *gin.Context) {
 
 // @Summary Удалить площадку
 // @Tags venues
+// @Security BearerAuth
 // @Param id path string true "Venue ID (UUID)"
 // @Success 204
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /venues/{id} [delete]
@@ -159,7 +174,7 @@ func (h *VenueHandler) DeleteVenue(c *gin.Context) {
 		RespondError(c, apperror.New(apperror.CodeValidation, "invalid id", err))
 		return
 	}
-	if err := h.uc.DeleteVenue(c.Request.Context(), id); err != nil {
+	if err := h.uc.DeleteVenue(c.Request.Context(), actorFromContext(c), id); err != nil {
 		RespondError(c, err)
 		return
 	}
@@ -177,12 +192,15 @@ type CreateRoomRequest struct {
 
 // @Summary Создать помещение на площадке
 // @Tags venues
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Venue ID (UUID)"
 // @Param body body CreateRoomRequest true "Помещение"
 // @Success 201 {object} IDResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /venues/{id}/rooms [post]
@@ -197,7 +215,7 @@ func (h *VenueHandler) CreateRoom(c *gin.Context) {
 		RespondError(c, err)
 		return
 	}
-	id, err := h.uc.CreateRoom(c.Request.Context(), venue.CreateRoomInput{
+	id, err := h.uc.CreateRoom(c.Request.Context(), actorFromContext(c), venue.CreateRoomInput{
 		VenueID:     venueID,
 		Name:        req.Name,
 		Capacity:    req.Capacity,
@@ -215,10 +233,13 @@ func (h *VenueHandler) CreateRoom(c *gin.Context) {
 
 // @Summary Список помещений площадки
 // @Tags venues
+// @Security BearerAuth
 // @Produce json
 // @Param id path string true "Venue ID (UUID)"
 // @Success 200 {array} RoomSwagger
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /venues/{id}/rooms [get]
 func (h *VenueHandler) ListRooms(c *gin.Context) {
@@ -232,5 +253,5 @@ func (h *VenueHandler) ListRooms(c *gin.Context) {
 		RespondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, rooms)
+	c.JSON(http.StatusOK, mapList(rooms, newRoomResponse))
 }
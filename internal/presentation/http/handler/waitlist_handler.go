@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"time2meet/internal/application/usecase/ticket"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WaitlistHandler struct {
+	uc *ticket.WaitlistUseCase
+}
+
+func NewWaitlistHandler(uc *ticket.WaitlistUseCase) *WaitlistHandler {
+	return &WaitlistHandler{uc: uc}
+}
+
+type JoinWaitlistRequest struct {
+	TicketTypeID string `json:"ticket_type_id" binding:"required"`
+	Quantity     int    `json:"quantity" binding:"required"`
+}
+
+// @Summary Встать в лист ожидания распроданного типа билетов
+// @Tags waitlist
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body JoinWaitlistRequest true "Заявка"
+// @Success 201 {object} WaitlistEntryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /waitlist [post]
+func (h *WaitlistHandler) Join(c *gin.Context) {
+	var req JoinWaitlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid body", err))
+		return
+	}
+	ttID, err := valueobject.ParseUUID(req.TicketTypeID)
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid ticket_type_id", err))
+		return
+	}
+	e, err := h.uc.Join(c.Request.Context(), ticket.JoinWaitlistInput{
+		Actor:        actorFromContext(c),
+		IP:           clientIP(c),
+		TicketTypeID: ttID,
+		Quantity:     req.Quantity,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, newWaitlistEntryResponse(e))
+}
+
+// @Summary Мои заявки в листах ожидания
+// @Tags waitlist
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {array} WaitlistEntryResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /waitlist [get]
+func (h *WaitlistHandler) ListMine(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	rows, err := h.uc.ListMine(c.Request.Context(), actorFromContext(c), limit, offset)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, mapList(rows, newWaitlistEntryResponse))
+}
+
+// @Summary Заявка в листе ожидания
+// @Tags waitlist
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Waitlist entry ID (UUID)"
+// @Success 200 {object} WaitlistEntryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /waitlist/{id} [get]
+func (h *WaitlistHandler) Get(c *gin.Context) {
+	id, ok := waitlistPathID(c)
+	if !ok {
+		return
+	}
+	e, err := h.uc.Get(c.Request.Context(), actorFromContext(c), id)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newWaitlistEntryResponse(e))
+}
+
+// @Summary Покинуть лист ожидания (открытое предложение отклоняется)
+// @Tags waitlist
+// @Security BearerAuth
+// @Param id path string true "Waitlist entry ID (UUID)"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /waitlist/{id} [delete]
+func (h *WaitlistHandler) Leave(c *gin.Context) {
+	id, ok := waitlistPathID(c)
+	if !ok {
+		return
+	}
+	if err := h.uc.Leave(c.Request.Context(), actorFromContext(c), clientIP(c), id); err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Лист ожидания типа билетов
+// @Tags waitlist
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Event ID (UUID)"
+// @Param type_id path string true "Ticket type ID (UUID)"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {array} WaitlistEntryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/ticket-types/{type_id}/waitlist [get]
+func (h *WaitlistHandler) ListForTicketType(c *gin.Context) {
+	eventID, typeID, ok := ticketTypePath(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	rows, err := h.uc.ListForTicketType(c.Request.Context(), actorFromContext(c), eventID, typeID, limit, offset)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, mapList(rows, newWaitlistEntryResponse))
+}
+
+func waitlistPathID(c *gin.Context) (valueobject.UUID, bool) {
+	id, err := valueobject.ParseUUID(c.Param("id"))
+	if err != nil {
+		RespondError(c, apperror.New(apperror.CodeValidation, "invalid id", err))
+		return valueobject.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"time2meet/internal/application/port/authtoken"
+	"time2meet/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Authenticate verifies a bearer access token and populates the user id and role.
+// Requests without an Authorization header pass through anonymously; an invalid
+// token is rejected with 401.
+func Authenticate(tokens authtoken.Issuer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(CtxIPKey, c.ClientIP())
+
+		h := c.GetHeader("Authorization")
+		if h == "" {
+			c.Next()
+			return
+		}
+		scheme, token, ok := strings.Cut(h, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			abortUnauthorized(c, apperror.New(apperror.CodeUnauthorized, "malformed authorization header", nil))
+			return
+		}
+		claims, err := tokens.Parse(authtoken.KindAccess, strings.TrimSpace(token))
+		if err != nil {
+			abortUnauthorized(c, err)
+			return
+		}
+		c.Set(CtxUserIDKey, claims.UserID)
+		c.Set(CtxRoleKey, claims.Role)
+		c.Next()
+	}
+}
+
+func abortUnauthorized(c *gin.Context, err error) {
+	msg := "unauthorized"
+	var ae *apperror.AppError
+	if errors.As(err, &ae) {
+		msg = ae.Message
+	}
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+		"code":    apperror.CodeUnauthorized,
+		"message": msg,
+	})
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"net/http"
+
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Rule describes who may call a route.
+type Rule struct {
+	Public bool
+	Roles  []entity.UserRole
+	// Devices admits registered scanner devices; which event they may scan is checked in the use case.
+	Devices bool
+}
+
+func Public() Rule { return Rule{Public: true} }
+
+func Roles(roles ...entity.UserRole) Rule { return Rule{Roles: roles} }
+
+// Authenticated allows any signed-in user regardless of role.
+func Authenticated() Rule {
+	return Roles(entity.UserRoleAdmin, entity.UserRoleOrganizer, entity.UserRoleAttendee)
+}
+
+// OrDevices additionally admits scanner devices.
+func (r Rule) OrDevices() Rule {
+	r.Devices = true
+	return r
+}
+
+func (r Rule) Allows(role entity.UserRole) bool {
+	for _, allowed := range r.Roles {
+		if allowed == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Policy maps a route key ("METHOD /full/path", as registered in gin) to its rule.
+type Policy map[string]Rule
+
+func RouteKey(method, fullPath string) string { return method + " " + fullPath }
+
+// Missing returns the keys of registered routes that have no rule.
+func (p Policy) Missing(routes gin.RoutesInfo) []string {
+	var out []string
+	for _, rt := range routes {
+		key := RouteKey(rt.Method, rt.Path)
+		if _, ok := p[key]; !ok {
+			out = append(out, key)
+		}
+	}
+	return out
+}
+
+// Authorize enforces the policy. Routes without a rule are denied.
+func Authorize(p Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.FullPath()
+		if path == "" {
+			// No route matched: let gin answer 404/405.
+			c.Next()
+			return
+		}
+		rule, ok := p[RouteKey(c.Request.Method, path)]
+		if !ok {
+			abortForbidden(c, "route is not covered by the access policy")
+			return
+		}
+		if rule.Public {
+			c.Next()
+			return
+		}
+		if _, ok := c.Get(CtxDeviceKey); ok {
+			if !rule.Devices {
+				abortForbidden(c, "route is not available to scanner devices")
+				return
+			}
+			c.Next()
+			return
+		}
+		uidAny, _ := c.Get(CtxUserIDKey)
+		userID, _ := uidAny.(valueobject.UUID)
+		if userID == valueobject.Nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"code":    apperror.CodeUnauthorized,
+				"message": "authentication required",
+			})
+			return
+		}
+		roleAny, _ := c.Get(CtxRoleKey)
+		role, _ := roleAny.(entity.UserRole)
+		if !rule.Allows(role) {
+			abortForbidden(c, "insufficient role")
+			return
+		}
+		c.Next()
+	}
+}
+
+func abortForbidden(c *gin.Context, msg string) {
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+		"code":    apperror.CodeForbidden,
+		"message": msg,
+	})
+}
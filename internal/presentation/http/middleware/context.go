@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"time2meet/internal/domain/entity"
 	"time2meet/internal/domain/valueobject"
 
 	"github.com/gin-gonic/gin"
@@ -8,9 +9,13 @@ import (
 
 const (
 	CtxUserIDKey = "user_id"
+	CtxRoleKey   = "role"
 	CtxIPKey     = "ip"
+	CtxDeviceKey = "device"
 )
 
+// ContextFromHeaders trusts X-User-Id/X-User-Role as sent by the client.
+// It is only wired when config.AuthConfig.DevHeaders is enabled.
 func ContextFromHeaders() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if v := c.GetHeader("X-User-Id"); v != "" {
@@ -18,6 +23,9 @@ func ContextFromHeaders() gin.HandlerFunc {
 				c.Set(CtxUserIDKey, id)
 			}
 		}
+		if v := c.GetHeader("X-User-Role"); v != "" {
+			c.Set(CtxRoleKey, entity.UserRole(v))
+		}
 		c.Set(CtxIPKey, c.ClientIP())
 		c.Next()
 	}
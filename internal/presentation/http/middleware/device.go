@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"time2meet/internal/application/authz"
+	"time2meet/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+const DeviceTokenHeader = "X-Device-Token"
+
+type DeviceAuthenticator interface {
+	AuthenticateDevice(ctx context.Context, token string) (authz.Device, error)
+}
+
+// DeviceAuthenticate lets registered scanner devices call check-in routes with their own
+// token instead of a user's. It must run after user authentication; sending both is rejected.
+func DeviceAuthenticate(devices DeviceAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := strings.TrimSpace(c.GetHeader(DeviceTokenHeader))
+		if token == "" {
+			c.Next()
+			return
+		}
+		if _, ok := c.Get(CtxUserIDKey); ok {
+			abortUnauthorized(c, apperror.New(apperror.CodeUnauthorized, "send either a device token or a user token", nil))
+			return
+		}
+		d, err := devices.AuthenticateDevice(c.Request.Context(), token)
+		if err != nil {
+			var ae *apperror.AppError
+			if errors.As(err, &ae) && ae.Code == apperror.CodeUnauthorized {
+				abortUnauthorized(c, err)
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"code":    apperror.CodeInternal,
+				"message": "internal error",
+			})
+			return
+		}
+		c.Set(CtxDeviceKey, d)
+		c.Next()
+	}
+}
@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"time2meet/internal/application/port/idempotency"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks responses served from the idempotency store.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLen = 255
+	// idempotencyLockTimeout bounds how long a claim survives a crashed request. It must
+	// exceed the slowest idempotent handler (batch imports).
+	idempotencyLockTimeout = 5 * time.Minute
+)
+
+// Idempotency makes a route safe to retry. The first request carrying an Idempotency-Key
+// claims it together with a fingerprint of the method, URI and body; a retry with the same
+// fingerprint gets the stored response replayed, a different request under the same key is
+// rejected with 422. Server errors are not stored, so the client may retry them.
+// Keys are scoped per user; it must run after authentication.
+func Idempotency(store idempotency.Store, ttl time.Duration, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(IdempotencyKeyHeader))
+		if key == "" {
+			c.Next()
+			return
+		}
+		uidAny, ok := c.Get(CtxUserIDKey)
+		if !ok {
+			c.Next()
+			return
+		}
+		userID := uidAny.(valueobject.UUID)
+		if len(key) > maxIdempotencyKeyLen {
+			abortWithCode(c, http.StatusBadRequest, apperror.CodeValidation, "Idempotency-Key is too long")
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abortWithCode(c, http.StatusBadRequest, apperror.CodeValidation, "failed to read request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.RequestURI(), body)
+
+		ctx := c.Request.Context()
+		now := time.Now().UTC()
+		rec, claimed, err := store.Claim(ctx, userID, key, fingerprint, now.Add(idempotencyLockTimeout), now.Add(ttl))
+		if err != nil {
+			abortStoreError(c, log, err)
+			return
+		}
+		if !claimed {
+			switch {
+			case rec.Fingerprint != fingerprint:
+				abortWithCode(c, http.StatusUnprocessableEntity, apperror.CodeIdempotencyMismatch, "Idempotency-Key was already used with a different request")
+			case !rec.Completed:
+				abortWithCode(c, http.StatusConflict, apperror.CodeConflict, "a request with this Idempotency-Key is still in progress")
+			default:
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(rec.StatusCode, rec.ContentType, rec.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		// The outcome is persisted even if the client has gone away, otherwise its retry
+		// would wait for the lock timeout and then run the request again.
+		storeCtx := context.WithoutCancel(ctx)
+		done := false
+		defer func() {
+			if !done {
+				if err := store.Release(storeCtx, userID, key); err != nil {
+					log.Error("release idempotency key failed", zap.Error(err))
+				}
+			}
+		}()
+
+		w := &capturingWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+
+		if status := w.Status(); status < http.StatusInternalServerError {
+			err := store.Complete(storeCtx, userID, key, idempotency.Record{
+				Fingerprint: fingerprint,
+				Completed:   true,
+				StatusCode:  status,
+				ContentType: w.Header().Get("Content-Type"),
+				Body:        w.body.Bytes(),
+			})
+			if err != nil {
+				log.Error("store idempotent response failed", zap.Error(err))
+				return
+			}
+			done = true
+		}
+	}
+}
+
+// requestFingerprint hashes what must match for a retry to count as the same request.
+func requestFingerprint(method, uri string, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, method)
+	h.Write([]byte{'\n'})
+	io.WriteString(h, uri)
+	h.Write([]byte{'\n'})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// capturingWriter keeps a copy of the response body so it can be stored for replay.
+type capturingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *capturingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *capturingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+func abortWithCode(c *gin.Context, status int, code apperror.Code, msg string) {
+	c.AbortWithStatusJSON(status, gin.H{
+		"code":    code,
+		"message": msg,
+	})
+}
+
+func abortStoreError(c *gin.Context, log *zap.Logger, err error) {
+	var ae *apperror.AppError
+	if errors.As(err, &ae) && ae.Code == apperror.CodeConflict {
+		abortWithCode(c, http.StatusConflict, ae.Code, ae.Message)
+		return
+	}
+	log.Error("idempotency store failed", zap.Error(err))
+	abortWithCode(c, http.StatusInternalServerError, apperror.CodeInternal, "internal error")
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/presentation/http/middleware"
+)
+
+const (
+	admin     = entity.UserRoleAdmin
+	organizer = entity.UserRoleOrganizer
+)
+
+// RoutePolicy is the route/role matrix. Every route registered on the engine must be listed here;
+// finer-grained checks (self-access, ownership) live in the use cases.
+var RoutePolicy = middleware.Policy{
+	"GET /api/v1/healthz":       middleware.Public(),
+	"GET /api/v1/swagger/*any":  middleware.Public(),
+	"POST /api/v1/auth/login":   middleware.Public(),
+	"POST /api/v1/auth/refresh": middleware.Public(),
+	"POST /api/v1/auth/logout":  middleware.Public(),
+
+	"POST /api/v1/auth/claim":         middleware.Public(),
+	"POST /api/v1/auth/claim/confirm": middleware.Public(),
+
+	"POST /api/v1/auth/register": middleware.Public(),
+
+	"GET /api/v1/users":        middleware.Roles(admin),
+	"POST /api/v1/users":       middleware.Roles(admin),
+	"GET /api/v1/users/:id":    middleware.Authenticated(),
+	"PUT /api/v1/users/:id":    middleware.Authenticated(),
+	"DELETE /api/v1/users/:id": middleware.Roles(admin),
+
+	"GET /api/v1/events":             middleware.Authenticated(),
+	"POST /api/v1/events":            middleware.Roles(admin, organizer),
+	"GET /api/v1/events/:id":         middleware.Authenticated(),
+	"PUT /api/v1/events/:id":         middleware.Roles(admin, organizer),
+	"DELETE /api/v1/events/:id":      middleware.Roles(admin, organizer),
+	"POST /api/v1/events/:id/cancel": middleware.Roles(admin, organizer),
+
+	"GET /api/v1/events/:id/ticket-types":             middleware.Authenticated(),
+	"POST /api/v1/events/:id/ticket-types":            middleware.Roles(admin, organizer),
+	"GET /api/v1/events/:id/ticket-types/:type_id":    middleware.Authenticated(),
+	"PUT /api/v1/events/:id/ticket-types/:type_id":    middleware.Roles(admin, organizer),
+	"DELETE /api/v1/events/:id/ticket-types/:type_id": middleware.Roles(admin, organizer),
+
+	"GET /api/v1/events/:id/ticket-types/:type_id/waitlist": middleware.Roles(admin, organizer),
+	"POST /api/v1/events/:id/ticket-types/:type_id/comps":   middleware.Roles(admin, organizer),
+
+	"GET /api/v1/events/:id/promo-codes":             middleware.Roles(admin, organizer),
+	"POST /api/v1/events/:id/promo-codes":            middleware.Roles(admin, organizer),
+	"GET /api/v1/events/:id/promo-codes/:code_id":    middleware.Roles(admin, organizer),
+	"PUT /api/v1/events/:id/promo-codes/:code_id":    middleware.Roles(admin, organizer),
+	"DELETE /api/v1/events/:id/promo-codes/:code_id": middleware.Roles(admin, organizer),
+
+	"GET /api/v1/events/:id/checkin/manifest":      middleware.Roles(admin, organizer).OrDevices(),
+	"POST /api/v1/events/:id/checkin/sync":         middleware.Roles(admin, organizer).OrDevices(),
+	"GET /api/v1/events/:id/checkins":              middleware.Roles(admin, organizer),
+	"POST /api/v1/events/:id/devices":              middleware.Roles(admin, organizer),
+	"GET /api/v1/events/:id/devices":               middleware.Roles(admin, organizer),
+	"DELETE /api/v1/events/:id/devices/:device_id": middleware.Roles(admin, organizer),
+
+	"POST /api/v1/venues":           middleware.Roles(admin),
+	"GET /api/v1/venues":            middleware.Authenticated(),
+	"GET /api/v1/venues/:id":        middleware.Authenticated(),
+	"PUT /api/v1/venues/:id":        middleware.Roles(admin),
+	"DELETE /api/v1/venues/:id":     middleware.Roles(admin),
+	"POST /api/v1/venues/:id/rooms": middleware.Roles(admin),
+	"GET /api/v1/venues/:id/rooms":  middleware.Authenticated(),
+
+	"POST /api/v1/tickets/purchase":      middleware.Authenticated(),
+	"GET /api/v1/tickets/:id":            middleware.Authenticated(),
+	"GET /api/v1/tickets":                middleware.Authenticated(),
+	"PATCH /api/v1/tickets/:id/status":   middleware.Roles(admin),
+	"DELETE /api/v1/tickets/:id":         middleware.Roles(admin),
+	"POST /api/v1/tickets/validate":      middleware.Roles(admin, organizer).OrDevices(),
+	"GET /api/v1/tickets/:id/checkins":   middleware.Authenticated(),
+	"POST /api/v1/tickets/:id/refund":    middleware.Authenticated(),
+	"GET /api/v1/tickets/:id/refunds":    middleware.Authenticated(),
+	"POST /api/v1/tickets/:id/transfers": middleware.Authenticated(),
+	"GET /api/v1/tickets/:id/transfers":  middleware.Authenticated(),
+
+	"GET /api/v1/transfers":             middleware.Authenticated(),
+	"POST /api/v1/transfers/:id/accept": middleware.Authenticated(),
+	"DELETE /api/v1/transfers/:id":      middleware.Authenticated(),
+
+	"POST /api/v1/orders":    middleware.Authenticated(),
+	"GET /api/v1/orders":     middleware.Authenticated(),
+	"GET /api/v1/orders/:id": middleware.Authenticated(),
+
+	"POST /api/v1/holds":             middleware.Authenticated(),
+	"GET /api/v1/holds/:id":          middleware.Authenticated(),
+	"DELETE /api/v1/holds/:id":       middleware.Authenticated(),
+	"POST /api/v1/holds/:id/confirm": middleware.Authenticated(),
+
+	"POST /api/v1/waitlist":       middleware.Authenticated(),
+	"GET /api/v1/waitlist":        middleware.Authenticated(),
+	"GET /api/v1/waitlist/:id":    middleware.Authenticated(),
+	"DELETE /api/v1/waitlist/:id": middleware.Authenticated(),
+
+	// Authenticated by the provider's signature, see PaymentProcessor.HandleWebhook.
+	"POST /api/v1/webhooks/payments": middleware.Public(),
+
+	"GET /api/v1/reports/sales":             middleware.Roles(admin),
+	"GET /api/v1/reports/attendance":        middleware.Roles(admin, organizer),
+	"GET /api/v1/reports/flagged-purchases": middleware.Roles(admin),
+	"GET /api/v1/reports/waitlist":          middleware.Roles(admin, organizer),
+	"GET /api/v1/analytics/popular-events":  middleware.Roles(admin, organizer),
+
+	"POST /api/v1/batch/import/users":   middleware.Roles(admin),
+	"POST /api/v1/batch/import/events":  middleware.Roles(admin),
+	"POST /api/v1/batch/import/tickets": middleware.Roles(admin),
+}
@@ -0,0 +1,265 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"time2meet/internal/application/authz"
+	"time2meet/internal/domain/entity"
+	"time2meet/internal/domain/valueobject"
+	"time2meet/internal/infrastructure/config"
+	"time2meet/internal/infrastructure/fakepay"
+	"time2meet/internal/presentation/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// access is who may call a route.
+type access int
+
+const (
+	public      access = iota
+	anyUser            // any signed-in user
+	adminOnly          // admins
+	staff              // admins and organizers
+	staffDevice        // admins, organizers and scanner devices
+)
+
+// routeMatrix is the expected access of every route. It is kept apart from RoutePolicy on
+// purpose: changing who may call a route means changing both.
+var routeMatrix = map[string]access{
+	"GET /api/v1/healthz":       public,
+	"GET /api/v1/swagger/*any":  public,
+	"POST /api/v1/auth/login":   public,
+	"POST /api/v1/auth/refresh": public,
+	"POST /api/v1/auth/logout":  public,
+
+	"POST /api/v1/auth/claim":         public,
+	"POST /api/v1/auth/claim/confirm": public,
+
+	"POST /api/v1/auth/register": public,
+
+	"GET /api/v1/users":        adminOnly,
+	"POST /api/v1/users":       adminOnly,
+	"GET /api/v1/users/:id":    anyUser,
+	"PUT /api/v1/users/:id":    anyUser,
+	"DELETE /api/v1/users/:id": adminOnly,
+
+	"GET /api/v1/events":             anyUser,
+	"POST /api/v1/events":            staff,
+	"GET /api/v1/events/:id":         anyUser,
+	"PUT /api/v1/events/:id":         staff,
+	"DELETE /api/v1/events/:id":      staff,
+	"POST /api/v1/events/:id/cancel": staff,
+
+	"GET /api/v1/events/:id/ticket-types":                   anyUser,
+	"POST /api/v1/events/:id/ticket-types":                  staff,
+	"GET /api/v1/events/:id/ticket-types/:type_id":          anyUser,
+	"PUT /api/v1/events/:id/ticket-types/:type_id":          staff,
+	"DELETE /api/v1/events/:id/ticket-types/:type_id":       staff,
+	"GET /api/v1/events/:id/ticket-types/:type_id/waitlist": staff,
+	"POST /api/v1/events/:id/ticket-types/:type_id/comps":   staff,
+
+	"GET /api/v1/events/:id/promo-codes":             staff,
+	"POST /api/v1/events/:id/promo-codes":            staff,
+	"GET /api/v1/events/:id/promo-codes/:code_id":    staff,
+	"PUT /api/v1/events/:id/promo-codes/:code_id":    staff,
+	"DELETE /api/v1/events/:id/promo-codes/:code_id": staff,
+
+	"GET /api/v1/events/:id/checkin/manifest":      staffDevice,
+	"POST /api/v1/events/:id/checkin/sync":         staffDevice,
+	"GET /api/v1/events/:id/checkins":              staff,
+	"POST /api/v1/events/:id/devices":              staff,
+	"GET /api/v1/events/:id/devices":               staff,
+	"DELETE /api/v1/events/:id/devices/:device_id": staff,
+
+	"POST /api/v1/venues":           adminOnly,
+	"GET /api/v1/venues":            anyUser,
+	"GET /api/v1/venues/:id":        anyUser,
+	"PUT /api/v1/venues/:id":        adminOnly,
+	"DELETE /api/v1/venues/:id":     adminOnly,
+	"POST /api/v1/venues/:id/rooms": adminOnly,
+	"GET /api/v1/venues/:id/rooms":  anyUser,
+
+	"POST /api/v1/tickets/purchase":      anyUser,
+	"GET /api/v1/tickets/:id":            anyUser,
+	"GET /api/v1/tickets":                anyUser,
+	"PATCH /api/v1/tickets/:id/status":   adminOnly,
+	"DELETE /api/v1/tickets/:id":         adminOnly,
+	"POST /api/v1/tickets/validate":      staffDevice,
+	"GET /api/v1/tickets/:id/checkins":   anyUser,
+	"POST /api/v1/tickets/:id/refund":    anyUser,
+	"GET /api/v1/tickets/:id/refunds":    anyUser,
+	"POST /api/v1/tickets/:id/transfers": anyUser,
+	"GET /api/v1/tickets/:id/transfers":  anyUser,
+
+	"GET /api/v1/transfers":             anyUser,
+	"POST /api/v1/transfers/:id/accept": anyUser,
+	"DELETE /api/v1/transfers/:id":      anyUser,
+
+	"POST /api/v1/orders":    anyUser,
+	"GET /api/v1/orders":     anyUser,
+	"GET /api/v1/orders/:id": anyUser,
+
+	"POST /api/v1/holds":             anyUser,
+	"GET /api/v1/holds/:id":          anyUser,
+	"DELETE /api/v1/holds/:id":       anyUser,
+	"POST /api/v1/holds/:id/confirm": anyUser,
+
+	"POST /api/v1/waitlist":       anyUser,
+	"GET /api/v1/waitlist":        anyUser,
+	"GET /api/v1/waitlist/:id":    anyUser,
+	"DELETE /api/v1/waitlist/:id": anyUser,
+
+	"POST /api/v1/webhooks/payments": public,
+
+	"GET /api/v1/reports/sales":             adminOnly,
+	"GET /api/v1/reports/attendance":        staff,
+	"GET /api/v1/reports/flagged-purchases": adminOnly,
+	"GET /api/v1/reports/waitlist":          staff,
+	"GET /api/v1/analytics/popular-events":  staff,
+
+	"POST /api/v1/batch/import/users":   adminOnly,
+	"POST /api/v1/batch/import/events":  adminOnly,
+	"POST /api/v1/batch/import/tickets": adminOnly,
+}
+
+// caller is who sends a request in the matrix test.
+type caller string
+
+const (
+	anonymous       caller = "anonymous"
+	attendeeCaller  caller = caller(entity.UserRoleAttendee)
+	organizerCaller caller = caller(entity.UserRoleOrganizer)
+	adminCaller     caller = caller(entity.UserRoleAdmin)
+	deviceCaller    caller = "device"
+)
+
+func (a access) allows(c caller) bool {
+	switch a {
+	case public:
+		return true
+	case anyUser:
+		return c == attendeeCaller || c == organizerCaller || c == adminCaller
+	case adminOnly:
+		return c == adminCaller
+	case staff:
+		return c == organizerCaller || c == adminCaller
+	case staffDevice:
+		return c == organizerCaller || c == adminCaller || c == deviceCaller
+	}
+	return false
+}
+
+func routerRoutes(t *testing.T) gin.RoutesInfo {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	srv, _ := NewServer(config.Config{}, nil, fakepay.New(fakepay.ModeSucceed, ""), zap.NewNop())
+	return srv.Handler.(*gin.Engine).Routes()
+}
+
+func TestRoutePolicyCoversEveryRoute(t *testing.T) {
+	routes := routerRoutes(t)
+	if missing := RoutePolicy.Missing(routes); len(missing) > 0 {
+		sort.Strings(missing)
+		t.Fatalf("routes without an access policy:\n%s", strings.Join(missing, "\n"))
+	}
+
+	registered := make(map[string]bool, len(routes))
+	for _, rt := range routes {
+		key := middleware.RouteKey(rt.Method, rt.Path)
+		registered[key] = true
+		if _, ok := routeMatrix[key]; !ok {
+			t.Errorf("%s is not in the test route matrix", key)
+		}
+	}
+	for key := range routeMatrix {
+		if !registered[key] {
+			t.Errorf("%s is in the test route matrix but not registered", key)
+		}
+	}
+	for key := range RoutePolicy {
+		if !registered[key] {
+			t.Errorf("%s has a policy but is not registered", key)
+		}
+	}
+}
+
+// TestRoutePolicyMatrix sends every route through Authorize with RoutePolicy as each kind of
+// caller. Handlers are stubbed, so a request that gets past Authorize answers 204.
+func TestRoutePolicyMatrix(t *testing.T) {
+	routes := routerRoutes(t)
+
+	e := gin.New()
+	e.Use(middleware.ContextFromHeaders(), fakeDevice(), middleware.Authorize(RoutePolicy))
+	for _, rt := range routes {
+		e.Handle(rt.Method, rt.Path, func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	}
+
+	callers := []caller{anonymous, attendeeCaller, organizerCaller, adminCaller, deviceCaller}
+	for _, rt := range routes {
+		key := middleware.RouteKey(rt.Method, rt.Path)
+		want, ok := routeMatrix[key]
+		if !ok {
+			continue // reported by TestRoutePolicyCoversEveryRoute
+		}
+		for _, who := range callers {
+			t.Run(key+"/"+string(who), func(t *testing.T) {
+				req := httptest.NewRequest(rt.Method, concretePath(rt.Path), nil)
+				switch who {
+				case anonymous:
+				case deviceCaller:
+					req.Header.Set(testDeviceHeader, "1")
+				default:
+					req.Header.Set("X-User-Id", valueobject.NewUUID().String())
+					req.Header.Set("X-User-Role", string(who))
+				}
+				w := httptest.NewRecorder()
+				e.ServeHTTP(w, req)
+
+				var wantStatus int
+				switch {
+				case want.allows(who):
+					wantStatus = http.StatusNoContent
+				case who == anonymous:
+					wantStatus = http.StatusUnauthorized
+				default:
+					wantStatus = http.StatusForbidden
+				}
+				if w.Code != wantStatus {
+					t.Fatalf("status = %d, want %d (body %s)", w.Code, wantStatus, w.Body.String())
+				}
+			})
+		}
+	}
+}
+
+const testDeviceHeader = "X-Test-Device"
+
+// fakeDevice stands in for middleware.DeviceAuthenticate, which needs the database.
+func fakeDevice() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(testDeviceHeader) != "" {
+			c.Set(middleware.CtxDeviceKey, authz.Device{ID: valueobject.NewUUID(), EventID: valueobject.NewUUID()})
+		}
+		c.Next()
+	}
+}
+
+// concretePath fills a gin route pattern with values that match it.
+func concretePath(pattern string) string {
+	parts := strings.Split(pattern, "/")
+	for i, p := range parts {
+		switch {
+		case strings.HasPrefix(p, ":"):
+			parts[i] = valueobject.NewUUID().String()
+		case strings.HasPrefix(p, "*"):
+			parts[i] = "index.html"
+		}
+	}
+	return strings.Join(parts, "/")
+}
@@ -1,14 +1,24 @@
 package http
 
 import (
+	"time2meet/internal/application/authz"
+	"time2meet/internal/application/port/payment"
+	"time2meet/internal/application/usecase/auth"
 	"time2meet/internal/application/usecase/batch"
+	"time2meet/internal/application/usecase/checkin"
 	"time2meet/internal/application/usecase/event"
 	"time2meet/internal/application/usecase/report"
 	"time2meet/internal/application/usecase/ticket"
 	"time2meet/internal/application/usecase/user"
 	"time2meet/internal/application/usecase/venue"
+	authinfra "time2meet/internal/infrastructure/auth"
+	"time2meet/internal/infrastructure/config"
+	"time2meet/internal/infrastructure/docsign"
+	"time2meet/internal/infrastructure/lognotify"
 	"time2meet/internal/infrastructure/persistence/postgres"
+	"time2meet/internal/infrastructure/qrsign"
 	"time2meet/internal/presentation/http/handler"
+	"time2meet/internal/presentation/http/middleware"
 
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -21,42 +31,131 @@ import (
 )
 
 type Dependencies struct {
-	DB  *sqlx.DB
-	Log *zap.Logger
+	Config         config.Config
+	DB             *sqlx.DB
+	PaymentGateway payment.Gateway
+	Log            *zap.Logger
 }
 
-func NewRouter(deps Dependencies) *gin.Engine {
+// Services are the use cases the API shares with its background workers, so both run with
+// the same configuration.
+type Services struct {
+	Payments *ticket.PaymentProcessor
+	Waitlist *ticket.WaitlistUseCase
+}
+
+// NewPaymentProcessor wires the payment processor as the API uses it. Commands that settle
+// payments outside the API build theirs here too.
+func NewPaymentProcessor(deps Dependencies) *ticket.PaymentProcessor {
+	return ticket.NewPaymentProcessor(
+		postgres.NewTxManager(deps.DB, deps.Log),
+		postgres.NewAuditContextSetter(),
+		postgres.NewTicketTxQueries(),
+		postgres.NewPaymentRepo(deps.DB),
+		postgres.NewRefundRepo(deps.DB),
+		deps.PaymentGateway,
+		ticket.VelocityLimits{
+			Window:    deps.Config.Purchase.VelocityWindow,
+			MaxOrders: deps.Config.Purchase.VelocityMaxOrders,
+			MaxBuyers: deps.Config.Purchase.VelocityMaxBuyers,
+		},
+	)
+}
+
+func NewRouter(deps Dependencies) (*gin.Engine, Services) {
 	r := gin.New()
 	r.Use(gin.Recovery())
+	// Client IPs feed the purchase velocity checks, so X-Forwarded-For is only read from
+	// configured proxies. The list is validated by config.
+	if err := r.SetTrustedProxies(deps.Config.HTTP.TrustedProxies); err != nil {
+		deps.Log.Error("invalid trusted proxies; trusting none", zap.Error(err))
+		_ = r.SetTrustedProxies(nil)
+	}
+
+	tokens := authinfra.NewJWTIssuer(deps.Config.Auth.JWTSecret)
+	hasher := authinfra.NewArgon2Hasher(authinfra.DefaultArgon2Params)
+	qrSigner := qrsign.NewHMACSigner(deps.Config.QR.SigningKeys, deps.Config.QR.ActiveKeyID)
+	manifestSigner := docsign.NewEd25519Signer(deps.Config.QR.ManifestKeyID, deps.Config.QR.ManifestKey)
+	notifier := lognotify.New(deps.Log)
 
 	userRepo := postgres.NewUserRepo(deps.DB)
 	userProfileRepo := postgres.NewUserProfileRepo(deps.DB)
 	eventRepo := postgres.NewEventRepo(deps.DB)
 	venueRepo := postgres.NewVenueRepo(deps.DB)
 	roomRepo := postgres.NewRoomRepo(deps.DB)
+	ticketTypeRepo := postgres.NewTicketTypeRepo(deps.DB)
 	ticketRepo := postgres.NewTicketRepo(deps.DB)
+	deviceRepo := postgres.NewCheckinDeviceRepo(deps.DB)
+	checkinRepo := postgres.NewCheckinRepo(deps.DB)
+	orderRepo := postgres.NewOrderRepo(deps.DB)
+	holdRepo := postgres.NewTicketHoldRepo(deps.DB)
+	paymentRepo := postgres.NewPaymentRepo(deps.DB)
+	refundRepo := postgres.NewRefundRepo(deps.DB)
+	transferRepo := postgres.NewTicketTransferRepo(deps.DB)
+	promoRepo := postgres.NewPromoCodeRepo(deps.DB)
+	waitlistRepo := postgres.NewWaitlistRepo(deps.DB)
 	reportRepo := postgres.NewReportRepo(deps.DB)
+	sessionRepo := postgres.NewAuthSessionRepo(deps.DB)
 	txManager := postgres.NewTxManager(deps.DB, deps.Log)
 	auditCtx := postgres.NewAuditContextSetter()
 	ticketTx := postgres.NewTicketTxQueries()
 	batchImp := postgres.NewBatchImporter(deps.Log)
+	idemStore := postgres.NewIdempotencyStore(deps.DB)
+
+	authUC := auth.New(userRepo, sessionRepo, tokens, hasher, notifier, deps.Config.Auth.AccessTTL, deps.Config.Auth.RefreshTTL, deps.Config.Auth.ClaimTTL)
+	userUC := user.New(userRepo, userProfileRepo, hasher)
+	eventGuard := authz.NewEventGuard(eventRepo)
 
-	userUC := user.New(userRepo, userProfileRepo)
-	eventUC := event.New(eventRepo)
+	eventUC := event.New(eventRepo, eventGuard)
 	venueUC := venue.New(venueRepo, roomRepo)
-	reportUC := report.New(reportRepo)
-	purchaseUC := ticket.NewPurchase(txManager, auditCtx, ticketTx)
-	ticketUC := ticket.NewTicketUC(ticketRepo)
-	validateUC := ticket.NewValidate(txManager, auditCtx, ticketTx)
-	batchUC := batch.New(txManager, auditCtx, batchImp)
+	reportUC := report.New(reportRepo, eventGuard)
+	paymentProc := NewPaymentProcessor(deps)
+	purchaseUC := ticket.NewPurchase(ticketTx, qrSigner, paymentProc)
+	orderUC := ticket.NewOrder(ticketTx, orderRepo, ticketRepo, qrSigner, paymentProc)
+	holdUC := ticket.NewHold(txManager, auditCtx, ticketTx, holdRepo, qrSigner, paymentProc, deps.Config.Hold.TTL)
+	ticketTypeUC := ticket.NewTicketTypeUC(ticketTypeRepo, eventGuard)
+	promoUC := ticket.NewPromoCodeUC(promoRepo, ticketTypeRepo, eventGuard)
+	ticketUC := ticket.NewTicketUC(ticketRepo, txManager, auditCtx, ticketTx)
+	refundUC := ticket.NewRefund(txManager, auditCtx, ticketTx, ticketRepo, refundRepo, paymentRepo, paymentProc)
+	transferUC := ticket.NewTransfer(txManager, auditCtx, ticketTx, ticketRepo, transferRepo, userRepo, qrSigner, deps.Config.Transfer.TTL)
+	waitlistUC := ticket.NewWaitlist(txManager, auditCtx, ticketTx, waitlistRepo, ticketTypeRepo, userRepo, eventGuard, notifier, deps.Config.Waitlist.OfferTTL)
+	compUC := ticket.NewComp(txManager, auditCtx, ticketTx, qrSigner, eventGuard)
+	validateUC := ticket.NewValidate(txManager, auditCtx, ticketTx, checkinRepo, qrSigner, eventGuard)
+	offlineUC := ticket.NewOfflineCheckin(txManager, auditCtx, ticketTx, ticketRepo, checkinRepo, deviceRepo, qrSigner, manifestSigner, eventGuard)
+	checkinUC := checkin.New(deviceRepo, checkinRepo, ticketRepo, ticketTypeRepo, manifestSigner, eventGuard)
+	batchUC := batch.New(txManager, auditCtx, batchImp, hasher)
 
+	authH := handler.NewAuthHandler(authUC)
 	userH := handler.NewUserHandler(userUC)
 	eventH := handler.NewEventHandler(eventUC)
 	venueH := handler.NewVenueHandler(venueUC)
 	reportH := handler.NewReportHandler(reportUC)
+	ticketTypeH := handler.NewTicketTypeHandler(ticketTypeUC)
+	promoH := handler.NewPromoCodeHandler(promoUC)
 	ticketH := handler.NewTicketHandler(purchaseUC, ticketUC, validateUC)
+	checkinH := handler.NewCheckinHandler(offlineUC, checkinUC)
+	orderH := handler.NewOrderHandler(orderUC)
+	holdH := handler.NewHoldHandler(holdUC)
+	paymentH := handler.NewPaymentHandler(paymentProc)
+	refundH := handler.NewRefundHandler(refundUC)
+	transferH := handler.NewTransferHandler(transferUC)
+	waitlistH := handler.NewWaitlistHandler(waitlistUC)
+	compH := handler.NewCompHandler(compUC)
 	batchH := handler.NewBatchHandler(batchUC)
 
+	if deps.Config.Auth.DevHeaders {
+		deps.Log.Warn("AUTH_DEV_HEADERS is enabled: X-User-Id/X-User-Role headers are trusted")
+		r.Use(middleware.ContextFromHeaders())
+	} else {
+		r.Use(middleware.Authenticate(tokens))
+	}
+	r.Use(middleware.DeviceAuthenticate(checkinUC))
+	r.Use(middleware.Authorize(RoutePolicy))
+
+	// idem is attached to POST routes that create tickets or import data, so client retries
+	// cannot apply them twice.
+	idem := middleware.Idempotency(idemStore, deps.Config.Idempotency.TTL, deps.Log)
+
 	api := r.Group("/api/v1")
 	{
 		api.GET("/swagger/*any", ginSwagger.WrapHandler(
@@ -64,6 +163,13 @@ func NewRouter(deps Dependencies) *gin.Engine {
 			ginSwagger.URL("/api/v1/swagger/doc.json"),
 		))
 
+		api.POST("/auth/register", userH.Register)
+		api.POST("/auth/login", authH.Login)
+		api.POST("/auth/refresh", authH.Refresh)
+		api.POST("/auth/logout", authH.Logout)
+		api.POST("/auth/claim", authH.RequestClaim)
+		api.POST("/auth/claim/confirm", authH.Claim)
+
 		api.GET("/users", userH.List)
 		api.POST("/users", userH.Create)
 		api.GET("/users/:id", userH.Get)
@@ -76,6 +182,25 @@ func NewRouter(deps Dependencies) *gin.Engine {
 		api.PUT("/events/:id", eventH.Update)
 		api.DELETE("/events/:id", eventH.Delete)
 		api.POST("/events/:id/cancel", eventH.Cancel)
+		api.GET("/events/:id/ticket-types", ticketTypeH.List)
+		api.POST("/events/:id/ticket-types", ticketTypeH.Create)
+		api.GET("/events/:id/ticket-types/:type_id", ticketTypeH.Get)
+		api.PUT("/events/:id/ticket-types/:type_id", ticketTypeH.Update)
+		api.DELETE("/events/:id/ticket-types/:type_id", ticketTypeH.Delete)
+		api.GET("/events/:id/ticket-types/:type_id/waitlist", waitlistH.ListForTicketType)
+		api.POST("/events/:id/ticket-types/:type_id/comps", idem, compH.Issue)
+
+		api.GET("/events/:id/promo-codes", promoH.List)
+		api.POST("/events/:id/promo-codes", promoH.Create)
+		api.GET("/events/:id/promo-codes/:code_id", promoH.Get)
+		api.PUT("/events/:id/promo-codes/:code_id", promoH.Update)
+		api.DELETE("/events/:id/promo-codes/:code_id", promoH.Delete)
+		api.GET("/events/:id/checkin/manifest", checkinH.Manifest)
+		api.POST("/events/:id/checkin/sync", checkinH.Sync)
+		api.GET("/events/:id/checkins", checkinH.EventHistory)
+		api.POST("/events/:id/devices", checkinH.RegisterDevice)
+		api.GET("/events/:id/devices", checkinH.ListDevices)
+		api.DELETE("/events/:id/devices/:device_id", checkinH.RevokeDevice)
 
 		api.POST("/venues", venueH.CreateVenue)
 		api.GET("/venues", venueH.ListVenues)
@@ -85,21 +210,48 @@ func NewRouter(deps Dependencies) *gin.Engine {
 		api.POST("/venues/:id/rooms", venueH.CreateRoom)
 		api.GET("/venues/:id/rooms", venueH.ListRooms)
 
-		api.POST("/tickets/purchase", ticketH.Purchase)
+		api.POST("/tickets/purchase", idem, ticketH.Purchase)
 		api.GET("/tickets/:id", ticketH.Get)
-		api.GET("/tickets", ticketH.ListByBuyer)
+		api.GET("/tickets", ticketH.ListByHolder)
 		api.PATCH("/tickets/:id/status", ticketH.UpdateStatus)
 		api.DELETE("/tickets/:id", ticketH.Delete)
-		api.POST("/tickets/:id/validate", ticketH.Validate)
+		api.POST("/tickets/validate", ticketH.Validate)
+		api.GET("/tickets/:id/checkins", checkinH.TicketHistory)
+		api.POST("/tickets/:id/refund", idem, refundH.Refund)
+		api.GET("/tickets/:id/refunds", refundH.List)
+		api.POST("/tickets/:id/transfers", transferH.Initiate)
+		api.GET("/tickets/:id/transfers", transferH.ListByTicket)
+
+		api.GET("/transfers", transferH.ListIncoming)
+		api.POST("/transfers/:id/accept", transferH.Accept)
+		api.DELETE("/transfers/:id", transferH.Cancel)
+
+		api.POST("/orders", idem, orderH.Place)
+		api.GET("/orders", orderH.List)
+		api.GET("/orders/:id", orderH.Get)
+
+		api.POST("/holds", idem, holdH.Create)
+		api.GET("/holds/:id", holdH.Get)
+		api.DELETE("/holds/:id", holdH.Release)
+		api.POST("/holds/:id/confirm", idem, holdH.Confirm)
+
+		api.POST("/waitlist", waitlistH.Join)
+		api.GET("/waitlist", waitlistH.ListMine)
+		api.GET("/waitlist/:id", waitlistH.Get)
+		api.DELETE("/waitlist/:id", waitlistH.Leave)
+
+		api.POST("/webhooks/payments", paymentH.Webhook)
 
 		api.GET("/reports/sales", reportH.Sales)
 		api.GET("/reports/attendance", reportH.Attendance)
+		api.GET("/reports/flagged-purchases", reportH.FlaggedPurchases)
+		api.GET("/reports/waitlist", reportH.Waitlist)
 		api.GET("/analytics/popular-events", reportH.Popular)
 
-		api.POST("/batch/import/users", batchH.ImportUsers)
-		api.POST("/batch/import/events", batchH.ImportEvents)
-		api.POST("/batch/import/tickets", batchH.ImportTickets)
+		api.POST("/batch/import/users", idem, batchH.ImportUsers)
+		api.POST("/batch/import/events", idem, batchH.ImportEvents)
+		api.POST("/batch/import/tickets", idem, batchH.ImportTickets)
 	}
 
-	return r
+	return r, Services{Payments: paymentProc, Waitlist: waitlistUC}
 }
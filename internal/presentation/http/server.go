@@ -7,15 +7,16 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 
+	"time2meet/internal/application/port/payment"
 	"time2meet/internal/infrastructure/config"
-	"time2meet/internal/presentation/http/middleware"
 
 	"go.uber.org/zap"
 )
 
-func NewServer(cfg config.HTTPConfig, db *sqlx.DB, log *zap.Logger) *http.Server {
-	r := NewRouter(Dependencies{DB: db, Log: log})
-	r.Use(middleware.ContextFromHeaders())
+// NewServer serves the API. The returned services are for the background workers: the
+// payment reconciler must share gw with the API to see the same provider state.
+func NewServer(cfg config.Config, db *sqlx.DB, gw payment.Gateway, log *zap.Logger) (*http.Server, Services) {
+	r, services := NewRouter(Dependencies{Config: cfg, DB: db, PaymentGateway: gw, Log: log})
 
 	api := r.Group("/api/v1")
 	api.GET("/healthz", func(c *gin.Context) {
@@ -32,10 +33,14 @@ func NewServer(cfg config.HTTPConfig, db *sqlx.DB, log *zap.Logger) *http.Server
 		c.JSON(http.StatusOK, resp{Status: "ok"})
 	})
 
+	for _, key := range RoutePolicy.Missing(r.Routes()) {
+		log.Error("route has no access policy and will always be denied", zap.String("route", key))
+	}
+
 	s := &http.Server{
-		Addr:              cfg.Addr,
+		Addr:              cfg.HTTP.Addr,
 		Handler:           r,
 		ReadHeaderTimeout: 5 * time.Second,
 	}
-	return s
+	return s, services
 }
@@ -0,0 +1,9 @@
+ALTER TABLE tickets
+    DROP CONSTRAINT IF EXISTS tickets_used_by_fk,
+    DROP CONSTRAINT IF EXISTS tickets_entry_count_chk,
+    DROP COLUMN IF EXISTS entry_count,
+    DROP COLUMN IF EXISTS used_by;
+
+ALTER TABLE ticket_types
+    DROP CONSTRAINT IF EXISTS ticket_types_reentry_limit_chk,
+    DROP COLUMN IF EXISTS reentry_limit;
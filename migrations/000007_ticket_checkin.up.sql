@@ -0,0 +1,15 @@
+-- Re-entry policy per ticket type: number of additional entries allowed after the first scan
+ALTER TABLE ticket_types
+    ADD COLUMN IF NOT EXISTS reentry_limit INT NOT NULL DEFAULT 0,
+    ADD CONSTRAINT ticket_types_reentry_limit_chk CHECK (reentry_limit >= 0);
+
+-- Check-in tracking: who scanned the ticket first and how many times it was admitted
+ALTER TABLE tickets
+    ADD COLUMN IF NOT EXISTS used_by UUID,
+    ADD COLUMN IF NOT EXISTS entry_count INT NOT NULL DEFAULT 0,
+    ADD CONSTRAINT tickets_entry_count_chk CHECK (entry_count >= 0),
+    ADD CONSTRAINT tickets_used_by_fk
+        FOREIGN KEY (used_by) REFERENCES users(id)
+        ON UPDATE CASCADE ON DELETE SET NULL;
+
+UPDATE tickets SET entry_count = 1 WHERE status = 'used' AND entry_count = 0;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_checkins_event;
+DROP INDEX IF EXISTS idx_checkins_ticket;
+DROP INDEX IF EXISTS idx_checkin_devices_event;
+DROP TABLE IF EXISTS checkins CASCADE;
+DROP TABLE IF EXISTS checkin_devices CASCADE;
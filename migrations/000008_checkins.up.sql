@@ -0,0 +1,51 @@
+-- Scanner devices registered per event. Only a hash of the device secret is stored.
+CREATE TABLE IF NOT EXISTS checkin_devices (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id        UUID NOT NULL,
+    name            TEXT NOT NULL,
+    gate            TEXT,
+    secret_hash     TEXT NOT NULL,
+    created_by      UUID,
+    last_seen_at    TIMESTAMPTZ,
+    revoked_at      TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT checkin_devices_event_fk
+        FOREIGN KEY (event_id) REFERENCES events(id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT checkin_devices_created_by_fk
+        FOREIGN KEY (created_by) REFERENCES users(id)
+        ON UPDATE CASCADE ON DELETE SET NULL,
+    CONSTRAINT checkin_devices_event_name_uniq UNIQUE (event_id, name)
+);
+
+-- Every scan attempt, successful or not
+CREATE TABLE IF NOT EXISTS checkins (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id        UUID,
+    ticket_id       UUID,
+    device_id       UUID,
+    staff_id        UUID,
+    gate            TEXT,
+    result          TEXT NOT NULL,
+    message         TEXT,
+    offline         BOOLEAN NOT NULL DEFAULT FALSE,
+    scanned_at      TIMESTAMPTZ NOT NULL,
+    recorded_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT checkins_result_chk CHECK (result IN ('admitted', 'duplicate', 'invalid', 'wrong_event', 'rejected')),
+    CONSTRAINT checkins_event_fk
+        FOREIGN KEY (event_id) REFERENCES events(id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT checkins_ticket_fk
+        FOREIGN KEY (ticket_id) REFERENCES tickets(id)
+        ON UPDATE CASCADE ON DELETE SET NULL,
+    CONSTRAINT checkins_device_fk
+        FOREIGN KEY (device_id) REFERENCES checkin_devices(id)
+        ON UPDATE CASCADE ON DELETE SET NULL,
+    CONSTRAINT checkins_staff_fk
+        FOREIGN KEY (staff_id) REFERENCES users(id)
+        ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_checkin_devices_event ON checkin_devices(event_id);
+CREATE INDEX IF NOT EXISTS idx_checkins_ticket ON checkins(ticket_id, scanned_at DESC);
+CREATE INDEX IF NOT EXISTS idx_checkins_event ON checkins(event_id, scanned_at DESC);
@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS trg_audit_orders ON orders;
+DROP TRIGGER IF EXISTS trg_orders_updated_at ON orders;
+DROP INDEX IF EXISTS idx_tickets_order;
+DROP INDEX IF EXISTS idx_orders_buyer;
+ALTER TABLE tickets
+    DROP CONSTRAINT IF EXISTS tickets_order_fk,
+    DROP COLUMN IF EXISTS order_id;
+DROP TABLE IF EXISTS order_items CASCADE;
+DROP TABLE IF EXISTS orders CASCADE;
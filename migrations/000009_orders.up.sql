@@ -0,0 +1,55 @@
+-- Orders group the tickets bought together in one checkout
+CREATE TABLE IF NOT EXISTS orders (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    buyer_id        UUID NOT NULL,
+    status          TEXT NOT NULL,
+    currency        CHAR(3) NOT NULL,
+    total_amount    NUMERIC(12,2) NOT NULL,
+    ticket_count    INT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT orders_status_chk CHECK (status IN ('paid')),
+    CONSTRAINT orders_total_amount_chk CHECK (total_amount >= 0),
+    CONSTRAINT orders_ticket_count_chk CHECK (ticket_count > 0),
+    CONSTRAINT orders_buyer_fk
+        FOREIGN KEY (buyer_id) REFERENCES users(id)
+        ON UPDATE CASCADE ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id        UUID NOT NULL,
+    ticket_type_id  UUID NOT NULL,
+    quantity        INT NOT NULL,
+    unit_price      NUMERIC(12,2) NOT NULL,
+    line_total      NUMERIC(12,2) NOT NULL,
+    CONSTRAINT order_items_quantity_chk CHECK (quantity > 0),
+    CONSTRAINT order_items_amounts_chk CHECK (unit_price >= 0 AND line_total >= 0),
+    CONSTRAINT order_items_order_fk
+        FOREIGN KEY (order_id) REFERENCES orders(id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT order_items_type_fk
+        FOREIGN KEY (ticket_type_id) REFERENCES ticket_types(id)
+        ON UPDATE CASCADE ON DELETE RESTRICT,
+    CONSTRAINT order_items_order_type_uniq UNIQUE (order_id, ticket_type_id)
+);
+
+-- Tickets issued before orders existed keep order_id NULL
+ALTER TABLE tickets
+    ADD COLUMN IF NOT EXISTS order_id UUID,
+    ADD CONSTRAINT tickets_order_fk
+        FOREIGN KEY (order_id) REFERENCES orders(id)
+        ON UPDATE CASCADE ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_orders_buyer ON orders(buyer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_tickets_order ON tickets(order_id);
+
+DROP TRIGGER IF EXISTS trg_orders_updated_at ON orders;
+CREATE TRIGGER trg_orders_updated_at
+BEFORE UPDATE ON orders
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+DROP TRIGGER IF EXISTS trg_audit_orders ON orders;
+CREATE TRIGGER trg_audit_orders
+AFTER INSERT OR UPDATE OR DELETE ON orders
+FOR EACH ROW EXECUTE FUNCTION audit_trigger_func();
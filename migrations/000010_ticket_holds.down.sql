@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS trg_ticket_holds_updated_at ON ticket_holds;
+DROP TRIGGER IF EXISTS trg_update_ticket_holds ON ticket_holds;
+DROP FUNCTION IF EXISTS update_ticket_holds();
+DROP INDEX IF EXISTS idx_ticket_holds_user;
+DROP INDEX IF EXISTS idx_ticket_holds_due;
+DROP TABLE IF EXISTS ticket_holds CASCADE;
+ALTER TABLE ticket_types
+    DROP CONSTRAINT IF EXISTS ticket_types_qty_held_chk,
+    DROP COLUMN IF EXISTS quantity_held;
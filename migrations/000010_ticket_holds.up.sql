@@ -0,0 +1,81 @@
+-- Checkout holds: inventory reserved for a user until the purchase is confirmed or the hold expires.
+-- quantity_held mirrors the active holds and, like quantity_sold, is maintained by a trigger.
+ALTER TABLE ticket_types
+    ADD COLUMN IF NOT EXISTS quantity_held INT NOT NULL DEFAULT 0,
+    ADD CONSTRAINT ticket_types_qty_held_chk CHECK (
+        quantity_held >= 0 AND quantity_sold + quantity_held <= quantity_total
+    );
+
+CREATE TABLE IF NOT EXISTS ticket_holds (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    ticket_type_id  UUID NOT NULL,
+    user_id         UUID NOT NULL,
+    quantity        INT NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'active',
+    expires_at      TIMESTAMPTZ NOT NULL,
+    order_id        UUID,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT ticket_holds_quantity_chk CHECK (quantity > 0),
+    CONSTRAINT ticket_holds_status_chk CHECK (status IN ('active', 'converted', 'expired', 'released')),
+    CONSTRAINT ticket_holds_type_fk
+        FOREIGN KEY (ticket_type_id) REFERENCES ticket_types(id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT ticket_holds_user_fk
+        FOREIGN KEY (user_id) REFERENCES users(id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    -- Deferred: a hold is marked converted before its order row is written in the same transaction.
+    CONSTRAINT ticket_holds_order_fk
+        FOREIGN KEY (order_id) REFERENCES orders(id)
+        ON UPDATE CASCADE ON DELETE SET NULL
+        DEFERRABLE INITIALLY DEFERRED
+);
+
+CREATE INDEX IF NOT EXISTS idx_ticket_holds_due ON ticket_holds(expires_at) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_ticket_holds_user ON ticket_holds(user_id, created_at DESC);
+
+CREATE OR REPLACE FUNCTION update_ticket_holds()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF (TG_OP = 'INSERT') THEN
+    IF NEW.status = 'active' THEN
+      UPDATE ticket_types
+      SET quantity_held = quantity_held + NEW.quantity,
+          updated_at = NOW()
+      WHERE id = NEW.ticket_type_id;
+    END IF;
+    RETURN NEW;
+  ELSIF (TG_OP = 'DELETE') THEN
+    IF OLD.status = 'active' THEN
+      UPDATE ticket_types
+      SET quantity_held = GREATEST(quantity_held - OLD.quantity, 0),
+          updated_at = NOW()
+      WHERE id = OLD.ticket_type_id;
+    END IF;
+    RETURN OLD;
+  ELSE
+    IF OLD.status = 'active' AND NEW.status <> 'active' THEN
+      UPDATE ticket_types
+      SET quantity_held = GREATEST(quantity_held - OLD.quantity, 0),
+          updated_at = NOW()
+      WHERE id = OLD.ticket_type_id;
+    ELSIF OLD.status <> 'active' AND NEW.status = 'active' THEN
+      UPDATE ticket_types
+      SET quantity_held = quantity_held + NEW.quantity,
+          updated_at = NOW()
+      WHERE id = NEW.ticket_type_id;
+    END IF;
+    RETURN NEW;
+  END IF;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_update_ticket_holds ON ticket_holds;
+CREATE TRIGGER trg_update_ticket_holds
+AFTER INSERT OR UPDATE OR DELETE ON ticket_holds
+FOR EACH ROW EXECUTE FUNCTION update_ticket_holds();
+
+DROP TRIGGER IF EXISTS trg_ticket_holds_updated_at ON ticket_holds;
+CREATE TRIGGER trg_ticket_holds_updated_at
+BEFORE UPDATE ON ticket_holds
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
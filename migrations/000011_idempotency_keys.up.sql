@@ -0,0 +1,21 @@
+-- Idempotency-Key records: the first request with a key claims it, retries replay the stored response
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id         UUID NOT NULL,
+    key             TEXT NOT NULL,
+    fingerprint     TEXT NOT NULL,
+    completed       BOOLEAN NOT NULL DEFAULT FALSE,
+    status_code     INT,
+    content_type    TEXT,
+    body            BYTEA,
+    -- an uncompleted claim older than locked_until is treated as abandoned (crashed request)
+    locked_until    TIMESTAMPTZ NOT NULL,
+    expires_at      TIMESTAMPTZ NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, key),
+    CONSTRAINT idempotency_keys_completed_chk CHECK (NOT completed OR status_code IS NOT NULL),
+    CONSTRAINT idempotency_keys_user_fk
+        FOREIGN KEY (user_id) REFERENCES users(id)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
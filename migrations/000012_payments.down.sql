@@ -0,0 +1,65 @@
+DROP TRIGGER IF EXISTS trg_audit_payments ON payments;
+DROP TRIGGER IF EXISTS trg_payments_updated_at ON payments;
+DROP INDEX IF EXISTS idx_payments_pending;
+DROP INDEX IF EXISTS idx_payments_order;
+DROP TABLE IF EXISTS payments CASCADE;
+
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_chk;
+ALTER TABLE orders ADD CONSTRAINT orders_status_chk CHECK (status IN ('paid'));
+
+ALTER TABLE tickets DROP CONSTRAINT IF EXISTS tickets_status_chk;
+ALTER TABLE tickets ADD CONSTRAINT tickets_status_chk CHECK (status IN ('paid', 'refunded', 'void', 'used'));
+
+-- Ticket sales aggregation trigger
+CREATE OR REPLACE FUNCTION update_ticket_sales()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF (TG_OP = 'INSERT') THEN
+    IF NEW.status IN ('paid', 'used') THEN
+      UPDATE ticket_types
+      SET quantity_sold = quantity_sold + 1,
+          updated_at = NOW()
+      WHERE id = NEW.ticket_type_id;
+    END IF;
+    RETURN NEW;
+  ELSIF (TG_OP = 'DELETE') THEN
+    IF OLD.status IN ('paid', 'used') THEN
+      UPDATE ticket_types
+      SET quantity_sold = GREATEST(quantity_sold - 1, 0),
+          updated_at = NOW()
+      WHERE id = OLD.ticket_type_id;
+    END IF;
+    RETURN OLD;
+  ELSE
+    -- UPDATE
+    IF OLD.ticket_type_id <> NEW.ticket_type_id THEN
+      IF OLD.status IN ('paid', 'used') THEN
+        UPDATE ticket_types
+        SET quantity_sold = GREATEST(quantity_sold - 1, 0),
+            updated_at = NOW()
+        WHERE id = OLD.ticket_type_id;
+      END IF;
+      IF NEW.status IN ('paid', 'used') THEN
+        UPDATE ticket_types
+        SET quantity_sold = quantity_sold + 1,
+            updated_at = NOW()
+        WHERE id = NEW.ticket_type_id;
+      END IF;
+      RETURN NEW;
+    END IF;
+
+    IF OLD.status NOT IN ('paid', 'used') AND NEW.status IN ('paid', 'used') THEN
+      UPDATE ticket_types
+      SET quantity_sold = quantity_sold + 1,
+          updated_at = NOW()
+      WHERE id = NEW.ticket_type_id;
+    ELSIF OLD.status IN ('paid', 'used') AND NEW.status NOT IN ('paid', 'used') THEN
+      UPDATE ticket_types
+      SET quantity_sold = GREATEST(quantity_sold - 1, 0),
+          updated_at = NOW()
+      WHERE id = NEW.ticket_type_id;
+    END IF;
+    RETURN NEW;
+  END IF;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,93 @@
+-- Payments: orders and their tickets wait in pending_payment until the gateway captures the charge
+ALTER TABLE tickets DROP CONSTRAINT IF EXISTS tickets_status_chk;
+ALTER TABLE tickets ADD CONSTRAINT tickets_status_chk
+    CHECK (status IN ('pending_payment', 'paid', 'refunded', 'void', 'used'));
+
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_chk;
+ALTER TABLE orders ADD CONSTRAINT orders_status_chk
+    CHECK (status IN ('pending_payment', 'paid', 'payment_failed'));
+
+CREATE TABLE IF NOT EXISTS payments (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id        UUID NOT NULL,
+    provider        TEXT NOT NULL,
+    -- NULL until the gateway has accepted the intent
+    intent_id       TEXT,
+    status          TEXT NOT NULL DEFAULT 'pending',
+    amount          NUMERIC(12,2) NOT NULL,
+    currency        CHAR(3) NOT NULL,
+    failure_reason  TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT payments_status_chk CHECK (status IN ('pending', 'succeeded', 'failed')),
+    CONSTRAINT payments_amount_chk CHECK (amount >= 0),
+    CONSTRAINT payments_order_fk
+        FOREIGN KEY (order_id) REFERENCES orders(id)
+        ON UPDATE CASCADE ON DELETE RESTRICT,
+    CONSTRAINT payments_intent_uniq UNIQUE (provider, intent_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payments_order ON payments(order_id);
+CREATE INDEX IF NOT EXISTS idx_payments_pending ON payments(created_at) WHERE status = 'pending';
+
+DROP TRIGGER IF EXISTS trg_payments_updated_at ON payments;
+CREATE TRIGGER trg_payments_updated_at
+BEFORE UPDATE ON payments
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+DROP TRIGGER IF EXISTS trg_audit_payments ON payments;
+CREATE TRIGGER trg_audit_payments
+AFTER INSERT OR UPDATE OR DELETE ON payments
+FOR EACH ROW EXECUTE FUNCTION audit_trigger_func();
+
+-- Tickets awaiting payment reserve capacity through quantity_held, paid and used ones count
+-- as sold. Both counters are moved in one UPDATE so that the pending -> paid transition never
+-- trips ticket_types_qty_held_chk.
+CREATE OR REPLACE FUNCTION update_ticket_sales()
+RETURNS TRIGGER AS $$
+DECLARE
+  old_sold INT := 0;
+  old_held INT := 0;
+  new_sold INT := 0;
+  new_held INT := 0;
+BEGIN
+  IF (TG_OP IN ('UPDATE', 'DELETE')) THEN
+    old_sold := CASE WHEN OLD.status IN ('paid', 'used') THEN 1 ELSE 0 END;
+    old_held := CASE WHEN OLD.status = 'pending_payment' THEN 1 ELSE 0 END;
+  END IF;
+  IF (TG_OP IN ('INSERT', 'UPDATE')) THEN
+    new_sold := CASE WHEN NEW.status IN ('paid', 'used') THEN 1 ELSE 0 END;
+    new_held := CASE WHEN NEW.status = 'pending_payment' THEN 1 ELSE 0 END;
+  END IF;
+
+  IF (TG_OP = 'UPDATE' AND OLD.ticket_type_id <> NEW.ticket_type_id) THEN
+    IF old_sold + old_held > 0 THEN
+      UPDATE ticket_types
+      SET quantity_sold = GREATEST(quantity_sold - old_sold, 0),
+          quantity_held = GREATEST(quantity_held - old_held, 0),
+          updated_at = NOW()
+      WHERE id = OLD.ticket_type_id;
+    END IF;
+    IF new_sold + new_held > 0 THEN
+      UPDATE ticket_types
+      SET quantity_sold = quantity_sold + new_sold,
+          quantity_held = quantity_held + new_held,
+          updated_at = NOW()
+      WHERE id = NEW.ticket_type_id;
+    END IF;
+    RETURN NEW;
+  END IF;
+
+  IF new_sold <> old_sold OR new_held <> old_held THEN
+    UPDATE ticket_types
+    SET quantity_sold = GREATEST(quantity_sold + new_sold - old_sold, 0),
+        quantity_held = GREATEST(quantity_held + new_held - old_held, 0),
+        updated_at = NOW()
+    WHERE id = CASE WHEN TG_OP = 'DELETE' THEN OLD.ticket_type_id ELSE NEW.ticket_type_id END;
+  END IF;
+  IF (TG_OP = 'DELETE') THEN
+    RETURN OLD;
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
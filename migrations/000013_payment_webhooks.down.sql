@@ -0,0 +1,10 @@
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS payments_status_chk;
+ALTER TABLE payments ADD CONSTRAINT payments_status_chk
+    CHECK (status IN ('pending', 'succeeded', 'failed'));
+
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_chk;
+ALTER TABLE orders ADD CONSTRAINT orders_status_chk
+    CHECK (status IN ('pending_payment', 'paid', 'payment_failed'));
+
+DROP INDEX IF EXISTS idx_payment_webhook_events_intent;
+DROP TABLE IF EXISTS payment_webhook_events;
@@ -0,0 +1,20 @@
+-- Provider webhook events, kept to de-duplicate redeliveries by the provider's event id
+CREATE TABLE IF NOT EXISTS payment_webhook_events (
+    provider        TEXT NOT NULL,
+    event_id        TEXT NOT NULL,
+    event_type      TEXT NOT NULL,
+    intent_id       TEXT NOT NULL,
+    received_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_webhook_events_intent ON payment_webhook_events(provider, intent_id);
+
+-- Refunds reported by the provider move paid orders and payments to refunded
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_chk;
+ALTER TABLE orders ADD CONSTRAINT orders_status_chk
+    CHECK (status IN ('pending_payment', 'paid', 'payment_failed', 'refunded'));
+
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS payments_status_chk;
+ALTER TABLE payments ADD CONSTRAINT payments_status_chk
+    CHECK (status IN ('pending', 'succeeded', 'failed', 'refunded'));
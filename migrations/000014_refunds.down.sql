@@ -0,0 +1,14 @@
+DROP TRIGGER IF EXISTS trg_audit_refunds ON refunds;
+DROP TRIGGER IF EXISTS trg_refunds_updated_at ON refunds;
+DROP INDEX IF EXISTS idx_refunds_pending;
+DROP INDEX IF EXISTS idx_refunds_payment;
+DROP INDEX IF EXISTS uniq_refunds_ticket_open;
+DROP TABLE IF EXISTS refunds;
+
+ALTER TABLE ticket_types
+    DROP CONSTRAINT IF EXISTS ticket_types_refund_percent_chk,
+    DROP CONSTRAINT IF EXISTS ticket_types_refund_deadline_chk,
+    DROP CONSTRAINT IF EXISTS ticket_types_refund_policy_chk,
+    DROP COLUMN IF EXISTS refund_percent,
+    DROP COLUMN IF EXISTS refund_deadline_days,
+    DROP COLUMN IF EXISTS refund_policy;
@@ -0,0 +1,57 @@
+-- Refund policies per ticket type. Existing types keep today's behaviour: a full refund up to the event.
+ALTER TABLE ticket_types
+    ADD COLUMN IF NOT EXISTS refund_policy TEXT NOT NULL DEFAULT 'full',
+    ADD COLUMN IF NOT EXISTS refund_deadline_days INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS refund_percent INT NOT NULL DEFAULT 100,
+    ADD CONSTRAINT ticket_types_refund_policy_chk CHECK (refund_policy IN ('full', 'partial', 'none')),
+    ADD CONSTRAINT ticket_types_refund_deadline_chk CHECK (refund_deadline_days >= 0),
+    ADD CONSTRAINT ticket_types_refund_percent_chk CHECK (refund_percent BETWEEN 0 AND 100);
+
+-- Refund ledger: one row per attempt to return money for a ticket. The ticket stays paid, but
+-- cannot be scanned or transferred, while its refund is pending; it moves to refunded once the
+-- provider confirms, and update_ticket_sales then returns it to the pool.
+CREATE TABLE IF NOT EXISTS refunds (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    ticket_id           UUID NOT NULL,
+    order_id            UUID,
+    -- NULL when the ticket was not charged through a provider
+    payment_id          UUID,
+    amount              NUMERIC(12,2) NOT NULL,
+    currency            CHAR(3) NOT NULL,
+    status              TEXT NOT NULL DEFAULT 'pending',
+    provider_refund_id  TEXT,
+    reason              TEXT,
+    failure_reason      TEXT,
+    requested_by        UUID,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT refunds_status_chk CHECK (status IN ('pending', 'succeeded', 'failed')),
+    CONSTRAINT refunds_amount_chk CHECK (amount >= 0),
+    CONSTRAINT refunds_ticket_fk
+        FOREIGN KEY (ticket_id) REFERENCES tickets(id)
+        ON UPDATE CASCADE ON DELETE RESTRICT,
+    CONSTRAINT refunds_order_fk
+        FOREIGN KEY (order_id) REFERENCES orders(id)
+        ON UPDATE CASCADE ON DELETE RESTRICT,
+    CONSTRAINT refunds_payment_fk
+        FOREIGN KEY (payment_id) REFERENCES payments(id)
+        ON UPDATE CASCADE ON DELETE RESTRICT,
+    CONSTRAINT refunds_requested_by_fk
+        FOREIGN KEY (requested_by) REFERENCES users(id)
+        ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+-- At most one refund per ticket may be in flight or done; failed attempts can be retried.
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_refunds_ticket_open ON refunds(ticket_id) WHERE status IN ('pending', 'succeeded');
+CREATE INDEX IF NOT EXISTS idx_refunds_payment ON refunds(payment_id);
+CREATE INDEX IF NOT EXISTS idx_refunds_pending ON refunds(created_at) WHERE status = 'pending';
+
+DROP TRIGGER IF EXISTS trg_refunds_updated_at ON refunds;
+CREATE TRIGGER trg_refunds_updated_at
+BEFORE UPDATE ON refunds
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+DROP TRIGGER IF EXISTS trg_audit_refunds ON refunds;
+CREATE TRIGGER trg_audit_refunds
+AFTER INSERT OR UPDATE OR DELETE ON refunds
+FOR EACH ROW EXECUTE FUNCTION audit_trigger_func();
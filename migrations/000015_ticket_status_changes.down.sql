@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_ticket_status_changes_ticket;
+DROP TABLE IF EXISTS ticket_status_changes;
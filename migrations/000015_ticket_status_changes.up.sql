@@ -0,0 +1,19 @@
+-- Manual ticket status changes, with the reason given and the user who made them
+CREATE TABLE IF NOT EXISTS ticket_status_changes (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    ticket_id       UUID NOT NULL,
+    from_status     TEXT NOT NULL,
+    to_status       TEXT NOT NULL,
+    reason          TEXT NOT NULL,
+    changed_by      UUID,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT ticket_status_changes_reason_chk CHECK (reason <> ''),
+    CONSTRAINT ticket_status_changes_ticket_fk
+        FOREIGN KEY (ticket_id) REFERENCES tickets(id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT ticket_status_changes_changed_by_fk
+        FOREIGN KEY (changed_by) REFERENCES users(id)
+        ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_ticket_status_changes_ticket ON ticket_status_changes(ticket_id, created_at);
@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS trg_audit_ticket_transfers ON ticket_transfers;
+DROP TRIGGER IF EXISTS trg_ticket_transfers_updated_at ON ticket_transfers;
+DROP INDEX IF EXISTS idx_ticket_transfers_expiry;
+DROP INDEX IF EXISTS idx_ticket_transfers_ticket;
+DROP INDEX IF EXISTS uniq_ticket_transfers_pending;
+DROP TABLE IF EXISTS ticket_transfers;
+
+DROP INDEX IF EXISTS idx_tickets_holder;
+ALTER TABLE tickets
+    DROP CONSTRAINT IF EXISTS tickets_holder_fk,
+    DROP COLUMN IF EXISTS holder_id;
@@ -0,0 +1,48 @@
+-- Ticket transfers. buyer_id stays the original purchaser (sales reports count buyers);
+-- holder_id is whoever holds the ticket now and is NULL until the first accepted transfer.
+ALTER TABLE tickets
+    ADD COLUMN IF NOT EXISTS holder_id UUID,
+    ADD CONSTRAINT tickets_holder_fk
+        FOREIGN KEY (holder_id) REFERENCES users(id)
+        ON UPDATE CASCADE ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_tickets_holder ON tickets ((COALESCE(holder_id, buyer_id)));
+
+CREATE TABLE IF NOT EXISTS ticket_transfers (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    ticket_id       UUID NOT NULL,
+    from_user_id    UUID NOT NULL,
+    to_email        TEXT NOT NULL,
+    -- set when the recipient accepts
+    to_user_id      UUID,
+    status          TEXT NOT NULL DEFAULT 'pending',
+    expires_at      TIMESTAMPTZ NOT NULL,
+    accepted_at     TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT ticket_transfers_status_chk CHECK (status IN ('pending', 'accepted', 'cancelled', 'expired')),
+    CONSTRAINT ticket_transfers_ticket_fk
+        FOREIGN KEY (ticket_id) REFERENCES tickets(id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT ticket_transfers_from_fk
+        FOREIGN KEY (from_user_id) REFERENCES users(id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT ticket_transfers_to_fk
+        FOREIGN KEY (to_user_id) REFERENCES users(id)
+        ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+-- A ticket can be offered to one recipient at a time.
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_ticket_transfers_pending ON ticket_transfers(ticket_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_ticket_transfers_ticket ON ticket_transfers(ticket_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_ticket_transfers_expiry ON ticket_transfers(expires_at) WHERE status = 'pending';
+
+DROP TRIGGER IF EXISTS trg_ticket_transfers_updated_at ON ticket_transfers;
+CREATE TRIGGER trg_ticket_transfers_updated_at
+BEFORE UPDATE ON ticket_transfers
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+DROP TRIGGER IF EXISTS trg_audit_ticket_transfers ON ticket_transfers;
+CREATE TRIGGER trg_audit_ticket_transfers
+AFTER INSERT OR UPDATE OR DELETE ON ticket_transfers
+FOR EACH ROW EXECUTE FUNCTION audit_trigger_func();
@@ -0,0 +1,44 @@
+DROP FUNCTION IF EXISTS get_sales_report(DATE, DATE);
+CREATE OR REPLACE FUNCTION get_sales_report(p_start DATE, p_end DATE)
+RETURNS TABLE(
+  event_id UUID,
+  event_title TEXT,
+  tickets_sold BIGINT,
+  revenue NUMERIC(14,2),
+  unique_buyers BIGINT
+)
+LANGUAGE sql
+STABLE
+AS $$
+  SELECT
+    e.id,
+    e.title,
+    COUNT(t.id) FILTER (WHERE t.status IN ('paid','used')) AS tickets_sold,
+    COALESCE(SUM(t.amount_paid) FILTER (WHERE t.status IN ('paid','used')), 0)::NUMERIC(14,2) AS revenue,
+    COUNT(DISTINCT t.buyer_id) FILTER (WHERE t.status IN ('paid','used')) AS unique_buyers
+  FROM events e
+  LEFT JOIN ticket_types tt ON tt.event_id = e.id
+  LEFT JOIN tickets t ON t.ticket_type_id = tt.id
+               AND t.purchase_date >= p_start::timestamptz
+               AND t.purchase_date < (p_end + 1)::timestamptz
+  GROUP BY e.id, e.title
+  ORDER BY revenue DESC;
+$$;
+
+ALTER TABLE tickets DROP COLUMN IF EXISTS discount_amount;
+ALTER TABLE order_items DROP COLUMN IF EXISTS discount_amount;
+ALTER TABLE orders
+    DROP CONSTRAINT IF EXISTS orders_promo_code_fk,
+    DROP COLUMN IF EXISTS discount_amount,
+    DROP COLUMN IF EXISTS promo_code_id;
+
+DROP TRIGGER IF EXISTS trg_audit_promo_redemptions ON promo_redemptions;
+DROP TRIGGER IF EXISTS trg_audit_promo_codes ON promo_codes;
+DROP TRIGGER IF EXISTS trg_promo_redemptions_updated_at ON promo_redemptions;
+DROP TRIGGER IF EXISTS trg_promo_codes_updated_at ON promo_codes;
+DROP INDEX IF EXISTS idx_promo_redemptions_code_user;
+DROP TABLE IF EXISTS promo_redemptions;
+DROP TABLE IF EXISTS promo_code_ticket_types;
+DROP INDEX IF EXISTS idx_promo_codes_event;
+DROP INDEX IF EXISTS uniq_promo_codes_code;
+DROP TABLE IF EXISTS promo_codes;
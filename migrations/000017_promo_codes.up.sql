@@ -0,0 +1,143 @@
+-- Promo codes: a percentage or fixed amount taken off each ticket of the event (optionally
+-- only of some ticket types) while the code is active, inside its window and under its caps.
+CREATE TABLE IF NOT EXISTS promo_codes (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id            UUID NOT NULL,
+    code                TEXT NOT NULL,
+    discount_type       TEXT NOT NULL,
+    -- percent (1-100) or an amount in currency, per ticket
+    discount_value      NUMERIC(12,2) NOT NULL,
+    -- fixed discounts only apply to ticket types priced in this currency
+    currency            CHAR(3),
+    -- NULL means unlimited; both caps count orders, not tickets
+    max_uses            INT,
+    max_uses_per_user   INT,
+    used_count          INT NOT NULL DEFAULT 0,
+    valid_from          TIMESTAMPTZ,
+    valid_until         TIMESTAMPTZ,
+    is_active           BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by          UUID,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT promo_codes_discount_type_chk CHECK (discount_type IN ('percent', 'fixed')),
+    CONSTRAINT promo_codes_discount_value_chk CHECK (
+        discount_value > 0 AND (discount_type <> 'percent' OR discount_value <= 100)),
+    CONSTRAINT promo_codes_currency_chk CHECK ((discount_type = 'fixed') = (currency IS NOT NULL)),
+    CONSTRAINT promo_codes_caps_chk CHECK (
+        (max_uses IS NULL OR max_uses > 0) AND (max_uses_per_user IS NULL OR max_uses_per_user > 0)),
+    CONSTRAINT promo_codes_used_count_chk CHECK (used_count >= 0),
+    CONSTRAINT promo_codes_window_chk CHECK (valid_from IS NULL OR valid_until IS NULL OR valid_until > valid_from),
+    CONSTRAINT promo_codes_event_fk
+        FOREIGN KEY (event_id) REFERENCES events(id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT promo_codes_created_by_fk
+        FOREIGN KEY (created_by) REFERENCES users(id)
+        ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+-- Buyers type the code, so it is unique regardless of case.
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_promo_codes_code ON promo_codes (UPPER(code));
+CREATE INDEX IF NOT EXISTS idx_promo_codes_event ON promo_codes(event_id);
+
+-- Ticket types a code is limited to; none means every ticket type of the event.
+CREATE TABLE IF NOT EXISTS promo_code_ticket_types (
+    promo_code_id   UUID NOT NULL,
+    ticket_type_id  UUID NOT NULL,
+    PRIMARY KEY (promo_code_id, ticket_type_id),
+    CONSTRAINT promo_code_ticket_types_code_fk
+        FOREIGN KEY (promo_code_id) REFERENCES promo_codes(id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT promo_code_ticket_types_type_fk
+        FOREIGN KEY (ticket_type_id) REFERENCES ticket_types(id)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+-- One redemption per order. A redemption is released when the order's payment fails, which
+-- gives the use back to the code.
+CREATE TABLE IF NOT EXISTS promo_redemptions (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    promo_code_id       UUID NOT NULL,
+    order_id            UUID NOT NULL,
+    user_id             UUID NOT NULL,
+    discount_amount     NUMERIC(12,2) NOT NULL,
+    currency            CHAR(3) NOT NULL,
+    status              TEXT NOT NULL DEFAULT 'applied',
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT promo_redemptions_status_chk CHECK (status IN ('applied', 'released')),
+    CONSTRAINT promo_redemptions_amount_chk CHECK (discount_amount >= 0),
+    CONSTRAINT promo_redemptions_order_uniq UNIQUE (order_id),
+    CONSTRAINT promo_redemptions_code_fk
+        FOREIGN KEY (promo_code_id) REFERENCES promo_codes(id)
+        ON UPDATE CASCADE ON DELETE RESTRICT,
+    CONSTRAINT promo_redemptions_order_fk
+        FOREIGN KEY (order_id) REFERENCES orders(id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT promo_redemptions_user_fk
+        FOREIGN KEY (user_id) REFERENCES users(id)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_promo_redemptions_code_user ON promo_redemptions(promo_code_id, user_id) WHERE status = 'applied';
+
+-- Discounts taken off the order, each line and each ticket; amounts charged stay net.
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS promo_code_id UUID,
+    ADD COLUMN IF NOT EXISTS discount_amount NUMERIC(12,2) NOT NULL DEFAULT 0,
+    ADD CONSTRAINT orders_promo_code_fk
+        FOREIGN KEY (promo_code_id) REFERENCES promo_codes(id)
+        ON UPDATE CASCADE ON DELETE SET NULL;
+ALTER TABLE order_items
+    ADD COLUMN IF NOT EXISTS discount_amount NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE tickets
+    ADD COLUMN IF NOT EXISTS discount_amount NUMERIC(12,2) NOT NULL DEFAULT 0;
+
+DROP TRIGGER IF EXISTS trg_promo_codes_updated_at ON promo_codes;
+CREATE TRIGGER trg_promo_codes_updated_at
+BEFORE UPDATE ON promo_codes
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+DROP TRIGGER IF EXISTS trg_promo_redemptions_updated_at ON promo_redemptions;
+CREATE TRIGGER trg_promo_redemptions_updated_at
+BEFORE UPDATE ON promo_redemptions
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+DROP TRIGGER IF EXISTS trg_audit_promo_codes ON promo_codes;
+CREATE TRIGGER trg_audit_promo_codes
+AFTER INSERT OR UPDATE OR DELETE ON promo_codes
+FOR EACH ROW EXECUTE FUNCTION audit_trigger_func();
+
+DROP TRIGGER IF EXISTS trg_audit_promo_redemptions ON promo_redemptions;
+CREATE TRIGGER trg_audit_promo_redemptions
+AFTER INSERT OR UPDATE OR DELETE ON promo_redemptions
+FOR EACH ROW EXECUTE FUNCTION audit_trigger_func();
+
+-- The sales report also shows what discounts cost; revenue stays what buyers paid.
+DROP FUNCTION IF EXISTS get_sales_report(DATE, DATE);
+CREATE OR REPLACE FUNCTION get_sales_report(p_start DATE, p_end DATE)
+RETURNS TABLE(
+  event_id UUID,
+  event_title TEXT,
+  tickets_sold BIGINT,
+  revenue NUMERIC(14,2),
+  discounts NUMERIC(14,2),
+  unique_buyers BIGINT
+)
+LANGUAGE sql
+STABLE
+AS $$
+  SELECT
+    e.id,
+    e.title,
+    COUNT(t.id) FILTER (WHERE t.status IN ('paid','used')) AS tickets_sold,
+    COALESCE(SUM(t.amount_paid) FILTER (WHERE t.status IN ('paid','used')), 0)::NUMERIC(14,2) AS revenue,
+    COALESCE(SUM(t.discount_amount) FILTER (WHERE t.status IN ('paid','used')), 0)::NUMERIC(14,2) AS discounts,
+    COUNT(DISTINCT t.buyer_id) FILTER (WHERE t.status IN ('paid','used')) AS unique_buyers
+  FROM events e
+  LEFT JOIN ticket_types tt ON tt.event_id = e.id
+  LEFT JOIN tickets t ON t.ticket_type_id = tt.id
+               AND t.purchase_date >= p_start::timestamptz
+               AND t.purchase_date < (p_end + 1)::timestamptz
+  GROUP BY e.id, e.title
+  ORDER BY revenue DESC;
+$$;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_order_items_order;
+-- Fails while orders hold lines split across price tiers.
+ALTER TABLE order_items ADD CONSTRAINT order_items_order_type_uniq UNIQUE (order_id, ticket_type_id);
+
+DROP TRIGGER IF EXISTS trg_audit_ticket_price_tiers ON ticket_price_tiers;
+DROP INDEX IF EXISTS idx_ticket_price_tiers_type;
+DROP TABLE IF EXISTS ticket_price_tiers;
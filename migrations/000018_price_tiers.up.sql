@@ -0,0 +1,31 @@
+-- Price tiers: a ticket type sells at each tier's price, in position order, until the tier's
+-- quantity cap is reached or its end time passes; the ticket type's own price applies after the
+-- last tier. Caps count every ticket of the type sold or reserved, not only the tier's own.
+CREATE TABLE IF NOT EXISTS ticket_price_tiers (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    ticket_type_id  UUID NOT NULL,
+    position        INT NOT NULL,
+    price           NUMERIC(12,2) NOT NULL,
+    up_to_quantity  INT,
+    ends_at         TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT ticket_price_tiers_price_chk CHECK (price >= 0),
+    CONSTRAINT ticket_price_tiers_quantity_chk CHECK (up_to_quantity IS NULL OR up_to_quantity > 0),
+    -- a tier without limits would hide the base price and every tier after it
+    CONSTRAINT ticket_price_tiers_limit_chk CHECK (up_to_quantity IS NOT NULL OR ends_at IS NOT NULL),
+    CONSTRAINT ticket_price_tiers_type_fk
+        FOREIGN KEY (ticket_type_id) REFERENCES ticket_types(id)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+-- Not unique: tiers are replaced by deleting and inserting in one statement.
+CREATE INDEX IF NOT EXISTS idx_ticket_price_tiers_type ON ticket_price_tiers(ticket_type_id, position);
+
+DROP TRIGGER IF EXISTS trg_audit_ticket_price_tiers ON ticket_price_tiers;
+CREATE TRIGGER trg_audit_ticket_price_tiers
+AFTER INSERT OR UPDATE OR DELETE ON ticket_price_tiers
+FOR EACH ROW EXECUTE FUNCTION audit_trigger_func();
+
+-- An order line that crosses a tier's quantity cap is stored as one item per price.
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS order_items_order_type_uniq;
+CREATE INDEX IF NOT EXISTS idx_order_items_order ON order_items(order_id, ticket_type_id);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_purchase_flags_created;
+DROP TABLE IF EXISTS purchase_flags;
+
+DROP INDEX IF EXISTS idx_orders_client_ip;
+ALTER TABLE orders DROP COLUMN IF EXISTS client_ip;
+
+DROP INDEX IF EXISTS idx_tickets_buyer_type;
+ALTER TABLE ticket_types
+    DROP CONSTRAINT IF EXISTS ticket_types_purchase_limits_chk,
+    DROP COLUMN IF EXISTS max_per_user,
+    DROP COLUMN IF EXISTS max_per_order;
@@ -0,0 +1,36 @@
+-- Purchase limits per ticket type; NULL means no limit. max_per_user counts every ticket of the
+-- type a buyer has bought, is paying for or holds, across all of their orders.
+ALTER TABLE ticket_types
+    ADD COLUMN IF NOT EXISTS max_per_order INT,
+    ADD COLUMN IF NOT EXISTS max_per_user INT,
+    ADD CONSTRAINT ticket_types_purchase_limits_chk CHECK (
+        (max_per_order IS NULL OR max_per_order > 0) AND (max_per_user IS NULL OR max_per_user > 0));
+
+CREATE INDEX IF NOT EXISTS idx_tickets_buyer_type ON tickets(buyer_id, ticket_type_id);
+
+-- The client IP an order was placed from, for velocity checks. NULL for older orders.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS client_ip TEXT;
+CREATE INDEX IF NOT EXISTS idx_orders_client_ip ON orders(client_ip, created_at) WHERE client_ip IS NOT NULL;
+
+-- Orders that tripped a velocity check. Flagged orders still go through; admins review them.
+CREATE TABLE IF NOT EXISTS purchase_flags (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id        UUID NOT NULL,
+    buyer_id        UUID NOT NULL,
+    client_ip       TEXT NOT NULL,
+    reason          TEXT NOT NULL,
+    -- what was counted within the window, and the limit it went over
+    observed        INT NOT NULL,
+    threshold       INT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT purchase_flags_reason_chk CHECK (reason IN ('ip_order_velocity', 'ip_buyer_velocity')),
+    CONSTRAINT purchase_flags_order_reason_uniq UNIQUE (order_id, reason),
+    CONSTRAINT purchase_flags_order_fk
+        FOREIGN KEY (order_id) REFERENCES orders(id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT purchase_flags_buyer_fk
+        FOREIGN KEY (buyer_id) REFERENCES users(id)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_flags_created ON purchase_flags(created_at DESC);
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS trg_audit_waitlist_entries ON waitlist_entries;
+DROP TRIGGER IF EXISTS trg_waitlist_entries_updated_at ON waitlist_entries;
+DROP INDEX IF EXISTS idx_waitlist_entries_user;
+DROP INDEX IF EXISTS idx_waitlist_entries_offered;
+DROP INDEX IF EXISTS idx_waitlist_entries_queue;
+DROP INDEX IF EXISTS uniq_waitlist_entries_open;
+DROP TABLE IF EXISTS waitlist_entries;
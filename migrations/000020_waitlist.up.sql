@@ -0,0 +1,46 @@
+-- Waitlists for sold-out ticket types. Entries are served strictly in (created_at, id) order:
+-- when tickets are freed, the first waiting entry is offered a ticket hold for its quantity and
+-- buys through the usual hold confirmation. While anyone is waiting, freed tickets are not
+-- sold to the public.
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    ticket_type_id   UUID NOT NULL,
+    user_id          UUID NOT NULL,
+    quantity         INT NOT NULL,
+    status           TEXT NOT NULL DEFAULT 'waiting',
+    -- the hold reserving the offered tickets; its expires_at is the offer deadline
+    hold_id          UUID,
+    offered_at       TIMESTAMPTZ,
+    offer_expires_at TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT waitlist_entries_quantity_chk CHECK (quantity > 0),
+    CONSTRAINT waitlist_entries_status_chk CHECK (status IN ('waiting', 'offered', 'purchased', 'expired', 'cancelled')),
+    CONSTRAINT waitlist_entries_type_fk
+        FOREIGN KEY (ticket_type_id) REFERENCES ticket_types(id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT waitlist_entries_user_fk
+        FOREIGN KEY (user_id) REFERENCES users(id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT waitlist_entries_hold_fk
+        FOREIGN KEY (hold_id) REFERENCES ticket_holds(id)
+        ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+-- A user has at most one open entry per ticket type.
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_waitlist_entries_open
+    ON waitlist_entries(ticket_type_id, user_id) WHERE status IN ('waiting', 'offered');
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_queue
+    ON waitlist_entries(ticket_type_id, created_at, id) WHERE status = 'waiting';
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_offered ON waitlist_entries(hold_id) WHERE status = 'offered';
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_user ON waitlist_entries(user_id, created_at DESC);
+
+DROP TRIGGER IF EXISTS trg_waitlist_entries_updated_at ON waitlist_entries;
+CREATE TRIGGER trg_waitlist_entries_updated_at
+BEFORE UPDATE ON waitlist_entries
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+DROP TRIGGER IF EXISTS trg_audit_waitlist_entries ON waitlist_entries;
+CREATE TRIGGER trg_audit_waitlist_entries
+AFTER INSERT OR UPDATE OR DELETE ON waitlist_entries
+FOR EACH ROW EXECUTE FUNCTION audit_trigger_func();
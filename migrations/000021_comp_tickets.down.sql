@@ -0,0 +1,34 @@
+DROP FUNCTION IF EXISTS get_sales_report(DATE, DATE);
+CREATE OR REPLACE FUNCTION get_sales_report(p_start DATE, p_end DATE)
+RETURNS TABLE(
+  event_id UUID,
+  event_title TEXT,
+  tickets_sold BIGINT,
+  revenue NUMERIC(14,2),
+  discounts NUMERIC(14,2),
+  unique_buyers BIGINT
+)
+LANGUAGE sql
+STABLE
+AS $$
+  SELECT
+    e.id,
+    e.title,
+    COUNT(t.id) FILTER (WHERE t.status IN ('paid','used')) AS tickets_sold,
+    COALESCE(SUM(t.amount_paid) FILTER (WHERE t.status IN ('paid','used')), 0)::NUMERIC(14,2) AS revenue,
+    COALESCE(SUM(t.discount_amount) FILTER (WHERE t.status IN ('paid','used')), 0)::NUMERIC(14,2) AS discounts,
+    COUNT(DISTINCT t.buyer_id) FILTER (WHERE t.status IN ('paid','used')) AS unique_buyers
+  FROM events e
+  LEFT JOIN ticket_types tt ON tt.event_id = e.id
+  LEFT JOIN tickets t ON t.ticket_type_id = tt.id
+               AND t.purchase_date >= p_start::timestamptz
+               AND t.purchase_date < (p_end + 1)::timestamptz
+  GROUP BY e.id, e.title
+  ORDER BY revenue DESC;
+$$;
+
+DROP INDEX IF EXISTS idx_tickets_comp;
+ALTER TABLE tickets
+    DROP CONSTRAINT IF EXISTS tickets_comp_chk,
+    DROP COLUMN IF EXISTS comp_kind,
+    DROP COLUMN IF EXISTS is_comp;
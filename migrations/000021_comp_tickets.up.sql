@@ -0,0 +1,43 @@
+-- Complimentary tickets issued by organizers. They take capacity like sold tickets but are
+-- free, have no order and are left out of sales figures.
+ALTER TABLE tickets
+    ADD COLUMN IF NOT EXISTS is_comp BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS comp_kind TEXT,
+    ADD CONSTRAINT tickets_comp_chk CHECK (
+        (is_comp AND comp_kind IN ('guest', 'press', 'staff') AND amount_paid = 0 AND order_id IS NULL)
+        OR (NOT is_comp AND comp_kind IS NULL));
+
+CREATE INDEX IF NOT EXISTS idx_tickets_comp ON tickets(ticket_type_id) WHERE is_comp;
+
+-- tickets_sold, revenue, discounts and unique_buyers count sold tickets only; comps are
+-- reported on their own.
+DROP FUNCTION IF EXISTS get_sales_report(DATE, DATE);
+CREATE OR REPLACE FUNCTION get_sales_report(p_start DATE, p_end DATE)
+RETURNS TABLE(
+  event_id UUID,
+  event_title TEXT,
+  tickets_sold BIGINT,
+  revenue NUMERIC(14,2),
+  discounts NUMERIC(14,2),
+  unique_buyers BIGINT,
+  comp_tickets BIGINT
+)
+LANGUAGE sql
+STABLE
+AS $$
+  SELECT
+    e.id,
+    e.title,
+    COUNT(t.id) FILTER (WHERE t.status IN ('paid','used') AND NOT t.is_comp) AS tickets_sold,
+    COALESCE(SUM(t.amount_paid) FILTER (WHERE t.status IN ('paid','used') AND NOT t.is_comp), 0)::NUMERIC(14,2) AS revenue,
+    COALESCE(SUM(t.discount_amount) FILTER (WHERE t.status IN ('paid','used') AND NOT t.is_comp), 0)::NUMERIC(14,2) AS discounts,
+    COUNT(DISTINCT t.buyer_id) FILTER (WHERE t.status IN ('paid','used') AND NOT t.is_comp) AS unique_buyers,
+    COUNT(t.id) FILTER (WHERE t.status IN ('paid','used') AND t.is_comp) AS comp_tickets
+  FROM events e
+  LEFT JOIN ticket_types tt ON tt.event_id = e.id
+  LEFT JOIN tickets t ON t.ticket_type_id = tt.id
+               AND t.purchase_date >= p_start::timestamptz
+               AND t.purchase_date < (p_end + 1)::timestamptz
+  GROUP BY e.id, e.title
+  ORDER BY revenue DESC;
+$$;
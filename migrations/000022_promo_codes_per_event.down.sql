@@ -0,0 +1,4 @@
+-- Fails while two events share a code; rename one of them first.
+DROP INDEX IF EXISTS uniq_promo_codes_event_code;
+CREATE INDEX IF NOT EXISTS idx_promo_codes_event ON promo_codes(event_id);
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_promo_codes_code ON promo_codes (UPPER(code));
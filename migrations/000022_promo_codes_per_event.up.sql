@@ -0,0 +1,5 @@
+-- Promo codes are only unique within their event, so organizers of different events can use
+-- the same code. The new index also covers lookups by event, which made the old one redundant.
+DROP INDEX IF EXISTS uniq_promo_codes_code;
+DROP INDEX IF EXISTS idx_promo_codes_event;
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_promo_codes_event_code ON promo_codes (event_id, UPPER(code));
@@ -0,0 +1,3 @@
+ALTER TABLE tickets
+    DROP CONSTRAINT IF EXISTS tickets_used_by_device_fk,
+    DROP COLUMN IF EXISTS used_by_device_id;
@@ -0,0 +1,17 @@
+-- The scanner device that first admitted a ticket; used_by stays NULL for device scans.
+ALTER TABLE tickets
+    ADD COLUMN IF NOT EXISTS used_by_device_id UUID,
+    ADD CONSTRAINT tickets_used_by_device_fk
+        FOREIGN KEY (used_by_device_id) REFERENCES checkin_devices(id)
+        ON UPDATE CASCADE ON DELETE SET NULL;
+
+-- Backfill from the first admission of tickets already scanned by a device.
+UPDATE tickets t
+SET used_by_device_id = c.device_id
+FROM (
+    SELECT DISTINCT ON (ticket_id) ticket_id, device_id
+    FROM checkins
+    WHERE result = 'admitted' AND ticket_id IS NOT NULL
+    ORDER BY ticket_id, scanned_at
+) c
+WHERE c.ticket_id = t.id AND t.used_by IS NULL AND c.device_id IS NOT NULL;
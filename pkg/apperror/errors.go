@@ -13,11 +13,37 @@ const (
 	CodeInternal      Code = "internal"
 	CodeInvalidState  Code = "invalid_state"
 	CodeUnavailable   Code = "unavailable"
+
+	// Purchase-specific conflicts, so clients can tell the buyer exactly why a sale failed.
+	CodeSoldOut            Code = "sold_out"
+	CodeSaleNotStarted     Code = "sale_not_started"
+	CodeSaleEnded          Code = "sale_ended"
+	CodeTicketTypeInactive Code = "ticket_type_inactive"
+	CodeEventNotOnSale     Code = "event_not_on_sale"
+	CodeHoldExpired        Code = "hold_expired"
+	CodePaymentFailed      Code = "payment_failed"
+	CodeTransferExpired    Code = "transfer_expired"
+	// CodePromoInvalid covers unknown, inactive, expired, used-up and inapplicable promo codes.
+	CodePromoInvalid Code = "promo_code_invalid"
+	// CodeRefundNotAllowed means the ticket type's refund policy rules the refund out.
+	CodeRefundNotAllowed Code = "refund_not_allowed"
+	// CodePurchaseLimit means the ticket type's per-order or per-buyer limit would be exceeded.
+	CodePurchaseLimit Code = "purchase_limit_exceeded"
+
+	// CodeInvalidQRCode covers forged, malformed and superseded ticket QR codes.
+	CodeInvalidQRCode Code = "invalid_qr_code"
+	CodeAlreadyUsed   Code = "already_used"
+	// CodeWrongEvent is returned when a genuine ticket is scanned at another event's entrance.
+	CodeWrongEvent Code = "wrong_event"
+	// CodeIdempotencyMismatch means an Idempotency-Key was reused for a different request.
+	CodeIdempotencyMismatch Code = "idempotency_mismatch"
 )
 
 type AppError struct {
 	Code    Code
 	Message string
+	// Details is optional structured context returned to clients (e.g. when a ticket was used).
+	Details map[string]any
 	Cause   error
 }
 
@@ -34,3 +60,7 @@ func New(code Code, msg string, cause error) *AppError {
 	return &AppError{Code: code, Message: msg, Cause: cause}
 }
 
+func (e *AppError) WithDetails(details map[string]any) *AppError {
+	e.Details = details
+	return e
+}